@@ -3,18 +3,49 @@ package main
 import (
 	"log"
 	"sirclo/project/capstone/config"
+	_jobs "sirclo/project/capstone/delivery/jobs"
+	_middlewares "sirclo/project/capstone/delivery/middleware"
 	_route "sirclo/project/capstone/delivery/routers"
 	"sirclo/project/capstone/util"
+	"time"
 
 	_assetController "sirclo/project/capstone/delivery/controllers/asset"
+	_attachmentController "sirclo/project/capstone/delivery/controllers/attachment"
+	_auditController "sirclo/project/capstone/delivery/controllers/audit"
 	_authController "sirclo/project/capstone/delivery/controllers/auth"
+	_commentController "sirclo/project/capstone/delivery/controllers/comment"
+	_delegationController "sirclo/project/capstone/delivery/controllers/delegation"
+	_kitController "sirclo/project/capstone/delivery/controllers/kit"
+	_locationController "sirclo/project/capstone/delivery/controllers/location"
+	_maintenanceController "sirclo/project/capstone/delivery/controllers/maintenance"
+	_notificationController "sirclo/project/capstone/delivery/controllers/notification"
+	_offboardingController "sirclo/project/capstone/delivery/controllers/offboarding"
+	_reportController "sirclo/project/capstone/delivery/controllers/report"
 	_requestController "sirclo/project/capstone/delivery/controllers/request"
+	_reservationController "sirclo/project/capstone/delivery/controllers/reservation"
+	_slaController "sirclo/project/capstone/delivery/controllers/sla"
 	_userController "sirclo/project/capstone/delivery/controllers/user"
+	_vendorController "sirclo/project/capstone/delivery/controllers/vendor"
+	_waitlistController "sirclo/project/capstone/delivery/controllers/waitlist"
 
 	_assetRepo "sirclo/project/capstone/repository/asset"
+	_attachmentRepo "sirclo/project/capstone/repository/attachment"
+	_auditRepo "sirclo/project/capstone/repository/audit"
 	_authRepo "sirclo/project/capstone/repository/auth"
+	_commentRepo "sirclo/project/capstone/repository/comment"
+	_delegationRepo "sirclo/project/capstone/repository/delegation"
+	_kitRepo "sirclo/project/capstone/repository/kit"
+	_locationRepo "sirclo/project/capstone/repository/location"
+	_maintenanceRepo "sirclo/project/capstone/repository/maintenance"
+	_notificationRepo "sirclo/project/capstone/repository/notification"
+	_offboardingRepo "sirclo/project/capstone/repository/offboarding"
+	_reportRepo "sirclo/project/capstone/repository/report"
 	_requestRepo "sirclo/project/capstone/repository/request"
+	_reservationRepo "sirclo/project/capstone/repository/reservation"
+	_slaRepo "sirclo/project/capstone/repository/sla"
 	_userRepo "sirclo/project/capstone/repository/user"
+	_vendorRepo "sirclo/project/capstone/repository/vendor"
+	_waitlistRepo "sirclo/project/capstone/repository/waitlist"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -35,19 +66,55 @@ func main() {
 	userRepo := _userRepo.NewUserRepo(db)
 	assetRepo := _assetRepo.NewAssetRepo(db)
 	requestRepo := _requestRepo.NewRequestRepo(db)
+	attachmentRepo := _attachmentRepo.NewAttachmentRepo(db)
+	vendorRepo := _vendorRepo.NewVendorRepo(db)
+	reportRepo := _reportRepo.NewReportRepo(db)
+	locationRepo := _locationRepo.NewLocationRepo(db)
+	maintenanceRepo := _maintenanceRepo.NewMaintenanceRepo(db)
+	auditRepo := _auditRepo.NewAuditRepo(db)
+	kitRepo := _kitRepo.NewKitRepo(db)
+	reservationRepo := _reservationRepo.NewReservationRepo(db)
+	waitlistRepo := _waitlistRepo.NewWaitlistRepo(db)
+	notificationRepo := _notificationRepo.NewNotificationRepo(db)
+	commentRepo := _commentRepo.NewCommentRepo(db)
+	delegationRepo := _delegationRepo.NewDelegationRepo(db)
+	slaRepo := _slaRepo.NewSlaRepo(db)
+	offboardingRepo := _offboardingRepo.NewOffboardingRepo(db)
 
 	// initialize controller
 	authController := _authController.NewAuthController(authRepo)
 	userController := _userController.NewUserController(userRepo)
 	assetController := _assetController.NewAssetController(assetRepo)
 	requestController := _requestController.NewRequestController(requestRepo)
+	attachmentController := _attachmentController.NewAttachmentController(attachmentRepo)
+	vendorController := _vendorController.NewVendorController(vendorRepo)
+	reportController := _reportController.NewReportController(reportRepo)
+	locationController := _locationController.NewLocationController(locationRepo)
+	maintenanceController := _maintenanceController.NewMaintenanceController(maintenanceRepo)
+	auditController := _auditController.NewAuditController(auditRepo)
+	kitController := _kitController.NewKitController(kitRepo)
+	reservationController := _reservationController.NewReservationController(reservationRepo)
+	waitlistController := _waitlistController.NewWaitlistController(waitlistRepo)
+	notificationController := _notificationController.NewNotificationController(notificationRepo)
+	commentController := _commentController.NewCommentController(commentRepo)
+	delegationController := _delegationController.NewDelegationController(delegationRepo)
+	slaController := _slaController.NewSlaController(slaRepo)
+	offboardingController := _offboardingController.NewOffboardingController(offboardingRepo)
+
+	// background jobs
+	_jobs.RunEvery("cleanup uploads", 30*time.Minute, _jobs.CleanupUploads(attachmentRepo))
+	_jobs.RunEvery("process uploads", time.Minute, _jobs.ProcessUploads(attachmentRepo))
+	_jobs.RunEvery("start reservations", 5*time.Minute, _jobs.StartReservations(reservationRepo))
+	_jobs.RunEvery("expire waitlist holds", 15*time.Minute, _jobs.ExpireWaitlistHolds(waitlistRepo))
+	_jobs.RunEvery("escalate requests", 15*time.Minute, _jobs.EscalateRequests(slaRepo))
 
 	// create new echo
 	e := echo.New()
 
 	e.Pre(middleware.RemoveTrailingSlash(), middleware.CORS())
+	e.Use(_middlewares.ActiveUser(authRepo.IsActive))
 
-	_route.RegisterPath(e, authController, userController, assetController, requestController)
+	_route.RegisterPath(e, authController, userController, assetController, requestController, attachmentController, vendorController, reportController, locationController, maintenanceController, auditController, kitController, reservationController, waitlistController, notificationController, commentController, delegationController, slaController, offboardingController)
 
 	// start the server, and log if it fails
 	e.Logger.Fatal(e.Start(":80"))
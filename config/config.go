@@ -22,6 +22,9 @@ type AppConfig struct {
 		KeyID      string
 		AccessKey  string
 		BucketName string
+		// Endpoint and ForcePathStyle allow any S3 compatible store (e.g. MinIO) to be used
+		Endpoint       string
+		ForcePathStyle bool
 	}
 }
 
@@ -52,6 +55,8 @@ func initConfig() *AppConfig {
 	defaultConfig.S3Config.KeyID = os.Getenv("S3_KEY_ID")
 	defaultConfig.S3Config.AccessKey = os.Getenv("S3_ACCESS_KEY")
 	defaultConfig.S3Config.BucketName = os.Getenv("S3_BUCKET_NAME")
+	defaultConfig.S3Config.Endpoint = os.Getenv("S3_ENDPOINT")
+	defaultConfig.S3Config.ForcePathStyle = os.Getenv("S3_FORCE_PATH_STYLE") == "true"
 
 	return &defaultConfig
 }
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"sirclo/project/capstone/entities"
 	"sirclo/project/capstone/util"
+	"sirclo/project/capstone/util/pagination"
 
 	response "sirclo/project/capstone/delivery/common"
 	middlewares "sirclo/project/capstone/delivery/middleware"
@@ -17,6 +19,61 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// pixel size of a single QR code label image
+	labelImageSize = 300
+	// a label sheet holds 24 labels, 10 pages at most
+	maxLabels = 240
+)
+
+// lifecycle status: ordered -> received -> in_service -> retired -> disposed || sold
+var lifecycleTransitions = map[string][]string{
+	"ordered":    {"received"},
+	"received":   {"in_service"},
+	"in_service": {"retired"},
+	"retired":    {"disposed", "sold"},
+}
+
+// sorts of the asset list
+var assetSorts = []string{"name", "avail", "created_at"}
+
+// disposal method allowed when the asset leaves the company
+var disposalMethods = map[string][]string{
+	"disposed": {"recycle", "scrap", "donation"},
+	"sold":     {"sale", "trade_in"},
+}
+
+// checkDate validates optional dates, formatted as yyyy-mm-dd
+func checkDate(dates ...string) error {
+	for _, date := range dates {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("date must be formatted as yyyy-mm-dd")
+		}
+	}
+	return nil
+}
+
+// parseDays parses a duration in days such as 30d (or 30)
+func parseDays(value string) (int, error) {
+	days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+	if err != nil || days <= 0 {
+		return 0, fmt.Errorf("invalid days")
+	}
+	return days, nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 type AssetController struct {
 	repository assetRepo.AssetRepo
 }
@@ -43,10 +100,13 @@ func (ac AssetController) CreateAssetController() echo.HandlerFunc {
 
 		//bind data photo
 		// Multipart form
-		var urlPhoto string
+		var urlPhoto util.PhotoURLs
 		form, err := c.MultipartForm()
 		if err == nil {
 			files := form.File["photo"]
+			if len(files) > 1 {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "only one photo allowed, upload more photos as attachments"))
+			}
 
 			for _, file := range files {
 				// Source
@@ -57,32 +117,57 @@ func (ac AssetController) CreateAssetController() echo.HandlerFunc {
 				}
 				defer src.Close()
 
-				fileExtension := filepath.Ext(file.Filename)
-				err = util.CheckExtension(fileExtension)
-				if err != nil {
-					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to join, photo format not allowed"))
-				}
-
 				fileSize := file.Size
 				err = util.CheckSize(fileSize)
 				if err != nil {
 					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to join, photo size too big"))
 				}
 
-				fileName := "assets_pic/" + strconv.Itoa(userRequest.Id_category) + "_" + userRequest.Name + fileExtension
-				urlPhoto, err = util.UploadToS3(&src, fileName)
+				// validate the real content of the file, not its name
+				image, err := util.ProcessImage(src)
+				if err != nil {
+					log.Println(err)
+					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to join, photo format not allowed"))
+				}
+
+				fileName := "assets_pic/" + strconv.Itoa(userRequest.Id_category) + "_" + userRequest.Name
+				urlPhoto, err = util.UploadPhotoToS3(image, fileName)
 				if err != nil {
 					return err
 				}
 			}
 		}
 
+		// new asset can be registered while still ordered, in service by default
+		if userRequest.Lifecycle_status == "" {
+			userRequest.Lifecycle_status = "in_service"
+		}
+		if !contains([]string{"ordered", "received", "in_service"}, userRequest.Lifecycle_status) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "lifecycle_status must be ordered || received || in_service"))
+		}
+
+		if err := checkDate(userRequest.Purchase_date, userRequest.Warranty_expiry); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		if userRequest.Purchase_price < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "purchase_price cannot be negative"))
+		}
+
 		asset := entities.Asset{
+			Lifecycle_status: userRequest.Lifecycle_status,
+			Id_vendor:        userRequest.Id_vendor,
+			Purchase_date:    userRequest.Purchase_date,
+			Purchase_price:   userRequest.Purchase_price,
+			Invoice_number:   userRequest.Invoice_number,
+			Warranty_expiry:  userRequest.Warranty_expiry,
+			Id_location:      userRequest.Id_location,
 			Name:             userRequest.Name,
 			Description:      userRequest.Description,
 			Initial_quantity: userRequest.Initial_quantity,
 			Avail_quantity:   userRequest.Initial_quantity,
-			Photo:            urlPhoto,
+			Photo:            urlPhoto.Original,
+			Photo_medium:     urlPhoto.Medium,
+			Photo_thumbnail:  urlPhoto.Thumbnail,
 			Id_category:      userRequest.Id_category,
 		}
 
@@ -100,40 +185,84 @@ func (ac AssetController) CreateAssetController() echo.HandlerFunc {
 // 2. get all user controller
 func (ac AssetController) GetAssetsController() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		category := c.QueryParam("category")
-		maintenance := c.QueryParam("maintenance")
-		avail := c.QueryParam("avail")
+		filter := entities.AssetFilter{
+			Search:      c.QueryParam("search"),
+			Category:    c.QueryParam("category"),
+			Maintenance: c.QueryParam("maintenance"),
+			Avail:       c.QueryParam("avail"),
+			Lifecycle:   c.QueryParam("lifecycle"),
+			Sort:        c.QueryParam("sort"),
+			Order:       c.QueryParam("order"),
+		}
 
-		limitStr := c.QueryParam("limit")
-		offsetStr := c.QueryParam("offset")
+		if filter.Maintenance != "" && filter.Maintenance != "yes" && filter.Maintenance != "no" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "maintenance must be yes || no"))
+		}
+		if filter.Sort != "" && !contains(assetSorts, filter.Sort) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "sort must be name || avail || created_at"))
+		}
+		if filter.Order != "" && filter.Order != "asc" && filter.Order != "desc" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "order must be asc || desc"))
+		}
 
-		limit, err := strconv.Atoi(limitStr)
-		if err != nil {
-			limit = 0
+		if warranty := c.QueryParam("warranty_expiring_within"); warranty != "" {
+			days, err := parseDays(warranty)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "warranty_expiring_within must be formatted as 30d"))
+			}
+			filter.Warranty_days = days
 		}
 
-		offset, err := strconv.Atoi(offsetStr)
+		if locationStr := c.QueryParam("location"); locationStr != "" {
+			idLocation, err := strconv.Atoi(locationStr)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert location"))
+			}
+			filter.Location = idLocation
+		}
+
+		// range of the available quantity
+		if minStr := c.QueryParam("min_quantity"); minStr != "" {
+			minQuantity, err := strconv.Atoi(minStr)
+			if err != nil || minQuantity < 0 {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "min_quantity must be a number not below 0"))
+			}
+			filter.Min_quantity = minQuantity
+		}
+		if maxStr := c.QueryParam("max_quantity"); maxStr != "" {
+			maxQuantity, err := strconv.Atoi(maxStr)
+			if err != nil || maxQuantity < 0 {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "max_quantity must be a number not below 0"))
+			}
+			filter.Max_quantity = maxQuantity
+			filter.Has_max = true
+		}
+		if filter.Has_max && filter.Min_quantity > filter.Max_quantity {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "min_quantity must not be above max_quantity"))
+		}
+
+		page, err := pagination.Parse(c.QueryParams())
 		if err != nil {
-			offset = 0
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
 		}
+		if page.Keyset {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "cursor is only supported on the request activity and history"))
+		}
+		filter.Limit = page.Limit
+		filter.Offset = page.Offset
 
-		assets, err := ac.repository.Get(category, maintenance, avail, limit, offset)
+		assets, err := ac.repository.Get(filter)
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
 		}
 
-		totalPage := 0
-		if limit > 0 {
-			assetsforTotPage, _ := ac.repository.Get(category, maintenance, avail, 0, 0)
-			if len(assetsforTotPage)%limit == 0 {
-				totalPage = (len(assetsforTotPage) / limit)
-			} else {
-				totalPage = (len(assetsforTotPage) / limit) + 1
-			}
+		total, err := ac.repository.Count(filter)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
 		}
-		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all assets", map[string]interface{}{
-			"totalPage": totalPage,
-			"data":      assets,
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all assets", pagination.List{
+			Meta: pagination.NewMeta(total, page),
+			Data: assets,
 		}))
 	}
 }
@@ -184,6 +313,13 @@ func (ac AssetController) UpdateAssetController() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
 		}
 
+		if err := checkDate(asset.Purchase_date, asset.Warranty_expiry); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		if asset.Purchase_price < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "purchase_price cannot be negative"))
+		}
+
 		//asset existed
 		assetExisted, err := ac.repository.GetById(idAsset)
 		if err != nil {
@@ -193,7 +329,7 @@ func (ac AssetController) UpdateAssetController() echo.HandlerFunc {
 
 		//bind data photo
 		// Multipart form
-		var urlPhoto string
+		var urlPhoto util.PhotoURLs
 		form, err := c.MultipartForm()
 		name := asset.Name
 		if name == "" {
@@ -201,6 +337,9 @@ func (ac AssetController) UpdateAssetController() echo.HandlerFunc {
 		}
 		if err == nil {
 			files := form.File["photo"]
+			if len(files) > 1 {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "only one photo allowed, upload more photos as attachments"))
+			}
 
 			for _, file := range files {
 				// Source
@@ -211,27 +350,30 @@ func (ac AssetController) UpdateAssetController() echo.HandlerFunc {
 				}
 				defer src.Close()
 
-				fileExtension := filepath.Ext(file.Filename)
-				err = util.CheckExtension(fileExtension)
-				if err != nil {
-					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to join, photo format not allowed"))
-				}
-
 				fileSize := file.Size
 				err = util.CheckSize(fileSize)
 				if err != nil {
 					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to join, photo size too big"))
 				}
 
-				fileName := "assets_pic/" + strconv.Itoa(asset.Id_category) + "_" + name + fileExtension
-				urlPhoto, err = util.UploadToS3(&src, fileName)
+				// validate the real content of the file, not its name
+				image, err := util.ProcessImage(src)
+				if err != nil {
+					log.Println(err)
+					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to join, photo format not allowed"))
+				}
+
+				fileName := "assets_pic/" + strconv.Itoa(asset.Id_category) + "_" + name
+				urlPhoto, err = util.UploadPhotoToS3(image, fileName)
 				if err != nil {
 					return err
 				}
 			}
 		}
-		if urlPhoto != "" {
-			asset.Photo = urlPhoto
+		if urlPhoto.Original != "" {
+			asset.Photo = urlPhoto.Original
+			asset.Photo_medium = urlPhoto.Medium
+			asset.Photo_thumbnail = urlPhoto.Thumbnail
 		}
 
 		// update user based on id to database
@@ -334,3 +476,256 @@ func (ac AssetController) GetCategoriesController() echo.HandlerFunc {
 		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get categories", categories))
 	}
 }
+
+// 8. update lifecycle status of an asset
+func (ac AssetController) UpdateLifecycleController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		// get id from param
+		idAsset, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var lifecycleReq LifecycleRequestFormat
+		if err := c.Bind(&lifecycleReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if lifecycleReq.Date == "" {
+			lifecycleReq.Date = time.Now().Format("2006-01-02")
+		}
+		if _, err := time.Parse("2006-01-02", lifecycleReq.Date); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "date must be formatted as yyyy-mm-dd"))
+		}
+
+		assetExisted, err := ac.repository.GetById(idAsset)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, response.NotFound("failed", "asset not found"))
+		}
+
+		if !contains(lifecycleTransitions[assetExisted.Lifecycle_status], lifecycleReq.Status) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "cannot change lifecycle status from "+assetExisted.Lifecycle_status+" to "+lifecycleReq.Status))
+		}
+
+		if (lifecycleReq.Status == "retired" || lifecycleReq.Status == "disposed" || lifecycleReq.Status == "sold") && lifecycleReq.Reason == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "reason is required"))
+		}
+
+		event := entities.AssetLifecycleEvent{
+			Id_asset:    idAsset,
+			Id_user:     idUser,
+			From_status: assetExisted.Lifecycle_status,
+			To_status:   lifecycleReq.Status,
+			Reason:      lifecycleReq.Reason,
+			Event_date:  lifecycleReq.Date,
+		}
+
+		var disposal *entities.AssetDisposal
+		if methods, ok := disposalMethods[lifecycleReq.Status]; ok {
+			if !contains(methods, lifecycleReq.Disposal_method) {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "disposal_method must be "+strings.Join(methods, " || ")))
+			}
+			if lifecycleReq.Recovered_value < 0 {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "recovered_value cannot be negative"))
+			}
+
+			disposal = &entities.AssetDisposal{
+				Id_asset:        idAsset,
+				Id_user:         idUser,
+				Method:          lifecycleReq.Disposal_method,
+				Recovered_value: lifecycleReq.Recovered_value,
+				Disposal_date:   lifecycleReq.Date,
+				Notes:           lifecycleReq.Reason,
+			}
+		}
+
+		if err := ac.repository.UpdateLifecycle(event, disposal); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update lifecycle status"))
+	}
+}
+
+// 9. get lifecycle history of an asset
+func (ac AssetController) GetLifecycleController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idAsset, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		lifecycle, err := ac.repository.GetLifecycle(idAsset)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get lifecycle", lifecycle))
+	}
+}
+
+// 10. update depreciation policy of a category
+func (ac AssetController) UpdateCategoryDepreciationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idCategory, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var depreciationReq DepreciationRequestFormat
+		if err := c.Bind(&depreciationReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if depreciationReq.Method != "straight_line" && depreciationReq.Method != "declining_balance" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "method must be straight_line || declining_balance"))
+		}
+		if depreciationReq.Useful_life_months <= 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "useful_life_months must be greater than 0"))
+		}
+		if depreciationReq.Salvage_rate < 0 || depreciationReq.Salvage_rate >= 1 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "salvage_rate must be between 0 and 1"))
+		}
+		if depreciationReq.Declining_factor == 0 {
+			depreciationReq.Declining_factor = 2
+		}
+		if depreciationReq.Declining_factor < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "declining_factor cannot be negative"))
+		}
+
+		category := entities.Categories{
+			Id:                  idCategory,
+			Depreciation_method: depreciationReq.Method,
+			Useful_life_months:  depreciationReq.Useful_life_months,
+			Salvage_rate:        depreciationReq.Salvage_rate,
+			Declining_factor:    depreciationReq.Declining_factor,
+		}
+
+		if err := ac.repository.UpdateCategoryDepreciation(category); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed update data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update depreciation"))
+	}
+}
+
+// 11. resolve a scanned asset tag to the asset detail
+func (ac AssetController) LookupAssetController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		tag := strings.TrimSpace(c.QueryParam("tag"))
+		if tag == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "tag is required"))
+		}
+
+		asset, err := ac.repository.GetByTag(tag)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, response.NotFound("failed", "asset not found"))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get asset", asset))
+	}
+}
+
+// 12. QR code or Code128 barcode image of an asset tag
+func (ac AssetController) GetAssetLabelController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idAsset, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		asset, err := ac.repository.GetById(idAsset)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+
+		var label []byte
+		switch c.QueryParam("format") {
+		case "", "qr":
+			label, err = util.QRCode(asset.Tag, labelImageSize)
+		case "code128":
+			label, err = util.Code128(asset.Tag, labelImageSize*2, labelImageSize/2)
+		default:
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "format must be qr || code128"))
+		}
+		if err != nil {
+			log.Println(err)
+			return c.JSON(http.StatusInternalServerError, response.InternalServerError("failed", "failed to generate label"))
+		}
+
+		return c.Blob(http.StatusOK, "image/png", label)
+	}
+}
+
+// 13. printable PDF sheet of labels for a selection of assets
+func (ac AssetController) PrintLabelsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		var labelReq LabelRequestFormat
+		if err := c.Bind(&labelReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if len(labelReq.Ids) == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "ids is required"))
+		}
+		if len(labelReq.Ids) > maxLabels {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", fmt.Sprintf("maximum %d labels per sheet", maxLabels)))
+		}
+
+		var labels []util.AssetLabel
+		for _, idAsset := range labelReq.Ids {
+			asset, err := ac.repository.GetById(idAsset)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", fmt.Sprintf("asset %d not found", idAsset)))
+			}
+			labels = append(labels, util.AssetLabel{Tag: asset.Tag, Name: asset.Name})
+		}
+
+		sheet, err := util.LabelSheet(labels)
+		if err != nil {
+			log.Println(err)
+			return c.JSON(http.StatusInternalServerError, response.InternalServerError("failed", "failed to generate label"))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="asset-labels.pdf"`)
+		return c.Blob(http.StatusOK, "application/pdf", sheet)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	middlewares "sirclo/project/capstone/delivery/middleware"
 	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -1122,6 +1123,460 @@ func TestGetCategories(t *testing.T) {
 
 }
 
+// 8. test update lifecycle
+func TestUpdateLifecycle(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		idAsset string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "1", map[string]interface{}{"status": "retired"}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", map[string]interface{}{"status": "retired"}, http.StatusBadRequest, "failed to convert id"},
+		{"invalid date", 1, "1", map[string]interface{}{"status": "retired", "reason": "broken", "date": "01-02-2022"}, http.StatusBadRequest, "date must be formatted as yyyy-mm-dd"},
+		{"asset not found", 1, "100", map[string]interface{}{"status": "retired", "reason": "broken"}, http.StatusNotFound, "asset not found"},
+		{"invalid transition", 1, "1", map[string]interface{}{"status": "sold"}, http.StatusBadRequest, "cannot change lifecycle status from in_service to sold"},
+		{"reason is required", 1, "1", map[string]interface{}{"status": "retired"}, http.StatusBadRequest, "reason is required"},
+		{"invalid disposal method", 1, "2", map[string]interface{}{"status": "sold", "reason": "old", "disposal_method": "scrap"}, http.StatusBadRequest, "disposal_method must be sale || trade_in"},
+		{"negative recovered value", 1, "2", map[string]interface{}{"status": "sold", "reason": "old", "disposal_method": "sale", "recovered_value": -1}, http.StatusBadRequest, "recovered_value cannot be negative"},
+		{"asset still on loan", 1, "3", map[string]interface{}{"status": "retired", "reason": "broken"}, http.StatusBadRequest, "asset is still on loan"},
+		{"success retire asset", 1, "1", map[string]interface{}{"status": "retired", "reason": "broken", "date": "2022-03-01"}, http.StatusOK, "success update lifecycle status"},
+		{"success sell asset", 1, "2", map[string]interface{}{"status": "sold", "reason": "old", "disposal_method": "sale", "recovered_value": 1500000}, http.StatusOK, "success update lifecycle status"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/:id/lifecycle")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idAsset)
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.UpdateLifecycleController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 9. test get lifecycle
+func TestGetLifecycle(t *testing.T) {
+	t.Run("failed to fetch data", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/lifecycle")
+		context.SetParamNames("id")
+		context.SetParamValues("100")
+
+		reqController := NewAssetController(mockAssetRepository{})
+
+		type Responses struct {
+			Code    int    `json:"code"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		}
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(reqController.GetLifecycleController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "failed to fetch data", response.Message)
+		}
+	})
+	t.Run("success get lifecycle", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/lifecycle")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		reqController := NewAssetController(mockAssetRepository{})
+
+		type Responses struct {
+			Code    int    `json:"code"`
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		}
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(reqController.GetLifecycleController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, "success get lifecycle", response.Message)
+		}
+	})
+}
+
+// 10. test get assets with warranty filter
+func TestGetAssetsWarrantyExpiring(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   string
+		code    int
+		message string
+	}{
+		{"invalid warranty filter", "/?warranty_expiring_within=month", http.StatusBadRequest, "warranty_expiring_within must be formatted as 30d"},
+		{"success with days suffix", "/?warranty_expiring_within=30d", http.StatusOK, "success get all assets"},
+		{"success without days suffix", "/?warranty_expiring_within=30", http.StatusOK, "success get all assets"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets")
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, reqController.GetAssetsController()(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 11. test update category depreciation
+func TestUpdateCategoryDepreciation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		idRole     int
+		idCategory string
+		body       map[string]interface{}
+		code       int
+		message    string
+	}{
+		{"unauthorized access", 3, "1", map[string]interface{}{"method": "straight_line"}, http.StatusUnauthorized, "unauthorized access"},
+		{"invalid method", 1, "1", map[string]interface{}{"method": "sum_of_years", "useful_life_months": 36}, http.StatusBadRequest, "method must be straight_line || declining_balance"},
+		{"invalid useful life", 1, "1", map[string]interface{}{"method": "straight_line"}, http.StatusBadRequest, "useful_life_months must be greater than 0"},
+		{"invalid salvage rate", 1, "1", map[string]interface{}{"method": "straight_line", "useful_life_months": 36, "salvage_rate": 1.5}, http.StatusBadRequest, "salvage_rate must be between 0 and 1"},
+		{"category not found", 1, "100", map[string]interface{}{"method": "straight_line", "useful_life_months": 36}, http.StatusBadRequest, "failed update data"},
+		{"success update depreciation", 1, "1", map[string]interface{}{"method": "declining_balance", "useful_life_months": 36, "salvage_rate": 0.1}, http.StatusOK, "success update depreciation"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/categories/:id/depreciation")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idCategory)
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.UpdateCategoryDepreciationController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 12. test get assets by location
+func TestGetAssetsByLocation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   string
+		code    int
+		message string
+	}{
+		{"invalid location filter", "/?location=hq", http.StatusBadRequest, "failed to convert location"},
+		{"failed to fetch data", "/?location=100", http.StatusBadRequest, "failed to fetch data"},
+		{"success filter by location", "/?location=1", http.StatusOK, "success get all assets"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets")
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, reqController.GetAssetsController()(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 13. test lookup asset by scanned tag
+func TestLookupAsset(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   string
+		code    int
+		message string
+	}{
+		{"tag is required", "/", http.StatusBadRequest, "tag is required"},
+		{"asset not found", "/?tag=AST-999999", http.StatusNotFound, "asset not found"},
+		{"success lookup asset", "/?tag=AST-000001", http.StatusOK, "success get asset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/lookup")
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			type Responses struct {
+				Code    int            `json:"code"`
+				Status  string         `json:"status"`
+				Message string         `json:"message"`
+				Data    entities.Asset `json:"data"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.LookupAssetController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				if tc.code == http.StatusOK {
+					assert.Equal(t, 1, response.Data.Id)
+				}
+			}
+		})
+	}
+}
+
+// 14. test QR code and barcode image of an asset
+func TestGetAssetLabel(t *testing.T) {
+	testCases := []struct {
+		name        string
+		idRole      int
+		idAsset     string
+		query       string
+		code        int
+		contentType string
+	}{
+		{"unauthorized access", 2, "1", "/", http.StatusUnauthorized, echo.MIMEApplicationJSONCharsetUTF8},
+		{"asset not found", 1, "100", "/", http.StatusBadRequest, echo.MIMEApplicationJSONCharsetUTF8},
+		{"invalid format", 1, "1", "/?format=ean13", http.StatusBadRequest, echo.MIMEApplicationJSONCharsetUTF8},
+		{"success qr code", 1, "1", "/", http.StatusOK, "image/png"},
+		{"success code128", 1, "1", "/?format=code128", http.StatusOK, "image/png"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/:id/label")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idAsset)
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.GetAssetLabelController())(context)) {
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.contentType, res.Header().Get(echo.HeaderContentType))
+				if tc.code == http.StatusOK {
+					assert.True(t, bytes.HasPrefix(res.Body.Bytes(), []byte("\x89PNG")))
+				}
+			}
+		})
+	}
+}
+
+// 15. test printable label sheet
+func TestPrintLabels(t *testing.T) {
+	tooMany := make([]int, maxLabels+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, map[string]interface{}{"ids": []int{1}}, http.StatusUnauthorized, "unauthorized access"},
+		{"ids is required", 1, map[string]interface{}{"ids": []int{}}, http.StatusBadRequest, "ids is required"},
+		{"too many labels", 1, map[string]interface{}{"ids": tooMany}, http.StatusBadRequest, "maximum 240 labels per sheet"},
+		{"asset not found", 1, map[string]interface{}{"ids": []int{1, 100}}, http.StatusBadRequest, "asset 100 not found"},
+		{"success print labels", 1, map[string]interface{}{"ids": []int{1, 2, 3}}, http.StatusOK, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/labels")
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.PrintLabelsController())(context)) {
+				assert.Equal(t, tc.code, res.Code)
+				if tc.code == http.StatusOK {
+					assert.Equal(t, "application/pdf", res.Header().Get(echo.HeaderContentType))
+					assert.True(t, bytes.HasPrefix(res.Body.Bytes(), []byte("%PDF")))
+					return
+				}
+
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 16. test search, filter and sort assets
+func TestSearchAssets(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		code      int
+		message   string
+		totalPage int
+	}{
+		{"invalid maintenance filter", "/?maintenance=maybe", http.StatusBadRequest, "maintenance must be yes || no", 0},
+		{"invalid sort", "/?sort=price", http.StatusBadRequest, "sort must be name || avail || created_at", 0},
+		{"invalid order", "/?sort=name&order=up", http.StatusBadRequest, "order must be asc || desc", 0},
+		{"invalid min quantity", "/?min_quantity=-1", http.StatusBadRequest, "min_quantity must be a number not below 0", 0},
+		{"invalid max quantity", "/?max_quantity=many", http.StatusBadRequest, "max_quantity must be a number not below 0", 0},
+		{"min above max", "/?min_quantity=5&max_quantity=2", http.StatusBadRequest, "min_quantity must not be above max_quantity", 0},
+		{"min above zero max", "/?min_quantity=1&max_quantity=0", http.StatusBadRequest, "min_quantity must not be above max_quantity", 0},
+		{"zero max quantity", "/?max_quantity=0&limit=2", http.StatusOK, "success get all assets", 1},
+		{"failed to count", "/?search=count", http.StatusBadRequest, "failed to fetch data", 0},
+		{"success search", "/?search=laptop&category=Elektronik&maintenance=no&min_quantity=1&max_quantity=5&sort=avail&order=desc&limit=2", http.StatusOK, "success get all assets", 2},
+		{"success without limit", "/?search=laptop", http.StatusOK, "success get all assets", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets")
+
+			reqController := NewAssetController(mockAssetRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+				Data    struct {
+					Total     int `json:"total_items"`
+					TotalPage int `json:"total_pages"`
+				} `json:"data"`
+			}
+
+			if assert.NoError(t, reqController.GetAssetsController()(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				assert.Equal(t, tc.totalPage, response.Data.TotalPage)
+			}
+		})
+	}
+}
+
 type mockAssetRepository struct{}
 
 func (m mockAssetRepository) Create(asset entities.Asset) error {
@@ -1131,18 +1586,39 @@ func (m mockAssetRepository) Create(asset entities.Asset) error {
 	return nil
 }
 
-func (m mockAssetRepository) Get(category, maintenance, avail string, limit, offset int) ([]entities.Asset, error) {
-	if category == "100" {
+func (m mockAssetRepository) Get(filter entities.AssetFilter) ([]entities.Asset, error) {
+	if filter.Category == "100" || filter.Location == 100 {
 		return nil, fmt.Errorf("error")
 	}
 	return nil, nil
 }
 
+func (m mockAssetRepository) Count(filter entities.AssetFilter) (int, error) {
+	if filter.Search == "count" {
+		return 0, fmt.Errorf("error")
+	}
+	// a single asset is out of stock
+	if filter.Has_max && filter.Max_quantity == 0 {
+		return 1, nil
+	}
+	return 3, nil
+}
+
 func (m mockAssetRepository) GetById(id int) (entities.Asset, error) {
 	if id == 100 {
 		return entities.Asset{}, fmt.Errorf("error")
 	}
-	return entities.Asset{}, nil
+	if id == 2 {
+		return entities.Asset{Id: id, Tag: util.AssetTag(id), Name: "projector", Lifecycle_status: "retired"}, nil
+	}
+	return entities.Asset{Id: id, Tag: util.AssetTag(id), Name: "laptop", Lifecycle_status: "in_service"}, nil
+}
+
+func (m mockAssetRepository) GetByTag(tag string) (entities.Asset, error) {
+	if tag != util.AssetTag(1) {
+		return entities.Asset{}, fmt.Errorf("sql: no rows in result set")
+	}
+	return entities.Asset{Id: 1, Tag: tag, Name: "laptop", Lifecycle_status: "in_service"}, nil
 }
 
 func (m mockAssetRepository) Update(assetExisted, asset entities.Asset, id int) error {
@@ -1174,6 +1650,27 @@ func (m mockAssetRepository) GetCategory() ([]entities.Categories, error) {
 	return nil, nil
 }
 
+func (m mockAssetRepository) UpdateLifecycle(event entities.AssetLifecycleEvent, disposal *entities.AssetDisposal) error {
+	if event.Id_asset == 3 {
+		return fmt.Errorf("asset is still on loan")
+	}
+	return nil
+}
+
+func (m mockAssetRepository) UpdateCategoryDepreciation(category entities.Categories) error {
+	if category.Id == 100 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
+
+func (m mockAssetRepository) GetLifecycle(id int) (entities.AssetLifecycle, error) {
+	if id == 100 {
+		return entities.AssetLifecycle{}, fmt.Errorf("error")
+	}
+	return entities.AssetLifecycle{Id_asset: id, Lifecycle_status: "in_service"}, nil
+}
+
 type mockErrorAssetRepository struct{}
 
 func (m mockErrorAssetRepository) Create(asset entities.Asset) error {
@@ -1183,13 +1680,17 @@ func (m mockErrorAssetRepository) Create(asset entities.Asset) error {
 	return nil
 }
 
-func (m mockErrorAssetRepository) Get(category, maintenance, avail string, limit, offset int) ([]entities.Asset, error) {
-	if category == "100" {
+func (m mockErrorAssetRepository) Get(filter entities.AssetFilter) ([]entities.Asset, error) {
+	if filter.Category == "100" {
 		return nil, fmt.Errorf("error")
 	}
 	return nil, nil
 }
 
+func (m mockErrorAssetRepository) Count(filter entities.AssetFilter) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+
 func (m mockErrorAssetRepository) GetById(id int) (entities.Asset, error) {
 	if id == 100 {
 		return entities.Asset{}, fmt.Errorf("error")
@@ -1197,6 +1698,10 @@ func (m mockErrorAssetRepository) GetById(id int) (entities.Asset, error) {
 	return entities.Asset{}, nil
 }
 
+func (m mockErrorAssetRepository) GetByTag(tag string) (entities.Asset, error) {
+	return entities.Asset{}, fmt.Errorf("error")
+}
+
 func (m mockErrorAssetRepository) Update(assetExisted, asset entities.Asset, id int) error {
 	if asset.Id_category == 100 {
 		return fmt.Errorf("errors")
@@ -1222,3 +1727,15 @@ func (m mockErrorAssetRepository) GetHistoryUsage(int, int, int) (entities.Histo
 func (m mockErrorAssetRepository) GetCategory() ([]entities.Categories, error) {
 	return nil, fmt.Errorf("error")
 }
+
+func (m mockErrorAssetRepository) UpdateLifecycle(entities.AssetLifecycleEvent, *entities.AssetDisposal) error {
+	return fmt.Errorf("error")
+}
+
+func (m mockErrorAssetRepository) GetLifecycle(int) (entities.AssetLifecycle, error) {
+	return entities.AssetLifecycle{}, fmt.Errorf("error")
+}
+
+func (m mockErrorAssetRepository) UpdateCategoryDepreciation(entities.Categories) error {
+	return fmt.Errorf("error")
+}
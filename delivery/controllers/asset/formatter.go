@@ -1,10 +1,36 @@
 package asset
 
 type UserRequestFormat struct {
-	Id_category      int    `json:"id_category" form:"id_category"`
-	Is_maintenence   bool   `json:"is_maintenence" form:"is_maintenence"`
-	Name             string `json:"name" form:"name"`
-	Description      string `json:"description" form:"description"`
-	Initial_quantity int    `json:"initial_quantity" form:"initial_quantity"`
-	Photo            string `json:"photo" form:"photo"`
+	Id_category      int     `json:"id_category" form:"id_category"`
+	Is_maintenence   bool    `json:"is_maintenence" form:"is_maintenence"`
+	Name             string  `json:"name" form:"name"`
+	Description      string  `json:"description" form:"description"`
+	Initial_quantity int     `json:"initial_quantity" form:"initial_quantity"`
+	Photo            string  `json:"photo" form:"photo"`
+	Lifecycle_status string  `json:"lifecycle_status" form:"lifecycle_status"`
+	Id_vendor        int     `json:"id_vendor" form:"id_vendor"`
+	Purchase_date    string  `json:"purchase_date" form:"purchase_date"`
+	Purchase_price   float64 `json:"purchase_price" form:"purchase_price"`
+	Invoice_number   string  `json:"invoice_number" form:"invoice_number"`
+	Warranty_expiry  string  `json:"warranty_expiry" form:"warranty_expiry"`
+	Id_location      int     `json:"id_location" form:"id_location"`
+}
+
+type LifecycleRequestFormat struct {
+	Status          string  `json:"status" form:"status"`
+	Reason          string  `json:"reason" form:"reason"`
+	Date            string  `json:"date" form:"date"`
+	Disposal_method string  `json:"disposal_method" form:"disposal_method"`
+	Recovered_value float64 `json:"recovered_value" form:"recovered_value"`
+}
+
+type DepreciationRequestFormat struct {
+	Method             string  `json:"method" form:"method"`
+	Useful_life_months int     `json:"useful_life_months" form:"useful_life_months"`
+	Salvage_rate       float64 `json:"salvage_rate" form:"salvage_rate"`
+	Declining_factor   float64 `json:"declining_factor" form:"declining_factor"`
+}
+
+type LabelRequestFormat struct {
+	Ids []int `json:"ids" form:"ids"`
 }
@@ -0,0 +1,421 @@
+package attachment
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	attachmentRepo "sirclo/project/capstone/repository/attachment"
+
+	"github.com/labstack/echo/v4"
+)
+
+/*
+document type
+invoice, manual, warranty, other
+*/
+var documentTypes = map[string]bool{
+	"invoice":  true,
+	"manual":   true,
+	"warranty": true,
+	"other":    true,
+}
+
+// content type allowed for each attachment type, with the extension used for the object key
+var uploadContentTypes = map[string]map[string]string{
+	"photo": {
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+	},
+	"document": {
+		"application/pdf": ".pdf",
+	},
+}
+
+const (
+	// presigned url lifetime, in seconds
+	uploadUrlExpiry = 900
+	// time left to confirm an upload before it is cleaned up, in seconds
+	uploadExpiry = 3600
+)
+
+type AttachmentController struct {
+	repository attachmentRepo.AttachmentRepo
+}
+
+func NewAttachmentController(attachment attachmentRepo.AttachmentRepo) *AttachmentController {
+	return &AttachmentController{repository: attachment}
+}
+
+// 1. upload attachments, photos and pdf documents of an asset
+func (ac AttachmentController) UploadAttachmentController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idAsset, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		form, err := c.MultipartForm()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		photos := form.File["photo"]
+		documents := form.File["document"]
+		if len(photos) == 0 && len(documents) == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "no file uploaded"))
+		}
+
+		documentType := c.FormValue("document_type")
+		if documentType == "" {
+			documentType = "other"
+		}
+		if len(documents) > 0 && !documentTypes[documentType] {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "document_type must be invoice || manual || warranty || other"))
+		}
+
+		if err := ac.repository.CheckAsset(idAsset); err != nil {
+			return c.JSON(http.StatusNotFound, response.NotFound("failed", "asset not found"))
+		}
+
+		prefix := "assets_attachment/" + strconv.Itoa(idAsset) + "/" + strconv.FormatInt(time.Now().UnixNano(), 10) + "_"
+
+		// the objects uploaded so far are deleted when a file is refused or the attachments can not be saved
+		var uploaded []string
+		discard := func() {
+			for _, key := range uploaded {
+				util.DeleteFromS3(key)
+			}
+		}
+
+		var attachments []entities.AssetAttachment
+		for i, file := range photos {
+			src, err := file.Open()
+			if err != nil {
+				log.Println(err)
+				discard()
+				return err
+			}
+			image, err := util.ProcessImage(src)
+			src.Close()
+			if err != nil {
+				log.Println(err)
+				discard()
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "photo "+file.Filename+" not allowed, "+err.Error()))
+			}
+
+			fileName := prefix + "photo_" + strconv.Itoa(i)
+			uploaded = append(uploaded, fileName+image.Extension, fileName+"_medium"+image.Extension, fileName+"_thumb"+image.Extension)
+			urlPhoto, err := util.UploadPhotoToS3(image, fileName)
+			if err != nil {
+				discard()
+				return err
+			}
+
+			attachments = append(attachments, entities.AssetAttachment{
+				Id_asset:      idAsset,
+				Type:          "photo",
+				Name:          file.Filename,
+				Content_type:  image.ContentType,
+				Url:           urlPhoto.Original,
+				Url_medium:    urlPhoto.Medium,
+				Url_thumbnail: urlPhoto.Thumbnail,
+			})
+		}
+
+		for i, file := range documents {
+			src, err := file.Open()
+			if err != nil {
+				log.Println(err)
+				discard()
+				return err
+			}
+			document, err := util.ReadDocument(src)
+			src.Close()
+			if err != nil {
+				log.Println(err)
+				discard()
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "document "+file.Filename+" not allowed, "+err.Error()))
+			}
+
+			fileName := prefix + documentType + "_" + strconv.Itoa(i) + ".pdf"
+			uploaded = append(uploaded, fileName)
+			urlDocument, err := util.UploadToS3(document, fileName, "application/pdf")
+			if err != nil {
+				discard()
+				return err
+			}
+
+			attachments = append(attachments, entities.AssetAttachment{
+				Id_asset:      idAsset,
+				Type:          "document",
+				Document_type: documentType,
+				Name:          file.Filename,
+				Content_type:  "application/pdf",
+				Url:           urlDocument,
+			})
+		}
+
+		// every attachment is saved or none
+		if err := ac.repository.Create(attachments); err != nil {
+			log.Println(err)
+			discard()
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to create attachment"))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success upload attachment"))
+	}
+}
+
+// 2. get attachments of an asset
+func (ac AttachmentController) GetAttachmentsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idAsset, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		attachments, err := ac.repository.Get(idAsset)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get attachments", attachments))
+	}
+}
+
+// 3. reorder attachments of an asset
+func (ac AttachmentController) ReorderAttachmentsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idAsset, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var reorderReq ReorderRequestFormat
+		if err := c.Bind(&reorderReq); err != nil || len(reorderReq.Order) == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if err := ac.repository.Reorder(idAsset, reorderReq.Order); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success reorder attachments"))
+	}
+}
+
+// 4. set primary photo of an asset
+func (ac AttachmentController) SetPrimaryAttachmentController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idAsset, errAsset := strconv.Atoi(c.Param("id"))
+		idAttachment, errAttachment := strconv.Atoi(c.Param("id_attachment"))
+		if errAsset != nil || errAttachment != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		if err := ac.repository.SetPrimary(idAsset, idAttachment); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success set primary photo"))
+	}
+}
+
+// 5. delete attachment of an asset
+func (ac AttachmentController) DeleteAttachmentController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idAsset, errAsset := strconv.Atoi(c.Param("id"))
+		idAttachment, errAttachment := strconv.Atoi(c.Param("id_attachment"))
+		if errAsset != nil || errAttachment != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		if err := ac.repository.Delete(idAsset, idAttachment); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "data not found"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "delete success"))
+	}
+}
+
+// 6. create presigned url to upload an attachment directly to the storage
+func (ac AttachmentController) CreateUploadController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		idAsset, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var uploadReq UploadRequestFormat
+		if err := c.Bind(&uploadReq); err != nil || uploadReq.Name == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		contentTypes, ok := uploadContentTypes[uploadReq.Type]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "type must be photo || document"))
+		}
+		extension, ok := contentTypes[uploadReq.Content_type]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "content_type not supported"))
+		}
+
+		if uploadReq.Type == "document" {
+			if uploadReq.Document_type == "" {
+				uploadReq.Document_type = "other"
+			}
+			if !documentTypes[uploadReq.Document_type] {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "document_type must be invoice || manual || warranty || other"))
+			}
+		} else {
+			uploadReq.Document_type = ""
+		}
+
+		if err := ac.repository.CheckAsset(idAsset); err != nil {
+			return c.JSON(http.StatusNotFound, response.NotFound("failed", "asset not found"))
+		}
+
+		objectKey := "assets_upload/" + strconv.Itoa(idAsset) + "/" + strconv.FormatInt(time.Now().UnixNano(), 10) + extension
+		uploadUrl, err := util.PresignUploadS3(objectKey, uploadReq.Content_type, uploadUrlExpiry*time.Second)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, response.InternalServerError("error", "failed to create upload url"))
+		}
+
+		idUpload, err := ac.repository.CreateUpload(entities.AssetUpload{
+			Id_asset:      idAsset,
+			Id_user:       idUser,
+			Object_key:    objectKey,
+			Type:          uploadReq.Type,
+			Document_type: uploadReq.Document_type,
+			Name:          uploadReq.Name,
+			Content_type:  uploadReq.Content_type,
+		}, uploadExpiry)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to create upload"))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success create upload", UploadResponseFormat{
+			Id:           idUpload,
+			Object_key:   objectKey,
+			Upload_url:   uploadUrl,
+			Method:       http.MethodPut,
+			Content_type: uploadReq.Content_type,
+			Expires_in:   uploadUrlExpiry,
+		}))
+	}
+}
+
+// 7. confirm a direct upload, the uploaded file is verified then attached to the asset. A photo is attached once
+// its renditions are made
+func (ac AttachmentController) ConfirmUploadController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idAsset, errAsset := strconv.Atoi(c.Param("id"))
+		idUpload, errUpload := strconv.Atoi(c.Param("id_upload"))
+		if errAsset != nil || errUpload != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		upload, err := ac.repository.GetUpload(idUpload)
+		if err != nil || upload.Id_asset != idAsset {
+			return c.JSON(http.StatusNotFound, response.NotFound("failed", "upload not found"))
+		}
+		if upload.Status != "pending" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "upload "+upload.Status))
+		}
+
+		object, err := util.HeadObjectS3(upload.Object_key)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "file has not been uploaded"))
+		}
+
+		if upload.Type == "photo" {
+			if err := util.CheckSize(object.Size); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "photo not allowed, "+err.Error()))
+			}
+
+			data, err := util.DownloadFromS3(upload.Object_key, 512)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, response.InternalServerError("error", "failed to read uploaded file"))
+			}
+			if err := util.CheckImageContent(data); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "photo not allowed, "+err.Error()))
+			}
+
+			// the renditions are made in the background (see jobs.ProcessUploads), the photo is never read whole here
+			if err := ac.repository.ProcessUpload(idUpload); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+			}
+			return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success confirm upload, the photo is being processed"))
+		}
+
+		if err := util.CheckDocumentSize(object.Size); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "document not allowed, "+err.Error()))
+		}
+
+		data, err := util.DownloadFromS3(upload.Object_key, 512)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, response.InternalServerError("error", "failed to read uploaded file"))
+		}
+		if err := util.CheckDocumentContent(data); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "document not allowed, "+err.Error()))
+		}
+
+		url, err := util.ObjectURLS3(upload.Object_key)
+		if err != nil {
+			return err
+		}
+
+		attachment := entities.AssetAttachment{
+			Id_asset:      idAsset,
+			Type:          upload.Type,
+			Document_type: upload.Document_type,
+			Name:          upload.Name,
+			Content_type:  upload.Content_type,
+			Url:           url,
+		}
+		if err := ac.repository.ConfirmUpload(idUpload, attachment); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success confirm upload"))
+	}
+}
@@ -0,0 +1,761 @@
+package attachment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+func multipartBody(field, filename string, content []byte, values map[string]string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range values {
+		writer.WriteField(key, value)
+	}
+	if field != "" {
+		part, _ := writer.CreateFormFile(field, filename)
+		part.Write(content)
+	}
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+// 1. test upload attachment
+func TestUploadAttachment(t *testing.T) {
+	t.Run("unauthorized access", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+		body, contentType := multipartBody("photo", "laptop.jpg", []byte("photo"), nil)
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.UploadAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+			assert.Equal(t, "unauthorized access", response.Message)
+		}
+	})
+	t.Run("failed to convert id", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		body, contentType := multipartBody("photo", "laptop.jpg", []byte("photo"), nil)
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("a")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.UploadAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "failed to convert id", response.Message)
+		}
+	})
+	t.Run("no file uploaded", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		body, contentType := multipartBody("", "", nil, map[string]string{"document_type": "manual"})
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.UploadAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "no file uploaded", response.Message)
+		}
+	})
+	t.Run("invalid document type", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		body, contentType := multipartBody("document", "invoice.pdf", []byte("%PDF-1.4"), map[string]string{"document_type": "receipt"})
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.UploadAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "document_type must be invoice || manual || warranty || other", response.Message)
+		}
+	})
+	t.Run("asset not found", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		body, contentType := multipartBody("photo", "laptop.jpg", []byte("photo"), nil)
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("100")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.UploadAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusNotFound, res.Code)
+			assert.Equal(t, "asset not found", response.Message)
+		}
+	})
+	t.Run("photo content not allowed", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		body, contentType := multipartBody("photo", "laptop.png", []byte("this is not a png file"), nil)
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.UploadAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "photo laptop.png not allowed, format file not supported", response.Message)
+		}
+	})
+	t.Run("document content not allowed", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		body, contentType := multipartBody("document", "manual.pdf", []byte("plain text"), map[string]string{"document_type": "manual"})
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.UploadAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "document manual.pdf not allowed, format file not supported", response.Message)
+		}
+	})
+}
+
+// 2. test get attachments
+func TestGetAttachments(t *testing.T) {
+	t.Run("failed to fetch data", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("100")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.GetAttachmentsController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "failed to fetch data", response.Message)
+		}
+	})
+	t.Run("success get attachments", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.GetAttachmentsController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, "success get attachments", response.Message)
+		}
+	})
+}
+
+// 3. test reorder attachments
+func TestReorderAttachments(t *testing.T) {
+	t.Run("failed to bind data", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"order": []int{},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/order")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.ReorderAttachmentsController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "failed to bind data", response.Message)
+		}
+	})
+	t.Run("incomplete order", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"order": []int{2},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/order")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.ReorderAttachmentsController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "order must contain every attachment of the asset", response.Message)
+		}
+	})
+	t.Run("duplicate attachment", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"order": []int{2, 2},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/order")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.ReorderAttachmentsController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "attachment 2 is listed twice", response.Message)
+		}
+	})
+	t.Run("attachment of another asset", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"order": []int{2, 1, 9},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/order")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.ReorderAttachmentsController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "attachment 9 not found", response.Message)
+		}
+	})
+	t.Run("success reorder attachments", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"order": []int{2, 1},
+		})
+		req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/order")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.ReorderAttachmentsController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, "success reorder attachments", response.Message)
+		}
+	})
+}
+
+// 4. test set primary photo
+func TestSetPrimaryAttachment(t *testing.T) {
+	t.Run("document cannot be primary", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/:id_attachment/primary")
+		context.SetParamNames("id", "id_attachment")
+		context.SetParamValues("1", "3")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.SetPrimaryAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "only photo can be primary", response.Message)
+		}
+	})
+	t.Run("success set primary photo", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodPut, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/:id_attachment/primary")
+		context.SetParamNames("id", "id_attachment")
+		context.SetParamValues("1", "2")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.SetPrimaryAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, "success set primary photo", response.Message)
+		}
+	})
+}
+
+// 5. test delete attachment
+func TestDeleteAttachment(t *testing.T) {
+	t.Run("unauthorized access", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 3)
+
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/:id_attachment")
+		context.SetParamNames("id", "id_attachment")
+		context.SetParamValues("1", "1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.DeleteAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+			assert.Equal(t, "unauthorized access", response.Message)
+		}
+	})
+	t.Run("data not found", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/:id_attachment")
+		context.SetParamNames("id", "id_attachment")
+		context.SetParamValues("1", "100")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.DeleteAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "data not found", response.Message)
+		}
+	})
+	t.Run("delete success", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/attachments/:id_attachment")
+		context.SetParamNames("id", "id_attachment")
+		context.SetParamValues("1", "1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.DeleteAttachmentController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, "delete success", response.Message)
+		}
+	})
+}
+
+// 6. test create direct upload
+func TestCreateUpload(t *testing.T) {
+	t.Run("unauthorized access", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"name":         "laptop.jpg",
+			"type":         "photo",
+			"content_type": "image/jpeg",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/uploads")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.CreateUploadController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+			assert.Equal(t, "unauthorized access", response.Message)
+		}
+	})
+	t.Run("invalid type", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"name":         "laptop.mp4",
+			"type":         "video",
+			"content_type": "video/mp4",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/uploads")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.CreateUploadController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "type must be photo || document", response.Message)
+		}
+	})
+	t.Run("content type not supported", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"name":         "manual.pdf",
+			"type":         "photo",
+			"content_type": "application/pdf",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/uploads")
+		context.SetParamNames("id")
+		context.SetParamValues("1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.CreateUploadController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "content_type not supported", response.Message)
+		}
+	})
+	t.Run("asset not found", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"name":          "warranty.pdf",
+			"type":          "document",
+			"document_type": "warranty",
+			"content_type":  "application/pdf",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/uploads")
+		context.SetParamNames("id")
+		context.SetParamValues("100")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.CreateUploadController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusNotFound, res.Code)
+			assert.Equal(t, "asset not found", response.Message)
+		}
+	})
+}
+
+// 7. test confirm direct upload
+func TestConfirmUpload(t *testing.T) {
+	t.Run("upload of another asset", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/uploads/:id_upload/confirm")
+		context.SetParamNames("id", "id_upload")
+		context.SetParamValues("2", "1")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.ConfirmUploadController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusNotFound, res.Code)
+			assert.Equal(t, "upload not found", response.Message)
+		}
+	})
+	t.Run("upload already confirmed", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/assets/:id/uploads/:id_upload/confirm")
+		context.SetParamNames("id", "id_upload")
+		context.SetParamValues("1", "2")
+
+		attachmentController := NewAttachmentController(mockAttachmentRepository{})
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(attachmentController.ConfirmUploadController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusBadRequest, res.Code)
+			assert.Equal(t, "upload confirmed", response.Message)
+		}
+	})
+}
+
+type mockAttachmentRepository struct{}
+
+func (m mockAttachmentRepository) CheckAsset(idAsset int) error {
+	if idAsset == 100 {
+		return fmt.Errorf("asset not found")
+	}
+	return nil
+}
+
+func (m mockAttachmentRepository) Create(attachments []entities.AssetAttachment) error {
+	return nil
+}
+
+func (m mockAttachmentRepository) Get(idAsset int) ([]entities.AssetAttachment, error) {
+	if idAsset == 100 {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.AssetAttachment{{Id: 1, Id_asset: idAsset, Type: "photo", Is_primary: true}}, nil
+}
+
+func (m mockAttachmentRepository) Reorder(idAsset int, order []int) error {
+	// the asset has attachments 1 and 2
+	attachments := map[int]bool{1: true, 2: true}
+	listed := map[int]bool{}
+	for _, id := range order {
+		if !attachments[id] {
+			return fmt.Errorf("attachment %d not found", id)
+		}
+		if listed[id] {
+			return fmt.Errorf("attachment %d is listed twice", id)
+		}
+		listed[id] = true
+	}
+	if len(listed) != len(attachments) {
+		return fmt.Errorf("order must contain every attachment of the asset")
+	}
+	return nil
+}
+
+func (m mockAttachmentRepository) SetPrimary(idAsset, id int) error {
+	if id == 3 {
+		return fmt.Errorf("only photo can be primary")
+	}
+	return nil
+}
+
+func (m mockAttachmentRepository) Delete(idAsset, id int) error {
+	if id == 100 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
+
+func (m mockAttachmentRepository) CreateUpload(upload entities.AssetUpload, expiry int) (int, error) {
+	return 1, nil
+}
+
+func (m mockAttachmentRepository) GetUpload(id int) (entities.AssetUpload, error) {
+	if id == 2 {
+		return entities.AssetUpload{Id: id, Id_asset: 1, Type: "photo", Status: "confirmed"}, nil
+	}
+	return entities.AssetUpload{Id: id, Id_asset: 1, Type: "photo", Status: "pending"}, nil
+}
+
+func (m mockAttachmentRepository) ConfirmUpload(id int, attachment entities.AssetAttachment) error {
+	return nil
+}
+
+func (m mockAttachmentRepository) ProcessUpload(id int) error {
+	return nil
+}
+
+func (m mockAttachmentRepository) GetExpiredUploads() ([]entities.AssetUpload, error) {
+	return nil, nil
+}
+
+func (m mockAttachmentRepository) GetProcessingUploads() ([]entities.AssetUpload, error) {
+	return nil, nil
+}
+
+func (m mockAttachmentRepository) RejectUpload(id int) error {
+	return nil
+}
+
+func (m mockAttachmentRepository) ExpireUpload(id int) error {
+	return nil
+}
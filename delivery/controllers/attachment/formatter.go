@@ -0,0 +1,21 @@
+package attachment
+
+type ReorderRequestFormat struct {
+	Order []int `json:"order" form:"order"`
+}
+
+type UploadRequestFormat struct {
+	Name          string `json:"name" form:"name"`
+	Type          string `json:"type" form:"type"`
+	Document_type string `json:"document_type" form:"document_type"`
+	Content_type  string `json:"content_type" form:"content_type"`
+}
+
+type UploadResponseFormat struct {
+	Id           int    `json:"id"`
+	Object_key   string `json:"object_key"`
+	Upload_url   string `json:"upload_url"`
+	Method       string `json:"method"`
+	Content_type string `json:"content_type"`
+	Expires_in   int    `json:"expires_in"`
+}
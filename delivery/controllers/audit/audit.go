@@ -0,0 +1,230 @@
+package audit
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	auditRepo "sirclo/project/capstone/repository/audit"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuditController struct {
+	repository auditRepo.AuditRepo
+}
+
+func NewAuditController(audit auditRepo.AuditRepo) *AuditController {
+	return &AuditController{repository: audit}
+}
+
+// auditReport compares the expected quantity of every item with the quantity found,
+// units on loan are not expected on the shelf so they are not counted as missing
+func auditReport(idSession int, items []entities.AuditItem) entities.AuditReport {
+	report := entities.AuditReport{
+		Id_session:   idSession,
+		Generated_at: time.Now().Format("2006-01-02 15:04:05"),
+		Items:        []entities.AuditItem{},
+	}
+
+	for _, item := range items {
+		item.Missing, item.Unexpected = 0, 0
+		if item.Found < item.Expected {
+			item.Missing = item.Expected - item.Found
+		} else {
+			item.Unexpected = item.Found - item.Expected
+		}
+
+		switch {
+		case item.Missing > 0:
+			item.Result = "missing"
+		case item.Unexpected > 0:
+			item.Result = "unexpected"
+		default:
+			item.Result = "ok"
+		}
+
+		report.Total_expected += item.Expected
+		report.Total_found += item.Found
+		report.Total_missing += item.Missing
+		report.Total_unexpected += item.Unexpected
+		report.Items = append(report.Items, item)
+	}
+	return report
+}
+
+// 1. start audit session controller
+func (ac AuditController) CreateAuditController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		var auditRequest CreateAuditRequestFormat
+		if errBind := c.Bind(&auditRequest); errBind != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if auditRequest.Id_location < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "invalid location"))
+		}
+
+		id, err := ac.repository.Create(entities.AuditSession{
+			Id_location: auditRequest.Id_location,
+			Id_user:     idUser,
+			Notes:       auditRequest.Notes,
+		})
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to create audit session"))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success create audit session", map[string]int{"id": id}))
+	}
+}
+
+// 2. get audit sessions controller
+func (ac AuditController) GetAuditsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 2 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		sessions, err := ac.repository.Get()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get audit sessions", sessions))
+	}
+}
+
+// 3. get audit session controller, with the signed off report or the report of the counts so far
+func (ac AuditController) GetAuditController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 2 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		sessionId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		session, err := ac.repository.GetById(sessionId)
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, response.NotFound("not found", "audit session not found"))
+		}
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+
+		if session.Report == nil {
+			items, err := ac.repository.GetItems(sessionId)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+			}
+			report := auditReport(sessionId, items)
+			session.Report = &report
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get audit session", session))
+	}
+}
+
+// 4. scan asset tag controller
+func (ac AuditController) ScanAuditController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		sessionId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var scanRequest ScanRequestFormat
+		if errBind := c.Bind(&scanRequest); errBind != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if scanRequest.Tag == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "tag is required"))
+		}
+
+		asset, err := ac.repository.Scan(sessionId, scanRequest.Tag, idUser)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success scan asset", asset))
+	}
+}
+
+// 5. tick off asset with the quantity counted controller
+func (ac AuditController) CountAuditController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		sessionId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var countRequest CountRequestFormat
+		if errBind := c.Bind(&countRequest); errBind != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if countRequest.Id_asset <= 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "id_asset is required"))
+		}
+		if countRequest.Found_quantity < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "found_quantity must not be negative"))
+		}
+
+		if err := ac.repository.SetCount(sessionId, countRequest.Id_asset, countRequest.Found_quantity, idUser); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success count asset"))
+	}
+}
+
+// 6. sign off audit session controller, the report is stored with the session
+func (ac AuditController) SignOffAuditController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		sessionId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		items, err := ac.repository.GetItems(sessionId)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		report := auditReport(sessionId, items)
+
+		if err := ac.repository.SignOff(sessionId, idUser, report); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success sign off audit session", report))
+	}
+}
@@ -0,0 +1,361 @@
+package audit
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test start audit session
+func TestCreateAudit(t *testing.T) {
+	testCases := []struct {
+		name       string
+		idRole     int
+		idLocation int
+		code       int
+		message    string
+	}{
+		{"unauthorized access", 3, 1, http.StatusUnauthorized, "unauthorized access"},
+		{"invalid location", 1, -1, http.StatusBadRequest, "invalid location"},
+		{"failed to create audit session", 1, 99, http.StatusBadRequest, "failed to create audit session"},
+		{"success create audit session", 1, 1, http.StatusOK, "success create audit session"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"id_location": tc.idLocation, "notes": "yearly stocktake"})
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/audits")
+
+			auditController := NewAuditController(mockAuditRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(auditController.CreateAuditController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test get audit sessions
+func TestGetAudits(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, http.StatusUnauthorized, "unauthorized access"},
+		{"success get audit sessions", 3, http.StatusOK, "success get audit sessions"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/audits")
+
+			auditController := NewAuditController(mockAuditRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(auditController.GetAuditsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 3. test get audit session with its report
+func TestGetAudit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		idRole    int
+		idSession string
+		code      int
+		message   string
+		missing   int
+	}{
+		{"unauthorized access", 2, "1", http.StatusUnauthorized, "unauthorized access", 0},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id", 0},
+		{"audit session not found", 1, "99", http.StatusNotFound, "audit session not found", 0},
+		{"success get open audit session", 1, "1", http.StatusOK, "success get audit session", 3},
+		{"success get signed off audit session", 3, "2", http.StatusOK, "success get audit session", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/audits/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idSession)
+
+			auditController := NewAuditController(mockAuditRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(auditController.GetAuditController())(context)) {
+				var response struct {
+					Responses
+					Data entities.AuditSession `json:"data"`
+				}
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				if tc.code == http.StatusOK {
+					assert.Equal(t, tc.missing, response.Data.Report.Total_missing)
+				}
+			}
+		})
+	}
+}
+
+// 4. test scan asset tag
+func TestScanAudit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		idRole    int
+		idSession string
+		tag       string
+		code      int
+		message   string
+	}{
+		{"unauthorized access", 3, "1", "AST-000001", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", "AST-000001", http.StatusBadRequest, "failed to convert id"},
+		{"tag is required", 1, "1", "", http.StatusBadRequest, "tag is required"},
+		{"signed off session", 1, "2", "AST-000001", http.StatusBadRequest, "audit session is already signed off"},
+		{"unknown tag", 1, "1", "AST-999999", http.StatusBadRequest, "asset not found"},
+		{"success scan asset", 1, "1", "AST-000001", http.StatusOK, "success scan asset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"tag": tc.tag})
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/audits/:id/scans")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idSession)
+
+			auditController := NewAuditController(mockAuditRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(auditController.ScanAuditController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 5. test tick off asset with the quantity counted
+func TestCountAudit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		idRole    int
+		idSession string
+		idAsset   int
+		found     int
+		code      int
+		message   string
+	}{
+		{"unauthorized access", 2, "1", 1, 2, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", 1, 2, http.StatusBadRequest, "failed to convert id"},
+		{"id_asset is required", 1, "1", 0, 2, http.StatusBadRequest, "id_asset is required"},
+		{"negative quantity", 1, "1", 1, -1, http.StatusBadRequest, "found_quantity must not be negative"},
+		{"signed off session", 1, "2", 1, 2, http.StatusBadRequest, "audit session is already signed off"},
+		{"success count asset", 1, "1", 1, 0, http.StatusOK, "success count asset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"id_asset": tc.idAsset, "found_quantity": tc.found})
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/audits/:id/counts")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idSession)
+
+			auditController := NewAuditController(mockAuditRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(auditController.CountAuditController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 6. test sign off audit session
+func TestSignOffAudit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		idRole    int
+		idSession string
+		code      int
+		message   string
+	}{
+		{"unauthorized access", 3, "1", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"already signed off", 1, "2", http.StatusBadRequest, "audit session not found or already signed off"},
+		{"success sign off audit session", 1, "1", http.StatusOK, "success sign off audit session"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/audits/:id/sign-off")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idSession)
+
+			auditController := NewAuditController(mockAuditRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(auditController.SignOffAuditController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 7. test expected vs found computation
+func TestAuditReport(t *testing.T) {
+	report := auditReport(1, mockAuditItems())
+
+	assert.Equal(t, 7, report.Total_expected)
+	assert.Equal(t, 5, report.Total_found)
+	assert.Equal(t, 3, report.Total_missing)
+	assert.Equal(t, 1, report.Total_unexpected)
+
+	results := []string{}
+	for _, item := range report.Items {
+		results = append(results, item.Result)
+	}
+	assert.Equal(t, []string{"ok", "missing", "unexpected", "missing"}, results)
+	assert.Equal(t, 2, report.Items[1].Missing)
+	assert.Equal(t, 1, report.Items[2].Unexpected)
+}
+
+// expected units on the shelf: laptop 3 (1 more on loan), monitor 2, projector from another room 0, chair 2
+func mockAuditItems() []entities.AuditItem {
+	return []entities.AuditItem{
+		{Id_asset: 1, Tag: "AST-000001", Name: "laptop", Expected: 3, On_loan: 1, Found: 3},
+		{Id_asset: 2, Tag: "AST-000002", Name: "monitor", Expected: 2, Found: 0},
+		{Id_asset: 3, Tag: "AST-000003", Name: "projector", Expected: 0, Found: 1},
+		{Id_asset: 4, Tag: "AST-000004", Name: "chair", Expected: 2, Found: 1},
+	}
+}
+
+type mockAuditRepository struct{}
+
+func (m mockAuditRepository) Create(session entities.AuditSession) (int, error) {
+	if session.Id_location == 99 {
+		return 0, fmt.Errorf("error")
+	}
+	return 1, nil
+}
+
+func (m mockAuditRepository) Get() ([]entities.AuditSession, error) {
+	return []entities.AuditSession{{Id: 1, Id_location: 1, Status: "open"}}, nil
+}
+
+func (m mockAuditRepository) GetById(id int) (entities.AuditSession, error) {
+	switch id {
+	case 1:
+		return entities.AuditSession{Id: 1, Id_location: 1, Status: "open"}, nil
+	case 2:
+		return entities.AuditSession{Id: 2, Id_location: 1, Status: "signed_off", Report: &entities.AuditReport{Id_session: 2, Total_missing: 1}}, nil
+	}
+	return entities.AuditSession{}, sql.ErrNoRows
+}
+
+func (m mockAuditRepository) Scan(idSession int, tag string, idUser int) (entities.Asset, error) {
+	if idSession == 2 {
+		return entities.Asset{}, fmt.Errorf("audit session is already signed off")
+	}
+	if tag != "AST-000001" {
+		return entities.Asset{}, fmt.Errorf("asset not found")
+	}
+	return entities.Asset{Id: 1, Tag: tag, Name: "laptop"}, nil
+}
+
+func (m mockAuditRepository) SetCount(idSession, idAsset, found, idUser int) error {
+	if idSession == 2 {
+		return fmt.Errorf("audit session is already signed off")
+	}
+	return nil
+}
+
+func (m mockAuditRepository) GetItems(idSession int) ([]entities.AuditItem, error) {
+	return mockAuditItems(), nil
+}
+
+func (m mockAuditRepository) SignOff(idSession, idUser int, report entities.AuditReport) error {
+	if idSession == 2 {
+		return fmt.Errorf("audit session not found or already signed off")
+	}
+	return nil
+}
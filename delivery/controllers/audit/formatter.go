@@ -0,0 +1,15 @@
+package audit
+
+type CreateAuditRequestFormat struct {
+	Id_location int    `json:"id_location" form:"id_location"`
+	Notes       string `json:"notes" form:"notes"`
+}
+
+type ScanRequestFormat struct {
+	Tag string `json:"tag" form:"tag"`
+}
+
+type CountRequestFormat struct {
+	Id_asset       int `json:"id_asset" form:"id_asset"`
+	Found_quantity int `json:"found_quantity" form:"found_quantity"`
+}
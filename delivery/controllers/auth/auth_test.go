@@ -107,10 +107,32 @@ func TestLogin(t *testing.T) {
 			assert.Equal(t, 400, res.Code)
 		}
 	})
+	t.Run("Failed Login after deactivation", func(t *testing.T) {
+		e := echo.New()
+		requestBody, _ := json.Marshal(map[string]string{
+			"password": "itachi",
+			"email":    "itachi@mail.com",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+		res := httptest.NewRecorder()
+		req.Header.Set("Content-Type", "application/json")
+		context := e.NewContext(req, res)
+
+		AuthController := NewAuthController(mockAuthRepository{})
+		if assert.NoError(t, (AuthController.LoginEmailController())(context)) {
+			var response common.DefaultResponse
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, 400, res.Code)
+			assert.Equal(t, "email not found", response.Message)
+		}
+	})
 }
 
 // =========================== mocking ===========================
 
+// itachi@mail.com (user 2) was deactivated, the repository no longer finds it by email
+
 type mockAuthRepository struct{}
 
 func (m mockAuthRepository) LoginEmail(email, password string) (string, error) {
@@ -163,3 +185,7 @@ func (m mockAuthRepository) GetNameByEmail(email string) (string, error) {
 
 	return "", fmt.Errorf("no record")
 }
+
+func (m mockAuthRepository) IsActive(id int) (bool, error) {
+	return id != 2, nil
+}
@@ -0,0 +1,86 @@
+package comment
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sirclo/project/capstone/entities"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	commentRepo "sirclo/project/capstone/repository/comment"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CommentController struct {
+	repository commentRepo.CommentRepo
+}
+
+func NewCommentController(comment commentRepo.CommentRepo) *CommentController {
+	return &CommentController{repository: comment}
+}
+
+// 1. add comment on a request controller, only the admin and the manager write internal notes
+func (cc CommentController) CreateCommentController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		// get id from param
+		idRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var commentReq CommentRequestFormat
+		if err := c.Bind(&commentReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		commentReq.Body = strings.TrimSpace(commentReq.Body)
+		if commentReq.Body == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "body is required"))
+		}
+		if commentReq.Internal && idRole == 2 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		id, err := cc.repository.Create(entities.RequestComment{
+			Id_request: idRequest,
+			Id_user:    idUser,
+			Body:       commentReq.Body,
+			Internal:   commentReq.Internal,
+		})
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to create comment"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success create comment", map[string]int{"id": id}))
+	}
+}
+
+// 2. get comments of a request controller, the employee does not see internal notes
+func (cc CommentController) GetCommentsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		comments, err := cc.repository.Get(idRequest, idRole != 2)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get comments", comments))
+	}
+}
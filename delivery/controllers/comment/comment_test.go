@@ -0,0 +1,195 @@
+package comment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type CommentsResponses struct {
+	Code    int                       `json:"code"`
+	Status  string                    `json:"status"`
+	Message string                    `json:"message"`
+	Data    []entities.RequestComment `json:"data"`
+}
+
+// 1. test create comment
+func TestCreateComment(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"failed to convert id", 2, "a", map[string]interface{}{"body": "when can I pick it up?"}, http.StatusBadRequest, "failed to convert id"},
+		{"failed to bind data", 2, "1", map[string]interface{}{"body": 1}, http.StatusBadRequest, "failed to bind data"},
+		{"body is required", 2, "1", map[string]interface{}{"body": "   "}, http.StatusBadRequest, "body is required"},
+		{"employee internal note", 2, "1", map[string]interface{}{"body": "note", "internal": true}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to create comment", 1, "9", map[string]interface{}{"body": "note"}, http.StatusBadRequest, "failed to create comment"},
+		{"success employee comment", 2, "1", map[string]interface{}{"body": "when can I pick it up?"}, http.StatusOK, "success create comment"},
+		{"success manager internal note", 3, "1", map[string]interface{}{"body": "check the budget first", "internal": true}, http.StatusOK, "success create comment"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id/comments")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			commentController := NewCommentController(mockCommentRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(commentController.CreateCommentController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test get comments
+func TestGetComments(t *testing.T) {
+	testCases := []struct {
+		name     string
+		idRole   int
+		id       string
+		code     int
+		message  string
+		comments int
+	}{
+		{"failed to convert id", 2, "a", http.StatusBadRequest, "failed to convert id", 0},
+		{"failed to fetch data", 1, "9", http.StatusBadRequest, "failed to fetch data", 0},
+		{"employee without internal notes", 2, "1", http.StatusOK, "success get comments", 1},
+		{"manager with internal notes", 3, "1", http.StatusOK, "success get comments", 2},
+		{"admin with internal notes", 1, "1", http.StatusOK, "success get comments", 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id/comments")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			commentController := NewCommentController(mockCommentRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(commentController.GetCommentsController())(context)) {
+				var response CommentsResponses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				assert.Equal(t, tc.comments, len(response.Data))
+			}
+		})
+	}
+}
+
+type mockCommentRepository struct{}
+
+func (m mockCommentRepository) Create(comment entities.RequestComment) (int, error) {
+	if comment.Id_request == 9 {
+		return 0, fmt.Errorf("error")
+	}
+	return 1, nil
+}
+
+func (m mockCommentRepository) Get(idRequest int, internal bool) ([]entities.RequestComment, error) {
+	if idRequest == 9 {
+		return nil, fmt.Errorf("error")
+	}
+	comments := []entities.RequestComment{{Id: 1, Id_request: idRequest, Body: "when can I pick it up?"}}
+	if internal {
+		comments = append(comments, entities.RequestComment{Id: 2, Id_request: idRequest, Body: "check the budget first", Internal: true})
+	}
+	return comments, nil
+}
+
+// 3. test comments behind the access of the request, the user approves for the manager of request 6 and request 7
+// was escalated to them, neither makes them a party to the request
+func TestCommentAccess(t *testing.T) {
+	load := func(idResource, idUser int) (entities.Ownership, error) {
+		switch idResource {
+		case 6:
+			return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 2}, nil
+		case 7:
+			return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Escalated: true, Id_status: 2}, nil
+		}
+		return entities.Ownership{Id_owner: 1, Owner_divisi: "IT", User_divisi: "IT"}, nil
+	}
+
+	testCases := []struct {
+		name       string
+		idRole     int
+		method     string
+		id         string
+		controller func(CommentController) echo.HandlerFunc
+		code       int
+		message    string
+	}{
+		{"delegate comments", 2, http.MethodPost, "6", CommentController.CreateCommentController, http.StatusUnauthorized, "unauthorized access"},
+		{"delegate reads comments", 2, http.MethodGet, "6", CommentController.GetCommentsController, http.StatusUnauthorized, "unauthorized access"},
+		{"fallback approver comments", 3, http.MethodPost, "7", CommentController.CreateCommentController, http.StatusUnauthorized, "unauthorized access"},
+		{"owner comments", 2, http.MethodPost, "1", CommentController.CreateCommentController, http.StatusOK, "success create comment"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"body": "when can I pick it up?"})
+			req := httptest.NewRequest(tc.method, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id/comments")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			commentController := *NewCommentController(mockCommentRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(middlewares.ResourceAccess(load)(tc.controller(commentController)))(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
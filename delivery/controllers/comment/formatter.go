@@ -0,0 +1,6 @@
+package comment
+
+type CommentRequestFormat struct {
+	Body     string `json:"body" form:"body"`
+	Internal bool   `json:"internal" form:"internal"`
+}
@@ -0,0 +1,114 @@
+package delegation
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	delegationRepo "sirclo/project/capstone/repository/delegation"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DelegationController struct {
+	repository delegationRepo.DelegationRepo
+}
+
+func NewDelegationController(delegation delegationRepo.DelegationRepo) *DelegationController {
+	return &DelegationController{repository: delegation}
+}
+
+// 1. create delegation controller, a manager names who approves for them from the start date to the end of the end date
+func (dc DelegationController) CreateDelegationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		var delegationReq DelegationRequestFormat
+		if err := c.Bind(&delegationReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if delegationReq.Id_delegate == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "id_delegate is required"))
+		}
+		if delegationReq.Id_delegate == idUser {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "a manager can not delegate to themselves"))
+		}
+
+		start, errStart := time.ParseInLocation("2006-01-02", delegationReq.Start_date, time.Local)
+		end, errEnd := time.ParseInLocation("2006-01-02", delegationReq.End_date, time.Local)
+		if errStart != nil || errEnd != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "start_date and end_date must be formatted as YYYY-MM-DD"))
+		}
+		end = end.AddDate(0, 0, 1).Add(-time.Second)
+		if end.Before(start) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "end_date must not be before start_date"))
+		}
+		if end.Before(time.Now()) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "end_date must not be in the past"))
+		}
+
+		id, err := dc.repository.Create(entities.Delegation{
+			Id_manager:  idUser,
+			Id_delegate: delegationReq.Id_delegate,
+			Start_at:    start.Format(util.DateTimeLayout),
+			End_at:      end.Format(util.DateTimeLayout),
+		})
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success create delegation", map[string]int{"id": id}))
+	}
+}
+
+// 2. get delegations controller, the admin gets every delegation, others the delegations they gave or received
+func (dc DelegationController) GetDelegationsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idUser := 0
+		if idRole != 1 {
+			idUser, _ = middlewares.GetId(c)
+		}
+
+		delegations, err := dc.repository.Get(idUser)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get delegations", delegations))
+	}
+}
+
+// 3. revoke delegation controller
+func (dc DelegationController) RevokeDelegationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		// get id from param
+		idDelegation, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		if err := dc.repository.Revoke(idDelegation, idUser); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success revoke delegation"))
+	}
+}
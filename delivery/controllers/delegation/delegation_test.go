@@ -0,0 +1,179 @@
+package delegation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test create delegation
+func TestCreateDelegation(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	lastWeek := time.Now().AddDate(0, 0, -7).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, map[string]interface{}{"id_delegate": 2, "start_date": today, "end_date": nextWeek}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to bind data", 3, map[string]interface{}{"id_delegate": "2"}, http.StatusBadRequest, "failed to bind data"},
+		{"id_delegate is required", 3, map[string]interface{}{"start_date": today, "end_date": nextWeek}, http.StatusBadRequest, "id_delegate is required"},
+		{"delegate to themselves", 3, map[string]interface{}{"id_delegate": 1, "start_date": today, "end_date": nextWeek}, http.StatusBadRequest, "a manager can not delegate to themselves"},
+		{"invalid date", 3, map[string]interface{}{"id_delegate": 2, "start_date": "today", "end_date": nextWeek}, http.StatusBadRequest, "start_date and end_date must be formatted as YYYY-MM-DD"},
+		{"end before start", 3, map[string]interface{}{"id_delegate": 2, "start_date": nextWeek, "end_date": today}, http.StatusBadRequest, "end_date must not be before start_date"},
+		{"window in the past", 3, map[string]interface{}{"id_delegate": 2, "start_date": lastWeek, "end_date": yesterday}, http.StatusBadRequest, "end_date must not be in the past"},
+		{"overlapping window", 3, map[string]interface{}{"id_delegate": 3, "start_date": today, "end_date": nextWeek}, http.StatusBadRequest, "delegation overlaps an existing one"},
+		{"success one day", 3, map[string]interface{}{"id_delegate": 2, "start_date": today, "end_date": today}, http.StatusOK, "success create delegation"},
+		{"success", 3, map[string]interface{}{"id_delegate": 2, "start_date": today, "end_date": nextWeek}, http.StatusOK, "success create delegation"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/delegations")
+
+			delegationController := NewDelegationController(mockDelegationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(delegationController.CreateDelegationController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test get delegations
+func TestGetDelegations(t *testing.T) {
+	testCases := []struct {
+		name    string
+		repo    mockDelegationRepository
+		idRole  int
+		code    int
+		message string
+	}{
+		{"failed to fetch data", mockDelegationRepository{fail: true}, 3, http.StatusBadRequest, "failed to fetch data"},
+		{"success admin", mockDelegationRepository{}, 1, http.StatusOK, "success get delegations"},
+		{"success delegate", mockDelegationRepository{}, 2, http.StatusOK, "success get delegations"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/delegations")
+
+			delegationController := NewDelegationController(tc.repo)
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(delegationController.GetDelegationsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 3. test revoke delegation
+func TestRevokeDelegation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"unauthorized access", 1, "1", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 3, "a", http.StatusBadRequest, "failed to convert id"},
+		{"not active", 3, "2", http.StatusBadRequest, "delegation not found or no longer active"},
+		{"success revoke", 3, "1", http.StatusOK, "success revoke delegation"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/delegations/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			delegationController := NewDelegationController(mockDelegationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(delegationController.RevokeDelegationController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// the manager already delegates to user 3 this week
+type mockDelegationRepository struct {
+	fail bool
+}
+
+func (m mockDelegationRepository) Create(delegation entities.Delegation) (int, error) {
+	if delegation.Id_delegate == 3 {
+		return 0, fmt.Errorf("delegation overlaps an existing one")
+	}
+	return 1, nil
+}
+
+func (m mockDelegationRepository) Get(idUser int) ([]entities.Delegation, error) {
+	if m.fail {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.Delegation{{Id: 1, Id_manager: 1, Id_delegate: 3}}, nil
+}
+
+func (m mockDelegationRepository) Revoke(id, idManager int) error {
+	if id != 1 {
+		return fmt.Errorf("delegation not found or no longer active")
+	}
+	return nil
+}
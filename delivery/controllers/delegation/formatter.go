@@ -0,0 +1,7 @@
+package delegation
+
+type DelegationRequestFormat struct {
+	Id_delegate int    `json:"id_delegate" form:"id_delegate"`
+	Start_date  string `json:"start_date" form:"start_date"`
+	End_date    string `json:"end_date" form:"end_date"`
+}
@@ -0,0 +1,12 @@
+package kit
+
+type KitRequestFormat struct {
+	Name        string          `json:"name" form:"name"`
+	Description string          `json:"description" form:"description"`
+	Items       []KitItemFormat `json:"items" form:"items"`
+}
+
+type KitItemFormat struct {
+	Id_asset int `json:"id_asset" form:"id_asset"`
+	Quantity int `json:"quantity" form:"quantity"`
+}
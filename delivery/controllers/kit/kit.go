@@ -0,0 +1,190 @@
+package kit
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"sirclo/project/capstone/entities"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	kitRepo "sirclo/project/capstone/repository/kit"
+
+	"github.com/labstack/echo/v4"
+)
+
+type KitController struct {
+	repository kitRepo.KitRepo
+}
+
+func NewKitController(kit kitRepo.KitRepo) *KitController {
+	return &KitController{repository: kit}
+}
+
+// kitItems checks the components of a kit, an asset is listed once with its quantity
+func kitItems(items []KitItemFormat) ([]entities.KitItem, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("items is required")
+	}
+
+	seen := map[int]bool{}
+	kitItems := []entities.KitItem{}
+	for _, item := range items {
+		if item.Id_asset <= 0 {
+			return nil, fmt.Errorf("id_asset is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity must be greater than 0")
+		}
+		if seen[item.Id_asset] {
+			return nil, fmt.Errorf("asset %d is listed more than once", item.Id_asset)
+		}
+		seen[item.Id_asset] = true
+		kitItems = append(kitItems, entities.KitItem{Id_asset: item.Id_asset, Quantity: item.Quantity})
+	}
+	return kitItems, nil
+}
+
+// 1. create kit controller
+func (kc KitController) CreateKitController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// bind data
+		var kitRequest KitRequestFormat
+		if err := c.Bind(&kitRequest); err != nil {
+			log.Println(err)
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if kitRequest.Name == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "name is required"))
+		}
+
+		items, err := kitItems(kitRequest.Items)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		kit := entities.Kit{
+			Name:        kitRequest.Name,
+			Description: kitRequest.Description,
+			Items:       items,
+		}
+
+		id, err := kc.repository.Create(kit)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success create kit", map[string]int{"id": id}))
+	}
+}
+
+// 2. get all kits controller
+func (kc KitController) GetKitsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		kits, err := kc.repository.Get()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all kits", kits))
+	}
+}
+
+// 3. get kit by id controller
+func (kc KitController) GetKitByIdController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		kitId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		kit, err := kc.repository.GetById(kitId)
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, response.NotFound("not found", "kit not found"))
+		}
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get kit", kit))
+	}
+}
+
+// 4. update kit controller, the components are replaced when items is given
+func (kc KitController) UpdateKitController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		kitId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		// bind data
+		var kitRequest KitRequestFormat
+		if err := c.Bind(&kitRequest); err != nil {
+			log.Println(err)
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		kit := entities.Kit{
+			Name:        kitRequest.Name,
+			Description: kitRequest.Description,
+		}
+		if kitRequest.Items != nil {
+			if kit.Items, err = kitItems(kitRequest.Items); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+			}
+		}
+
+		if err := kc.repository.Update(kit, kitId); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update kit"))
+	}
+}
+
+// 5. delete kit controller
+func (kc KitController) DeleteKitController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		kitId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		if err := kc.repository.Delete(kitId); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success delete kit"))
+	}
+}
@@ -0,0 +1,273 @@
+package kit
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test create kit
+func TestCreateKit(t *testing.T) {
+	laptop := map[string]interface{}{"id_asset": 1, "quantity": 1}
+	mouse := map[string]interface{}{"id_asset": 3, "quantity": 1}
+
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, map[string]interface{}{"name": "new hire"}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to bind data", 1, map[string]interface{}{"name": 1}, http.StatusBadRequest, "failed to bind data"},
+		{"name is required", 1, map[string]interface{}{"items": []interface{}{laptop}}, http.StatusBadRequest, "name is required"},
+		{"items is required", 1, map[string]interface{}{"name": "new hire"}, http.StatusBadRequest, "items is required"},
+		{"quantity must be positive", 1, map[string]interface{}{"name": "new hire", "items": []interface{}{map[string]interface{}{"id_asset": 1}}}, http.StatusBadRequest, "quantity must be greater than 0"},
+		{"duplicate asset", 1, map[string]interface{}{"name": "new hire", "items": []interface{}{laptop, laptop}}, http.StatusBadRequest, "asset 1 is listed more than once"},
+		{"asset not found", 1, map[string]interface{}{"name": "new hire", "items": []interface{}{map[string]interface{}{"id_asset": 100, "quantity": 1}}}, http.StatusBadRequest, "asset 100 not found"},
+		{"success create kit", 1, map[string]interface{}{"name": "new hire", "items": []interface{}{laptop, mouse}}, http.StatusOK, "success create kit"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kits")
+
+			kitController := NewKitController(mockKitRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(kitController.CreateKitController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test get all kits
+func TestGetKits(t *testing.T) {
+	testCases := []struct {
+		name       string
+		repository mockKitRepository
+		code       int
+		message    string
+	}{
+		{"failed to fetch data", mockKitRepository{fail: true}, http.StatusBadRequest, "failed to fetch data"},
+		{"success get all kits", mockKitRepository{}, http.StatusOK, "success get all kits"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kits")
+
+			kitController := NewKitController(tc.repository)
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(kitController.GetKitsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 3. test get kit by id
+func TestGetKitById(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idKit   string
+		code    int
+		message string
+	}{
+		{"failed to convert id", "a", http.StatusBadRequest, "failed to convert id"},
+		{"kit not found", "100", http.StatusNotFound, "kit not found"},
+		{"success get kit", "1", http.StatusOK, "success get kit"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kits/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idKit)
+
+			kitController := NewKitController(mockKitRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(kitController.GetKitByIdController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 4. test update kit
+func TestUpdateKit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		idKit   string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, "1", map[string]interface{}{"name": "new hire"}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", map[string]interface{}{"name": "new hire"}, http.StatusBadRequest, "failed to convert id"},
+		{"empty items", 1, "1", map[string]interface{}{"items": []interface{}{}}, http.StatusBadRequest, "items is required"},
+		{"id not found", 1, "100", map[string]interface{}{"name": "new hire"}, http.StatusBadRequest, "id not found"},
+		{"success update name", 1, "1", map[string]interface{}{"name": "new hire"}, http.StatusOK, "success update kit"},
+		{"success replace items", 1, "1", map[string]interface{}{"items": []interface{}{map[string]interface{}{"id_asset": 1, "quantity": 2}}}, http.StatusOK, "success update kit"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kits/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idKit)
+
+			kitController := NewKitController(mockKitRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(kitController.UpdateKitController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 5. test delete kit
+func TestDeleteKit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		idKit   string
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "1", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"id not found", 1, "100", http.StatusBadRequest, "id not found"},
+		{"success delete kit", 1, "1", http.StatusOK, "success delete kit"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kits/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idKit)
+
+			kitController := NewKitController(mockKitRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(kitController.DeleteKitController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+type mockKitRepository struct {
+	fail bool
+}
+
+func (m mockKitRepository) Create(kit entities.Kit) (int, error) {
+	for _, item := range kit.Items {
+		if item.Id_asset == 100 {
+			return 0, fmt.Errorf("asset %d not found", item.Id_asset)
+		}
+	}
+	return 1, nil
+}
+
+func (m mockKitRepository) Get() ([]entities.Kit, error) {
+	if m.fail {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.Kit{{Id: 1, Name: "new hire", Items: []entities.KitItem{{Id_asset: 1, Asset_name: "laptop", Quantity: 1}}}}, nil
+}
+
+func (m mockKitRepository) GetById(id int) (entities.Kit, error) {
+	if id == 100 {
+		return entities.Kit{}, sql.ErrNoRows
+	}
+	return entities.Kit{Id: id, Name: "new hire", Items: []entities.KitItem{{Id_asset: 1, Asset_name: "laptop", Quantity: 1}}}, nil
+}
+
+func (m mockKitRepository) Update(kit entities.Kit, id int) error {
+	if id == 100 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
+
+func (m mockKitRepository) Delete(id int) error {
+	if id == 100 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
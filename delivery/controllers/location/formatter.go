@@ -0,0 +1,12 @@
+package location
+
+type LocationRequestFormat struct {
+	Id_parent int    `json:"id_parent" form:"id_parent"`
+	Type      string `json:"type" form:"type"`
+	Name      string `json:"name" form:"name"`
+}
+
+type MoveRequestFormat struct {
+	Id_location int    `json:"id_location" form:"id_location"`
+	Notes       string `json:"notes" form:"notes"`
+}
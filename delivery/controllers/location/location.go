@@ -0,0 +1,267 @@
+package location
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"sirclo/project/capstone/entities"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	locationRepo "sirclo/project/capstone/repository/location"
+
+	"github.com/labstack/echo/v4"
+)
+
+type LocationController struct {
+	repository locationRepo.LocationRepo
+}
+
+func NewLocationController(location locationRepo.LocationRepo) *LocationController {
+	return &LocationController{repository: location}
+}
+
+// parentTypes is the type the parent of each location type must have, a site is the root of the tree
+var parentTypes = map[string]string{
+	"site":     "",
+	"building": "site",
+	"room":     "building",
+}
+
+// buildTree nests every location under its parent
+func buildTree(locations []entities.Location) []entities.Location {
+	children := map[int][]entities.Location{}
+	for _, location := range locations {
+		children[location.Id_parent] = append(children[location.Id_parent], location)
+	}
+
+	var attach func(parent int) []entities.Location
+	attach = func(parent int) []entities.Location {
+		nodes := children[parent]
+		for i := range nodes {
+			nodes[i].Children = attach(nodes[i].Id)
+		}
+		return nodes
+	}
+
+	tree := attach(0)
+	if tree == nil {
+		tree = []entities.Location{}
+	}
+	return tree
+}
+
+// 1. create location controller
+func (lc LocationController) CreateLocationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// bind data
+		var locationRequest LocationRequestFormat
+		if err := c.Bind(&locationRequest); err != nil {
+			log.Println(err)
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if locationRequest.Name == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "name is required"))
+		}
+
+		parentType, ok := parentTypes[locationRequest.Type]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "type must be site || building || room"))
+		}
+
+		if parentType == "" && locationRequest.Id_parent != 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "site cannot have a parent location"))
+		}
+
+		if parentType != "" {
+			parent, err := lc.repository.GetById(locationRequest.Id_parent)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "parent location not found"))
+			}
+			if parent.Type != parentType {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "parent of a "+locationRequest.Type+" must be a "+parentType))
+			}
+		}
+
+		location := entities.Location{
+			Id_parent: locationRequest.Id_parent,
+			Type:      locationRequest.Type,
+			Name:      locationRequest.Name,
+		}
+
+		err = lc.repository.Create(location)
+		if err != nil {
+			log.Println(err)
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to create location"))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success create location"))
+	}
+}
+
+// 2. get location tree controller
+func (lc LocationController) GetLocationsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		locations, err := lc.repository.Get()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all locations", buildTree(locations)))
+	}
+}
+
+// 3. get location by id
+func (lc LocationController) GetLocationByIdController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		locationId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		location, err := lc.repository.GetById(locationId)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get location", location))
+	}
+}
+
+// 4. update location name
+func (lc LocationController) UpdateLocationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		locationId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		// binding data
+		var locationRequest LocationRequestFormat
+		if errBind := c.Bind(&locationRequest); errBind != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if locationRequest.Name == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "name is required"))
+		}
+
+		errUpdate := lc.repository.Update(entities.Location{Name: locationRequest.Name}, locationId)
+		if errUpdate != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed update data"))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update location"))
+	}
+}
+
+// 5. delete location
+func (lc LocationController) DeleteLocationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		locationId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		errDelete := lc.repository.Delete(locationId)
+		if errDelete != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", errDelete.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "delete success"))
+	}
+}
+
+// 6. move asset to another location
+func (lc LocationController) MoveAssetController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idUser, err := middlewares.GetId(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		assetId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		// binding data
+		var moveRequest MoveRequestFormat
+		if errBind := c.Bind(&moveRequest); errBind != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if moveRequest.Id_location == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "id_location is required"))
+		}
+
+		move := entities.AssetLocationMove{
+			Id_asset:    assetId,
+			Id_user:     idUser,
+			To_location: moveRequest.Id_location,
+			Notes:       moveRequest.Notes,
+		}
+
+		if err := lc.repository.MoveAsset(move); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success move asset"))
+	}
+}
+
+// 7. get location history of an asset
+func (lc LocationController) GetAssetMovesController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		assetId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		moves, err := lc.repository.GetAssetMoves(assetId)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get location history", moves))
+	}
+}
@@ -0,0 +1,284 @@
+package location
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test create location
+func TestCreateLocation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, map[string]interface{}{"type": "site", "name": "Jakarta"}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to bind data", 1, map[string]interface{}{"name": 1}, http.StatusBadRequest, "failed to bind data"},
+		{"name is required", 1, map[string]interface{}{"type": "site"}, http.StatusBadRequest, "name is required"},
+		{"invalid type", 1, map[string]interface{}{"type": "floor", "name": "Floor 3"}, http.StatusBadRequest, "type must be site || building || room"},
+		{"site with parent", 1, map[string]interface{}{"type": "site", "name": "Jakarta", "id_parent": 1}, http.StatusBadRequest, "site cannot have a parent location"},
+		{"parent not found", 1, map[string]interface{}{"type": "room", "name": "Room 301", "id_parent": 100}, http.StatusBadRequest, "parent location not found"},
+		{"room under a site", 1, map[string]interface{}{"type": "room", "name": "Room 301", "id_parent": 1}, http.StatusBadRequest, "parent of a room must be a building"},
+		{"failed to create location", 1, map[string]interface{}{"type": "site", "name": "error"}, http.StatusBadRequest, "failed to create location"},
+		{"success create site", 1, map[string]interface{}{"type": "site", "name": "Jakarta"}, http.StatusOK, "success create location"},
+		{"success create room", 1, map[string]interface{}{"type": "room", "name": "Room 301", "id_parent": 2}, http.StatusOK, "success create location"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/locations")
+
+			locationController := NewLocationController(mockLocationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(locationController.CreateLocationController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test get location tree
+func TestGetLocations(t *testing.T) {
+	t.Run("success get location tree", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/locations")
+
+		locationController := NewLocationController(mockLocationRepository{})
+
+		type TreeResponses struct {
+			Code    int                 `json:"code"`
+			Message string              `json:"message"`
+			Data    []entities.Location `json:"data"`
+		}
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(locationController.GetLocationsController())(context)) {
+			var response TreeResponses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, "success get all locations", response.Message)
+			assert.Equal(t, 1, len(response.Data))
+			assert.Equal(t, "Jakarta", response.Data[0].Name)
+			assert.Equal(t, 1, len(response.Data[0].Children))
+			assert.Equal(t, 2, len(response.Data[0].Children[0].Children))
+			assert.Equal(t, "Jakarta / Tower A / Room 301", response.Data[0].Children[0].Children[0].Path)
+		}
+	})
+}
+
+// 3. test delete location
+func TestDeleteLocation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		idRole     int
+		idLocation string
+		code       int
+		message    string
+	}{
+		{"unauthorized access", 3, "1", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"location still in use", 1, "1", http.StatusBadRequest, "location is still in use"},
+		{"success delete location", 1, "4", http.StatusOK, "delete success"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/locations/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idLocation)
+
+			locationController := NewLocationController(mockLocationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(locationController.DeleteLocationController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 4. test move asset
+func TestMoveAsset(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		idAsset string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "1", map[string]interface{}{"id_location": 3}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", map[string]interface{}{"id_location": 3}, http.StatusBadRequest, "failed to convert id"},
+		{"id_location is required", 1, "1", map[string]interface{}{"notes": "moved"}, http.StatusBadRequest, "id_location is required"},
+		{"already in location", 1, "1", map[string]interface{}{"id_location": 4}, http.StatusBadRequest, "asset is already in this location"},
+		{"success move asset", 1, "1", map[string]interface{}{"id_location": 3, "notes": "moved for the townhall"}, http.StatusOK, "success move asset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/:id/location")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idAsset)
+
+			locationController := NewLocationController(mockLocationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(locationController.MoveAssetController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 5. test get location history of an asset
+func TestGetAssetMoves(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idAsset string
+		code    int
+		message string
+	}{
+		{"failed to fetch data", "100", http.StatusBadRequest, "failed to fetch data"},
+		{"success get location history", "1", http.StatusOK, "success get location history"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/:id/location/history")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idAsset)
+
+			locationController := NewLocationController(mockLocationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(locationController.GetAssetMovesController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+type mockLocationRepository struct{}
+
+var mockLocations = []entities.Location{
+	{Id: 1, Type: "site", Name: "Jakarta", Path: "Jakarta"},
+	{Id: 2, Id_parent: 1, Type: "building", Name: "Tower A", Path: "Jakarta / Tower A"},
+	{Id: 3, Id_parent: 2, Type: "room", Name: "Room 301", Path: "Jakarta / Tower A / Room 301"},
+	{Id: 4, Id_parent: 2, Type: "room", Name: "Room 302", Path: "Jakarta / Tower A / Room 302"},
+}
+
+func (m mockLocationRepository) Create(location entities.Location) error {
+	if location.Name == "error" {
+		return fmt.Errorf("error")
+	}
+	return nil
+}
+
+func (m mockLocationRepository) Get() ([]entities.Location, error) {
+	return mockLocations, nil
+}
+
+func (m mockLocationRepository) GetById(id int) (entities.Location, error) {
+	for _, location := range mockLocations {
+		if location.Id == id {
+			return location, nil
+		}
+	}
+	return entities.Location{}, fmt.Errorf("id not found")
+}
+
+func (m mockLocationRepository) Update(location entities.Location, id int) error {
+	if id == 100 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
+
+func (m mockLocationRepository) Delete(id int) error {
+	if id == 1 {
+		return fmt.Errorf("location is still in use")
+	}
+	return nil
+}
+
+func (m mockLocationRepository) MoveAsset(move entities.AssetLocationMove) error {
+	if move.To_location == 4 {
+		return fmt.Errorf("asset is already in this location")
+	}
+	return nil
+}
+
+func (m mockLocationRepository) GetAssetMoves(idAsset int) ([]entities.AssetLocationMove, error) {
+	if idAsset == 100 {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.AssetLocationMove{{Id: 1, Id_asset: idAsset, To_location: 3, To_name: "Room 301"}}, nil
+}
@@ -0,0 +1,5 @@
+package maintenance
+
+type CloseRequestFormat struct {
+	Notes string `json:"notes" form:"notes"`
+}
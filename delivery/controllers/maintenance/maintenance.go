@@ -0,0 +1,68 @@
+package maintenance
+
+import (
+	"net/http"
+	"strconv"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	maintenanceRepo "sirclo/project/capstone/repository/maintenance"
+
+	"github.com/labstack/echo/v4"
+)
+
+type MaintenanceController struct {
+	repository maintenanceRepo.MaintenanceRepo
+}
+
+func NewMaintenanceController(maintenance maintenanceRepo.MaintenanceRepo) *MaintenanceController {
+	return &MaintenanceController{repository: maintenance}
+}
+
+// 1. get maintenance records controller
+func (mc MaintenanceController) GetMaintenanceController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		status := c.QueryParam("status")
+		if status != "" && status != "open" && status != "closed" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "status must be open || closed"))
+		}
+
+		records, err := mc.repository.Get(status)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get maintenance", records))
+	}
+}
+
+// 2. close maintenance record controller
+func (mc MaintenanceController) CloseMaintenanceController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		maintenanceId, errConv := strconv.Atoi(c.Param("id"))
+		if errConv != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var closeRequest CloseRequestFormat
+		if errBind := c.Bind(&closeRequest); errBind != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if err := mc.repository.Close(maintenanceId, closeRequest.Notes); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success close maintenance"))
+	}
+}
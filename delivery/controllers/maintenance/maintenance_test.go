@@ -0,0 +1,119 @@
+package maintenance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test get maintenance records
+func TestGetMaintenance(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		query   string
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "/", http.StatusUnauthorized, "unauthorized access"},
+		{"invalid status", 1, "/?status=pending", http.StatusBadRequest, "status must be open || closed"},
+		{"failed to fetch data", 1, "/?status=closed", http.StatusBadRequest, "failed to fetch data"},
+		{"success get open maintenance", 1, "/?status=open", http.StatusOK, "success get maintenance"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/maintenance")
+
+			maintenanceController := NewMaintenanceController(mockMaintenanceRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(maintenanceController.GetMaintenanceController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test close maintenance record
+func TestCloseMaintenance(t *testing.T) {
+	testCases := []struct {
+		name          string
+		idRole        int
+		idMaintenance string
+		code          int
+		message       string
+	}{
+		{"unauthorized access", 3, "1", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"already closed", 1, "2", http.StatusBadRequest, "maintenance not found or already closed"},
+		{"success close maintenance", 1, "1", http.StatusOK, "success close maintenance"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"notes": "screen replaced"})
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/maintenance/:id/close")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idMaintenance)
+
+			maintenanceController := NewMaintenanceController(mockMaintenanceRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(maintenanceController.CloseMaintenanceController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+type mockMaintenanceRepository struct{}
+
+func (m mockMaintenanceRepository) Get(status string) ([]entities.AssetMaintenance, error) {
+	if status == "closed" {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.AssetMaintenance{{Id: 1, Id_asset: 1, Status: "open"}}, nil
+}
+
+func (m mockMaintenanceRepository) Close(id int, notes string) error {
+	if id == 2 {
+		return fmt.Errorf("maintenance not found or already closed")
+	}
+	return nil
+}
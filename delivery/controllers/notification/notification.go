@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"net/http"
+	"strconv"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	notificationRepo "sirclo/project/capstone/repository/notification"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationController struct {
+	repository notificationRepo.NotificationRepo
+}
+
+func NewNotificationController(notification notificationRepo.NotificationRepo) *NotificationController {
+	return &NotificationController{repository: notification}
+}
+
+// 1. get notifications of the logged in user controller
+func (nc NotificationController) GetNotificationsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idUser, err := middlewares.GetId(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		unread := c.QueryParam("unread") == "true"
+
+		notifications, err := nc.repository.Get(idUser, unread)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get notifications", notifications))
+	}
+}
+
+// 2. mark notification as read controller
+func (nc NotificationController) MarkReadController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idUser, err := middlewares.GetId(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idNotification, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		if err := nc.repository.MarkRead(idNotification, idUser); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success mark notification as read"))
+	}
+}
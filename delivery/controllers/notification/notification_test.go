@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test get notifications
+func TestGetNotifications(t *testing.T) {
+	testCases := []struct {
+		name    string
+		unread  string
+		code    int
+		message string
+	}{
+		{"failed to fetch data", "true", http.StatusBadRequest, "failed to fetch data"},
+		{"success get notifications", "", http.StatusOK, "success get notifications"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodGet, "/notifications?unread="+tc.unread, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/notifications")
+
+			notificationController := NewNotificationController(mockNotificationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(notificationController.GetNotificationsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test mark notification as read
+func TestMarkRead(t *testing.T) {
+	testCases := []struct {
+		name    string
+		id      string
+		code    int
+		message string
+	}{
+		{"failed to convert id", "a", http.StatusBadRequest, "failed to convert id"},
+		{"not found", "2", http.StatusBadRequest, "notification not found or already read"},
+		{"success mark read", "1", http.StatusOK, "success mark notification as read"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/notifications/:id/read")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			notificationController := NewNotificationController(mockNotificationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(notificationController.MarkReadController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+type mockNotificationRepository struct{}
+
+func (m mockNotificationRepository) Get(idUser int, unread bool) ([]entities.Notification, error) {
+	if unread {
+		return nil, fmt.Errorf("failed to fetch data")
+	}
+	return []entities.Notification{{Id: 1, Id_user: idUser, Type: "waitlist_hold", Message: "a unit is held for you"}}, nil
+}
+
+func (m mockNotificationRepository) MarkRead(id, idUser int) error {
+	if id != 1 {
+		return fmt.Errorf("notification not found or already read")
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+package offboarding
+
+type WriteOffFormat struct {
+	Reason string `json:"reason" form:"reason"`
+}
@@ -0,0 +1,132 @@
+package offboarding
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	offboardingRepo "sirclo/project/capstone/repository/offboarding"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OffboardingController struct {
+	repository offboardingRepo.OffboardingRepo
+}
+
+func NewOffboardingController(offboarding offboardingRepo.OffboardingRepo) *OffboardingController {
+	return &OffboardingController{repository: offboarding}
+}
+
+// 1. get holdings controller, the assets a user holds
+func (oc OffboardingController) GetHoldingsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idUser, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		// only the admin looks at the assets of another user
+		if id, _ := middlewares.GetId(c); idRole != 1 && id != idUser {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		holdings, err := oc.repository.GetHoldings(idUser)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get holdings", holdings))
+	}
+}
+
+// 2. start offboarding controller, a return is requested for every asset the user holds
+func (oc OffboardingController) StartOffboardingController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idAdmin, _ := middlewares.GetId(c)
+
+		// get id from param
+		idUser, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		id, err := oc.repository.Start(idUser, idAdmin)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success start offboarding", map[string]int{"id": id}))
+	}
+}
+
+// 3. get offboarding controller, the checklist of the latest offboarding of a user
+func (oc OffboardingController) GetOffboardingController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idUser, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		// only the admin looks at the assets of another user
+		if id, _ := middlewares.GetId(c); idRole != 1 && id != idUser {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		offboarding, err := oc.repository.Get(idUser)
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, response.NotFound("not found", "offboarding not found"))
+		}
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get offboarding", offboarding))
+	}
+}
+
+// 4. write off controller, an item of the checklist that will not come back
+func (oc OffboardingController) WriteOffController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idAdmin, _ := middlewares.GetId(c)
+
+		// get id from param
+		idItem, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var writeOffReq WriteOffFormat
+		if err := c.Bind(&writeOffReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if strings.TrimSpace(writeOffReq.Reason) == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "reason is required"))
+		}
+
+		if err := oc.repository.WriteOff(idItem, idAdmin, writeOffReq.Reason); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success write off item"))
+	}
+}
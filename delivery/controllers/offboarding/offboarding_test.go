@@ -0,0 +1,236 @@
+package offboarding
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test get holdings
+func TestGetHoldings(t *testing.T) {
+	testCases := []struct {
+		name    string
+		repo    mockOffboardingRepository
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"failed to convert id", mockOffboardingRepository{}, 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"holdings of another user", mockOffboardingRepository{}, 2, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to fetch data", mockOffboardingRepository{fail: true}, 1, "2", http.StatusBadRequest, "failed to fetch data"},
+		{"success admin", mockOffboardingRepository{}, 1, "2", http.StatusOK, "success get holdings"},
+		{"success own holdings", mockOffboardingRepository{}, 2, "1", http.StatusOK, "success get holdings"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/users/:id/holdings")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			offboardingController := NewOffboardingController(tc.repo)
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(offboardingController.GetHoldingsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test start offboarding
+func TestStartOffboarding(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"already in progress", 1, "3", http.StatusBadRequest, "offboarding already in progress"},
+		{"holds no assets", 1, "4", http.StatusBadRequest, "user holds no assets"},
+		{"success start offboarding", 1, "2", http.StatusOK, "success start offboarding"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/users/:id/offboarding")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			offboardingController := NewOffboardingController(mockOffboardingRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(offboardingController.StartOffboardingController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 3. test get offboarding
+func TestGetOffboarding(t *testing.T) {
+	testCases := []struct {
+		name    string
+		repo    mockOffboardingRepository
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"failed to convert id", mockOffboardingRepository{}, 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"offboarding of another user", mockOffboardingRepository{}, 3, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"offboarding not found", mockOffboardingRepository{}, 1, "4", http.StatusNotFound, "offboarding not found"},
+		{"failed to fetch data", mockOffboardingRepository{fail: true}, 1, "2", http.StatusBadRequest, "failed to fetch data"},
+		{"success admin", mockOffboardingRepository{}, 1, "2", http.StatusOK, "success get offboarding"},
+		{"success own offboarding", mockOffboardingRepository{}, 2, "1", http.StatusOK, "success get offboarding"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/users/:id/offboarding")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			offboardingController := NewOffboardingController(tc.repo)
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(offboardingController.GetOffboardingController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 4. test write off an item
+func TestWriteOff(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "1", map[string]interface{}{"reason": "lost"}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", map[string]interface{}{"reason": "lost"}, http.StatusBadRequest, "failed to convert id"},
+		{"failed to bind data", 1, "1", map[string]interface{}{"reason": 1}, http.StatusBadRequest, "failed to bind data"},
+		{"reason is required", 1, "1", map[string]interface{}{"reason": " "}, http.StatusBadRequest, "reason is required"},
+		{"already returned", 1, "2", map[string]interface{}{"reason": "lost"}, http.StatusBadRequest, "item is already returned or written off"},
+		{"success write off", 1, "1", map[string]interface{}{"reason": "lost"}, http.StatusOK, "success write off item"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/offboarding-items/:id/write-off")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			offboardingController := NewOffboardingController(mockOffboardingRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(offboardingController.WriteOffController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// user 3 is being offboarded, user 4 holds nothing and was never offboarded
+type mockOffboardingRepository struct {
+	fail bool
+}
+
+func (m mockOffboardingRepository) GetHoldings(idUser int) ([]entities.Holding, error) {
+	if m.fail {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.Holding{{Id_request: 1, Id_asset: 1, Asset_name: "laptop", Quantity: 1, Id_status: 6}}, nil
+}
+
+func (m mockOffboardingRepository) Start(idUser, idAdmin int) (int, error) {
+	switch idUser {
+	case 3:
+		return 0, fmt.Errorf("offboarding already in progress")
+	case 4:
+		return 0, fmt.Errorf("user holds no assets")
+	}
+	return 1, nil
+}
+
+func (m mockOffboardingRepository) Get(idUser int) (entities.Offboarding, error) {
+	if m.fail {
+		return entities.Offboarding{}, fmt.Errorf("error")
+	}
+	if idUser == 4 {
+		return entities.Offboarding{}, sql.ErrNoRows
+	}
+	return entities.Offboarding{Id: 1, Id_user: idUser, Pending: 1, Items: []entities.OffboardingItem{{Id: 1, Id_request: 1, Status: "pending"}}}, nil
+}
+
+func (m mockOffboardingRepository) WriteOff(idItem, idAdmin int, reason string) error {
+	if idItem != 1 {
+		return fmt.Errorf("item is already returned or written off")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"net/http"
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	reportRepo "sirclo/project/capstone/repository/report"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReportController struct {
+	repository reportRepo.ReportRepo
+}
+
+func NewReportController(report reportRepo.ReportRepo) *ReportController {
+	return &ReportController{repository: report}
+}
+
+// 1. get valuation report, book value of assets aggregated by category as of a date
+func (rc ReportController) GetValuationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || (idRole != 1 && idRole != 3) {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		asOfStr := c.QueryParam("as_of")
+		if asOfStr == "" {
+			asOfStr = time.Now().Format("2006-01-02")
+		}
+		asOf, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "as_of must be formatted as yyyy-mm-dd"))
+		}
+
+		assets, err := rc.repository.GetValuationAssets(asOfStr)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get valuation report", valuationReport(assets, asOf)))
+	}
+}
+
+// valuationReport computes the book value of every asset, assets are expected to be sorted by category
+func valuationReport(assets []entities.ValuationAsset, asOf time.Time) entities.ValuationReport {
+	report := entities.ValuationReport{
+		As_of:      asOf.Format("2006-01-02"),
+		Categories: []entities.CategoryValuation{},
+	}
+
+	var category *entities.CategoryValuation
+	for _, asset := range assets {
+		if category == nil || category.Id_category != asset.Id_category {
+			report.Categories = append(report.Categories, entities.CategoryValuation{
+				Id_category:         asset.Id_category,
+				Category:            asset.Category,
+				Depreciation_method: asset.Depreciation_method,
+				Useful_life_months:  asset.Useful_life_months,
+				Salvage_rate:        asset.Salvage_rate,
+				Assets:              []entities.AssetValuation{},
+			})
+			category = &report.Categories[len(report.Categories)-1]
+		}
+		category.Total_assets++
+
+		// without purchase data the asset cannot be valued
+		purchaseDate, err := time.Parse("2006-01-02", asset.Purchase_date)
+		if err != nil || asset.Purchase_price <= 0 {
+			category.Unvalued_assets++
+			continue
+		}
+
+		policy := util.DepreciationPolicy{
+			Method:           asset.Depreciation_method,
+			UsefulLifeMonths: asset.Useful_life_months,
+			SalvageRate:      asset.Salvage_rate,
+			DecliningFactor:  asset.Declining_factor,
+		}
+		unitValue := util.BookValue(asset.Purchase_price, purchaseDate, asOf, policy)
+
+		valuation := entities.AssetValuation{
+			Id:            asset.Id,
+			Name:          asset.Name,
+			Quantity:      asset.Quantity,
+			Purchase_date: asset.Purchase_date,
+			Unit_cost:     asset.Purchase_price,
+			Cost:          asset.Purchase_price * float64(asset.Quantity),
+			Book_value:    unitValue * float64(asset.Quantity),
+		}
+		valuation.Accumulated_depreciation = util.RoundMoney(valuation.Cost - valuation.Book_value)
+
+		category.Assets = append(category.Assets, valuation)
+		category.Cost += valuation.Cost
+		category.Book_value += valuation.Book_value
+		category.Accumulated_depreciation += valuation.Accumulated_depreciation
+	}
+
+	for i := range report.Categories {
+		category := &report.Categories[i]
+		category.Cost = util.RoundMoney(category.Cost)
+		category.Book_value = util.RoundMoney(category.Book_value)
+		category.Accumulated_depreciation = util.RoundMoney(category.Accumulated_depreciation)
+
+		report.Cost += category.Cost
+		report.Book_value += category.Book_value
+		report.Accumulated_depreciation += category.Accumulated_depreciation
+	}
+	report.Cost = util.RoundMoney(report.Cost)
+	report.Book_value = util.RoundMoney(report.Book_value)
+	report.Accumulated_depreciation = util.RoundMoney(report.Accumulated_depreciation)
+
+	return report
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// 1. test get valuation report
+func TestGetValuation(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		query   string
+		code    int
+		message string
+	}{
+		{"unauthorized employee", 2, "/", http.StatusUnauthorized, "unauthorized access"},
+		{"invalid as_of", 1, "/?as_of=01-01-2023", http.StatusBadRequest, "as_of must be formatted as yyyy-mm-dd"},
+		{"failed to fetch data", 1, "/?as_of=2000-01-01", http.StatusBadRequest, "failed to fetch data"},
+		{"success admin", 1, "/?as_of=2023-01-01", http.StatusOK, "success get valuation report"},
+		{"success manager", 3, "/?as_of=2023-01-01", http.StatusOK, "success get valuation report"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/reports/valuation")
+
+			reportController := NewReportController(mockReportRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reportController.GetValuationController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+
+	t.Run("book value by category", func(t *testing.T) {
+		e := echo.New()
+		token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+		req := httptest.NewRequest(http.MethodGet, "/?as_of=2023-01-01", nil)
+		req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+		res := httptest.NewRecorder()
+		context := e.NewContext(req, res)
+		context.SetPath("/reports/valuation")
+
+		reportController := NewReportController(mockReportRepository{})
+
+		type Responses struct {
+			Code int                      `json:"code"`
+			Data entities.ValuationReport `json:"data"`
+		}
+
+		if assert.NoError(t, middlewares.JWTMiddleware()(reportController.GetValuationController())(context)) {
+			var response Responses
+			json.Unmarshal(res.Body.Bytes(), &response)
+
+			report := response.Data
+			assert.Equal(t, "2023-01-01", report.As_of)
+			assert.Equal(t, 2, len(report.Categories))
+
+			// straight line: 12.000.000 - (12.000.000 - 10%) * 12 / 48 = 9.300.000 per unit, 2 units
+			laptop := report.Categories[0]
+			assert.Equal(t, 2, laptop.Total_assets)
+			assert.Equal(t, 1, laptop.Unvalued_assets)
+			assert.Equal(t, 24000000.0, laptop.Cost)
+			assert.Equal(t, 18600000.0, laptop.Book_value)
+			assert.Equal(t, 5400000.0, laptop.Accumulated_depreciation)
+
+			// double declining balance: 1.000.000 * (1 - 2 / 24) ^ 12
+			projector := report.Categories[1]
+			assert.InDelta(t, 351996.0, projector.Assets[0].Book_value, 1)
+
+			// end of useful life, only the salvage value is left
+			assert.Equal(t, 50000.0, projector.Assets[1].Book_value)
+
+			assert.InDelta(t, laptop.Book_value+projector.Book_value, report.Book_value, 0.01)
+		}
+	})
+}
+
+type mockReportRepository struct{}
+
+func (m mockReportRepository) GetValuationAssets(asOf string) ([]entities.ValuationAsset, error) {
+	if asOf == "2000-01-01" {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.ValuationAsset{
+		{Id: 1, Name: "laptop", Quantity: 2, Purchase_date: "2022-01-01", Purchase_price: 12000000, Id_category: 1, Category: "laptop",
+			Depreciation_method: "straight_line", Useful_life_months: 48, Salvage_rate: 0.1},
+		{Id: 2, Name: "old laptop", Quantity: 1, Id_category: 1, Category: "laptop",
+			Depreciation_method: "straight_line", Useful_life_months: 48, Salvage_rate: 0.1},
+		{Id: 3, Name: "projector", Quantity: 1, Purchase_date: "2022-01-01", Purchase_price: 1000000, Id_category: 2, Category: "projector",
+			Depreciation_method: "declining_balance", Useful_life_months: 24, Declining_factor: 2},
+		{Id: 4, Name: "old projector", Quantity: 1, Purchase_date: "2019-01-01", Purchase_price: 1000000, Id_category: 2, Category: "projector",
+			Depreciation_method: "declining_balance", Useful_life_months: 24, Salvage_rate: 0.05, Declining_factor: 2},
+	}, nil
+}
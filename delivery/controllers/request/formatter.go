@@ -4,7 +4,63 @@ type RequestFormat struct {
 	Id_user      int    `json:"id_user" form:"id_user"`
 	Id_asset     int    `json:"id_asset" form:"id_asset"`
 	Id_status    int    `json:"id_status" form:"id_status"`
+	Quantity     int    `json:"quantity" form:"quantity"`
 	Request_date string `json:"request_date" form:"request_date"`
 	Return_date  string `json:"return_date" form:"return_date"`
 	Descrition   string `json:"description" form:"description"`
 }
+
+type DeskRequestFormat struct {
+	Tag             string `json:"tag" form:"tag"`
+	Badge           string `json:"badge" form:"badge"`
+	Condition_notes string `json:"condition_notes" form:"condition_notes"`
+	Condition_grade string `json:"condition_grade" form:"condition_grade"`
+	Damage_notes    string `json:"damage_notes" form:"damage_notes"`
+	Quantity        int    `json:"quantity" form:"quantity"`
+}
+
+type KitRequestFormat struct {
+	Id_user     int    `json:"id_user" form:"id_user"`
+	Return_date string `json:"return_date" form:"return_date"`
+	Description string `json:"description" form:"description"`
+}
+
+type KitStatusFormat struct {
+	Id_status int `json:"id_status" form:"id_status"`
+}
+
+type KitReturnFormat struct {
+	Items []KitReturnItemFormat `json:"items" form:"items"`
+}
+
+type KitReturnItemFormat struct {
+	Id_request      int    `json:"id_request" form:"id_request"`
+	Condition_grade string `json:"condition_grade" form:"condition_grade"`
+	Damage_notes    string `json:"damage_notes" form:"damage_notes"`
+}
+
+type ExtensionFormat struct {
+	Return_date string `json:"return_date" form:"return_date"`
+	Reason      string `json:"reason" form:"reason"`
+}
+
+type ExtensionDecisionFormat struct {
+	Status string `json:"status" form:"status"`
+}
+
+type BulkStatusFormat struct {
+	Ids       []int  `json:"ids" form:"ids"`
+	Id_status int    `json:"id_status" form:"id_status"`
+	Reason    string `json:"reason" form:"reason"`
+}
+
+type BulkResult struct {
+	Id      int    `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type TransferFormat struct {
+	Id_user         int    `json:"id_user" form:"id_user"`
+	Condition_notes string `json:"condition_notes" form:"condition_notes"`
+}
@@ -1,18 +1,81 @@
 package request
 
 import (
+	"database/sql"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	response "sirclo/project/capstone/delivery/common"
 	middlewares "sirclo/project/capstone/delivery/middleware"
 	"sirclo/project/capstone/entities"
 	requestRepo "sirclo/project/capstone/repository/request"
+	"sirclo/project/capstone/util"
+	"sirclo/project/capstone/util/pagination"
 
 	"github.com/labstack/echo/v4"
 )
 
+// condition grade of a returned asset, a damaged asset goes to maintenance
+var conditionGrades = []string{"good", "fair", "poor", "damaged"}
+
+// maximum photos of a returned asset
+const maxReturnPhotos = 5
+
+func checkGrade(grade string) error {
+	for _, g := range conditionGrades {
+		if g == grade {
+			return nil
+		}
+	}
+	return fmt.Errorf("condition_grade must be good || fair || poor || damaged")
+}
+
+// uploadReturnPhotos stores the photos of the returned asset sent as multipart "photos"
+func uploadReturnPhotos(c echo.Context, prefix string) ([]entities.ReturnPhoto, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		// no multipart body, no photo
+		return nil, nil
+	}
+
+	files := form.File["photos"]
+	if len(files) > maxReturnPhotos {
+		return nil, fmt.Errorf("maximum %d photos allowed", maxReturnPhotos)
+	}
+
+	var photos []entities.ReturnPhoto
+	for i, file := range files {
+		if err := util.CheckSize(file.Size); err != nil {
+			return nil, fmt.Errorf("photo size too big")
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		image, err := util.ProcessImage(src)
+		src.Close()
+		if err != nil {
+			log.Println(err)
+			return nil, fmt.Errorf("photo format not allowed")
+		}
+
+		fileName := fmt.Sprintf("return_pic/%s_%d_%d", prefix, time.Now().Unix(), i)
+		urls, err := util.UploadPhotoToS3(image, fileName)
+		if err != nil {
+			log.Println(err)
+			return nil, fmt.Errorf("failed to upload photo")
+		}
+		photos = append(photos, entities.ReturnPhoto{Url: urls.Original, Url_thumbnail: urls.Thumbnail})
+	}
+	return photos, nil
+}
+
 type RequestController struct {
 	repository requestRepo.RequestRepo
 }
@@ -21,6 +84,27 @@ func NewRequestController(request requestRepo.RequestRepo) *RequestController {
 	return &RequestController{repository: request}
 }
 
+// RequestAccess checks the request of the id param belongs to the employee or to the division of the manager
+func (rc RequestController) RequestAccess() echo.MiddlewareFunc {
+	return middlewares.ResourceAccess(rc.repository.GetOwnership)
+}
+
+// RequestDecisionAccess checks the request of the id param like RequestAccess, and lets a delegate or the fallback
+// approver decide on it
+func (rc RequestController) RequestDecisionAccess() echo.MiddlewareFunc {
+	return middlewares.DecisionAccess(rc.repository.GetOwnership)
+}
+
+// KitRequestAccess checks the kit request of the id param the same way as RequestAccess
+func (rc RequestController) KitRequestAccess() echo.MiddlewareFunc {
+	return middlewares.ResourceAccess(rc.repository.GetKitOwnership)
+}
+
+// ExtensionAccess checks the loan of the extension of the id param the same way as RequestAccess
+func (rc RequestController) ExtensionAccess() echo.MiddlewareFunc {
+	return middlewares.ResourceAccess(rc.repository.GetExtensionOwnership)
+}
+
 // 1. create request
 func (rc RequestController) CreateRequestEmployee() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -36,6 +120,23 @@ func (rc RequestController) CreateRequestEmployee() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
 		}
 
+		// a request borrows one unit unless more are asked
+		if requestReq.Quantity == 0 {
+			requestReq.Quantity = 1
+		}
+		if requestReq.Quantity < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "quantity must be greater than 0"))
+		}
+
+		availQty, err := rc.repository.GetAssetAvailQty(requestReq.Id_asset, requestReq.Return_date)
+		if err != nil {
+			log.Println(err)
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to create request"))
+		}
+		if availQty < requestReq.Quantity {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "not enough units available"))
+		}
+
 		var request entities.Request
 
 		if idRole == 2 {
@@ -44,6 +145,7 @@ func (rc RequestController) CreateRequestEmployee() echo.HandlerFunc {
 				Id_user:     idUser,
 				Id_asset:    requestReq.Id_asset,
 				Id_status:   1,
+				Quantity:    requestReq.Quantity,
 				Return_date: requestReq.Return_date,
 				Description: requestReq.Descrition,
 			}
@@ -54,6 +156,7 @@ func (rc RequestController) CreateRequestEmployee() echo.HandlerFunc {
 				Id_user:     requestReq.Id_user,
 				Id_asset:    requestReq.Id_asset,
 				Id_status:   2,
+				Quantity:    requestReq.Quantity,
 				Return_date: requestReq.Return_date,
 				Description: requestReq.Descrition,
 			}
@@ -121,6 +224,7 @@ func (rc RequestController) UpdateRequestStatus() echo.HandlerFunc {
 			6: diterima
 			7: minta dikembalikan
 			8: berhasil dikembalikan
+			9: dibatalkan
 		*/
 
 		/*
@@ -130,70 +234,239 @@ func (rc RequestController) UpdateRequestStatus() echo.HandlerFunc {
 			3: manager
 		*/
 
-		switch idRole {
-		// admin
-		case 1:
-			if request.Id_status != 2 {
-				if request.Id_status != 5 {
-					if request.Id_status != 6 {
-						if request.Id_status != 7 {
-							return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 2 || 5 || 6 || 7"))
+		// an active delegate decides as the manager who delegated the approval to them, and the fallback approver
+		// as the manager of a request escalated to them, they can do nothing else on the request
+		idUser, _ := middlewares.GetId(c)
+		idOnBehalf := 0
+		if delegation, ok := c.Get("delegation").(entities.Ownership); ok {
+			if !middlewares.CanDecide(delegation, request.Id_status) {
+				return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+			}
+			idOnBehalf = delegation.Id_delegator
+		} else {
+			switch idRole {
+			// admin
+			case 1:
+				if request.Id_status != 2 {
+					if request.Id_status != 5 {
+						if request.Id_status != 6 {
+							if request.Id_status != 7 {
+								return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 2 || 5 || 6 || 7"))
+							}
 						}
 					}
 				}
+			// employee
+			case 2:
+				if request.Id_status != 8 {
+					if request.Id_status != 9 {
+						return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 8 || 9"))
+					}
+				}
+			// manager
+			case 3:
+				if request.Id_status != 3 {
+					if request.Id_status != 4 {
+						return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 3 || 4"))
+					}
+				}
 			}
-		// employee
-		case 2:
-			if request.Id_status != 8 {
-				return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 8"))
+		}
+		// if status == 9 dibatalkan, the employee withdraws their own request before it is handed over
+		if request.Id_status == 9 {
+			if err := rc.repository.Cancel(idRequest, idUser); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
 			}
-		// manager
-		case 3:
-			if request.Id_status != 3 {
-				if request.Id_status != 4 {
-					return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 3 || 4"))
-				}
+			return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update request"))
+		}
+
+		// if status == 8 berhasil dikembalikan, the condition of the asset is assessed on return
+		if request.Id_status == 8 {
+			if err := checkGrade(request.Condition_grade); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+			}
+
+			photos, err := uploadReturnPhotos(c, "request_"+strconv.Itoa(idRequest))
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+			}
+
+			// quantity is the number of units returned now, every unit left when not given
+			if request.Quantity < 0 {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "quantity must be greater than 0"))
 			}
+
+			// the condition is only assessed by an admin, an employee returning their asset is not an assessor
+			assessment := entities.ReturnAssessment{
+				Grade:        request.Condition_grade,
+				Quantity:     request.Quantity,
+				Damage_notes: request.Damage_notes,
+				Photos:       photos,
+			}
+			if idRole == 1 {
+				assessment.Id_assessor = idUser
+			}
+
+			if err := rc.repository.Return(idRequest, assessment); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+			}
+			return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update request"))
 		}
-		if request.Id_status == 8 || request.Id_status == 4 || request.Id_status == 5 {
-			request.Return_date = "0000-00-00"
+
+		if err := rc.changeStatus(idRequest, idUser, idOnBehalf, request); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
 		}
 
-		// update request based on id to database
-		errUpdate := rc.repository.Update(request, idRequest)
-		if errUpdate != nil {
-			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", errUpdate.Error()))
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update request"))
+	}
+}
+
+// changeStatus moves a request to a status set by the admin or the manager, for a single request and in bulk,
+// and records the decision with the manager a delegate decided for
+func (rc RequestController) changeStatus(idRequest, idUser, idOnBehalf int, request entities.Request) error {
+	if err := rc.applyStatus(idRequest, idUser, request); err != nil {
+		return err
+	}
+
+	// the decision is already saved, a history that fails to record does not undo it
+	event := entities.RequestEvent{Id_request: idRequest, Id_user: idUser, Id_on_behalf: idOnBehalf, Id_status: request.Id_status}
+	if err := rc.repository.AddEvent(event); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+// applyStatus saves the status of a request, with the stock leaving on hand over
+func (rc RequestController) applyStatus(idRequest, idUser int, request entities.Request) error {
+	// if status == 4 || 5 ditolak, the reason is required and kept as a comment on the request
+	if request.Id_status == 4 || request.Id_status == 5 {
+		reason := strings.TrimSpace(request.Reason)
+		if reason == "" {
+			return fmt.Errorf("reason is required to reject a request")
 		}
+		return rc.repository.Reject(idRequest, request.Id_status, idUser, reason)
+	}
 
-		// if status == 6 (diterima)
-		if request.Id_status == 6 {
-			// get current avail qty
-			availQty, _ := rc.repository.GetAvailQty(idRequest)
-			if availQty.Avail_quantity >= 0 {
-				qty := availQty.Avail_quantity - 1
-				err := rc.repository.UpdateAvailQty(qty, idRequest)
-				if err != nil {
-					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", errUpdate.Error()))
-				}
-			}
+	// if status == 6 (diterima), every unit of the request leaves the stock with the status
+	if request.Id_status == 6 {
+		return rc.repository.HandOver(idRequest, request.Return_date)
+	}
+
+	// the quantity requested is checked again against the stock when approved
+	if request.Id_status == 3 {
+		availQty, err := rc.repository.GetAvailQty(idRequest)
+		if err != nil {
+			return fmt.Errorf("id not found")
 		}
-		// if status == 8 berhasil dikembalikan
-		if request.Id_status == 8 {
-			// get current avail qty
-			availQty, _ := rc.repository.GetAvailQty(idRequest)
-			if availQty.Avail_quantity < availQty.Initial_quantity {
-				qty := availQty.Avail_quantity + 1
-				err := rc.repository.UpdateAvailQty(qty, idRequest)
-				if err != nil {
-					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", errUpdate.Error()))
-				}
+		if availQty.Avail_quantity < availQty.Quantity {
+			return fmt.Errorf("not enough units available")
+		}
+	}
+	// update request based on id to database
+	return rc.repository.Update(request, idRequest)
+}
+
+// bulkTransitions is the status each role can set in bulk, with the status the request has to be in
+var bulkTransitions = map[int]map[int]int{
+	1: {2: 1, 5: 1, 6: 3, 7: 6},
+	3: {3: 2, 4: 2},
+}
+
+// bulkStatusMessages is the error of a status the role can not set in bulk
+var bulkStatusMessages = map[int]string{
+	1: "id_status must be 2 || 5 || 6 || 7",
+	3: "id_status must be 3 || 4",
+}
+
+// maximum requests updated at once
+const maxBulkRequests = 100
+
+// update the status of many requests at once, every valid request is updated and each gets its own result
+func (rc RequestController) BulkUpdateRequestStatus() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || bulkTransitions[idRole] == nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		var bulkReq BulkStatusFormat
+		if err := c.Bind(&bulkReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if len(bulkReq.Ids) == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "ids is required"))
+		}
+		if len(bulkReq.Ids) > maxBulkRequests {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", fmt.Sprintf("at most %d requests at once", maxBulkRequests)))
+		}
+
+		fromStatus, ok := bulkTransitions[idRole][bulkReq.Id_status]
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", bulkStatusMessages[idRole]))
+		}
+		if (bulkReq.Id_status == 4 || bulkReq.Id_status == 5) && strings.TrimSpace(bulkReq.Reason) == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "reason is required to reject a request"))
+		}
+
+		request := entities.Request{Id_status: bulkReq.Id_status, Reason: bulkReq.Reason}
+
+		var results []BulkResult
+		succeeded := 0
+		seen := map[int]bool{}
+		for _, idRequest := range bulkReq.Ids {
+			if seen[idRequest] {
+				continue
+			}
+			seen[idRequest] = true
+
+			result := BulkResult{Id: idRequest, Status: "success"}
+			if err := rc.bulkChangeStatus(idRequest, idUser, idRole, fromStatus, request); err != nil {
+				result.Status = "failed"
+				result.Message = err.Error()
+			} else {
+				succeeded++
 			}
+			results = append(results, result)
 		}
 
-		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update request"))
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success bulk update request", map[string]interface{}{
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+			"results":   results,
+		}))
 	}
 }
 
+// bulkChangeStatus checks the caller can move one request of a bulk update, then moves it like a single request
+func (rc RequestController) bulkChangeStatus(idRequest, idUser, idRole, fromStatus int, request entities.Request) error {
+	ownership, err := rc.repository.GetOwnership(idRequest, idUser)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("id not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch data")
+	}
+	// a delegate or the fallback approver of another division decides as the manager of the division
+	idOnBehalf := 0
+	if !middlewares.CanAccess(idRole, idUser, ownership) {
+		if !middlewares.CanDecide(ownership, request.Id_status) {
+			return fmt.Errorf("unauthorized access")
+		}
+		idOnBehalf = ownership.Id_delegator
+	}
+
+	current, err := rc.repository.GetById(idRequest)
+	if err != nil {
+		return fmt.Errorf("failed to fetch data")
+	}
+	if current.Id_status != fromStatus {
+		return fmt.Errorf("request can not move from status %d to %d", current.Id_status, request.Id_status)
+	}
+
+	return rc.changeStatus(idRequest, idUser, idOnBehalf, request)
+}
+
 // 4. get requests
 func (rc *RequestController) GetRequestsController() echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -207,132 +480,527 @@ func (rc *RequestController) GetRequestsController() echo.HandlerFunc {
 		status := c.QueryParam("status")
 		filterDate := c.QueryParam("filter_date")
 		category := c.QueryParam("category")
-		limitStr := c.QueryParam("limit")
-		offsetStr := c.QueryParam("offset")
 
-		limit, err := strconv.Atoi(limitStr)
+		page, err := pagination.Parse(c.QueryParams())
 		if err != nil {
-			limit = 0
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
 		}
-
-		offset, err := strconv.Atoi(offsetStr)
-		if err != nil {
-			offset = 0
+		if page.Keyset {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "cursor is only supported on the request activity and history"))
 		}
 
+		idUser, _ := middlewares.GetId(c)
+
 		var requests []entities.RequestResponse
-		totalPage := 0
+		total := 0
 		switch idRole {
 		case 1:
-			requests, err = rc.repository.GetAdmin(returnDate, requestDate, status, filterDate, category, limit, offset)
-			if limit > 0 {
-				requestsTotPage, _ := rc.repository.GetAdmin(returnDate, requestDate, status, filterDate, category, 0, 0)
-				totalPage = (len(requestsTotPage) / limit) + 1
+			requests, err = rc.repository.GetAdmin(returnDate, requestDate, status, filterDate, category, page)
+			if err == nil {
+				total, err = rc.repository.CountAdmin(status, filterDate, category)
 			}
-		case 3:
-			requests, err = rc.repository.GetManager(returnDate, requestDate, status, filterDate, category, limit, offset)
-			if limit > 0 {
-				requestsTotPage, _ := rc.repository.GetManager(returnDate, requestDate, status, filterDate, category, 0, 0)
-
-				if len(requestsTotPage)%limit == 0 {
-					totalPage = (len(requestsTotPage) / limit)
-				} else {
-					totalPage = (len(requestsTotPage) / limit) + 1
-				}
+		// a delegate gets the requests of the divisions they approve for
+		case 2, 3:
+			requests, err = rc.repository.GetManager(idUser, returnDate, requestDate, status, filterDate, category, page)
+			if err == nil {
+				total, err = rc.repository.CountManager(idUser, status, filterDate, category)
 			}
 		}
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
 		}
-		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all request", map[string]interface{}{
-			"total_page": totalPage,
-			"data":       requests,
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all request", pagination.List{
+			Meta: pagination.NewMeta(total, page),
+			Data: requests,
 		}))
 	}
 }
 
+// employeeRequests lists the requests of the user asking, by offset with the count of their requests or, when a
+// cursor is asked, after the last request of the previous page
+func (rc RequestController) employeeRequests(c echo.Context, isHistory bool) error {
+	idUser, err := middlewares.GetId(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+	}
+
+	page, err := pagination.Parse(c.QueryParams())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+	}
+
+	requests, err := rc.repository.GetEmployee(idUser, isHistory, page)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+	}
+
+	if page.Keyset {
+		nextCursor := ""
+		if len(requests) > page.Limit {
+			requests = requests[:page.Limit]
+			last := requests[len(requests)-1]
+			nextCursor = pagination.Cursor{Id: last.Id}.Encode()
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all assets", pagination.CursorList{
+			Limit:       page.Limit,
+			Next_cursor: nextCursor,
+			Data:        requests,
+		}))
+	}
+
+	total, err := rc.repository.CountEmployee(idUser, isHistory)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+	}
+	return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all assets", pagination.List{
+		Meta: pagination.NewMeta(total, page),
+		Data: requests,
+	}))
+}
+
 // get request activity users
 func (rc RequestController) GetRequestActivityController() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		idUser, err := middlewares.GetId(c)
-		if err != nil {
+		return rc.employeeRequests(c, false)
+	}
+}
+
+// get request history users
+func (rc RequestController) GetRequestHistoryController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return rc.employeeRequests(c, true)
+	}
+}
+
+// desk check out, scan the asset tag and the user badge to hand over an approved request
+func (rc RequestController) CheckOutController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
 			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
 		}
+		idAdmin, _ := middlewares.GetId(c)
 
-		limitStr := c.QueryParam("limit")
-		offsetStr := c.QueryParam("offset")
+		var deskReq DeskRequestFormat
+		if err := c.Bind(&deskReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
 
-		limit, err := strconv.Atoi(limitStr)
+		if deskReq.Tag == "" || deskReq.Badge == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "tag and badge are required"))
+		}
+		idUser, err := util.ParseUserBadge(deskReq.Badge)
 		if err != nil {
-			limit = 0
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
 		}
 
-		offset, err := strconv.Atoi(offsetStr)
+		idRequest, err := rc.repository.CheckOut(deskReq.Tag, idUser, idAdmin, deskReq.Condition_notes)
 		if err != nil {
-			offset = 0
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
 		}
 
-		request, err := rc.repository.GetEmployee(idUser, false, limit, offset)
+		request, err := rc.repository.GetById(idRequest)
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
 		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success check out asset", request))
+	}
+}
 
-		totalPage := 0
-		if limit > 0 {
-			requestsTotPage, _ := rc.repository.GetEmployee(idUser, false, 0, 0)
+// desk check in, scan the asset tag (and the user badge when several users hold the asset) to return it
+func (rc RequestController) CheckInController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idAdmin, _ := middlewares.GetId(c)
 
-			if len(requestsTotPage)%limit == 0 {
-				totalPage = (len(requestsTotPage) / limit)
-			} else {
-				totalPage = (len(requestsTotPage) / limit) + 1
+		var deskReq DeskRequestFormat
+		if err := c.Bind(&deskReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if deskReq.Tag == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "tag is required"))
+		}
+		if err := checkGrade(deskReq.Condition_grade); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		idUser := 0
+		if deskReq.Badge != "" {
+			idUser, err = util.ParseUserBadge(deskReq.Badge)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
 			}
 		}
-		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all assets", map[string]interface{}{
-			"total_page": totalPage,
-			"data":       request,
-		}))
+
+		photos, err := uploadReturnPhotos(c, deskReq.Tag)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		// one unit is checked in per scan unless the quantity is given
+		if deskReq.Quantity == 0 {
+			deskReq.Quantity = 1
+		}
+		if deskReq.Quantity < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "quantity must be greater than 0"))
+		}
+
+		assessment := entities.ReturnAssessment{
+			Id_assessor:  idAdmin,
+			Grade:        deskReq.Condition_grade,
+			Quantity:     deskReq.Quantity,
+			Damage_notes: deskReq.Damage_notes,
+			Photos:       photos,
+		}
+
+		idRequest, err := rc.repository.CheckIn(deskReq.Tag, idUser, deskReq.Condition_notes, assessment)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		request, err := rc.repository.GetById(idRequest)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success check in asset", request))
 	}
 }
 
-// get request history users
-func (rc RequestController) GetRequestHistoryController() echo.HandlerFunc {
+// transfer an active loan to another employee, the loan is closed and a new one is handed over to the recipient
+func (rc RequestController) TransferRequestController() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		idUser, err := middlewares.GetId(c)
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idAdmin, _ := middlewares.GetId(c)
+
+		// get id from param
+		idRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var transferReq TransferFormat
+		if err := c.Bind(&transferReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if transferReq.Id_user == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "id_user is required"))
+		}
+
+		idTransfer, err := rc.repository.Transfer(idRequest, transferReq.Id_user, idAdmin, transferReq.Condition_notes)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+
+		request, err := rc.repository.GetById(idTransfer)
 		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success transfer request", request))
+	}
+}
+
+// damage history of a user, assets returned in poor condition or damaged
+func (rc RequestController) GetDamageHistoryController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
 			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
 		}
 
-		limitStr := c.QueryParam("limit")
-		offsetStr := c.QueryParam("offset")
+		// get id from param
+		idUser, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
 
-		limit, err := strconv.Atoi(limitStr)
+		damages, err := rc.repository.GetDamageHistory(idUser)
 		if err != nil {
-			limit = 0
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get damage history", damages))
+	}
+}
+
+// kitStatuses is the status each role can set on a kit request, a kit is returned through ReturnKitRequestController
+var kitStatuses = map[int]map[int]bool{
+	1: {2: true, 5: true, 6: true, 7: true},
+	3: {3: true, 4: true},
+}
+
+// request a kit, every component of the kit is requested at once
+func (rc RequestController) CreateKitRequestController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
 		}
 
-		offset, err := strconv.Atoi(offsetStr)
+		// get id kit from param
+		idKit, err := strconv.Atoi(c.Param("id"))
 		if err != nil {
-			offset = 0
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
 		}
 
-		request, err := rc.repository.GetEmployee(idUser, true, limit, offset)
+		var kitReq KitRequestFormat
+		if err := c.Bind(&kitReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		kitRequest := entities.KitRequest{
+			Id_kit:      idKit,
+			Id_user:     kitReq.Id_user,
+			Return_date: kitReq.Return_date,
+			Description: kitReq.Description,
+		}
+
+		// same flow as a single request, the admin requests for an employee and waits for the manager
+		idStatus := 2
+		if idRole == 2 {
+			kitRequest.Id_user, _ = middlewares.GetId(c)
+			idStatus = 1
+		}
+		if kitRequest.Id_user == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "id_user is required"))
+		}
+
+		id, err := rc.repository.CreateKitRequest(kitRequest, idStatus)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success create kit request", map[string]int{"id": id}))
+	}
+}
+
+// get kit request with the request of every component unit
+func (rc RequestController) GetKitRequestController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idKitRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		kitRequest, err := rc.repository.GetKitRequest(idKitRequest)
+		if err == sql.ErrNoRows {
+			return c.JSON(http.StatusNotFound, response.NotFound("not found", "kit request not found"))
+		}
 		if err != nil {
 			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
 		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get kit request", kitRequest))
+	}
+}
 
-		totalPage := 0
-		if limit > 0 {
-			requestsTotPage, _ := rc.repository.GetEmployee(idUser, true, 0, 0)
+// update the status of a kit request, handing over (status 6) takes every component or none
+func (rc RequestController) UpdateKitRequestStatusController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
 
-			if len(requestsTotPage)%limit == 0 {
-				totalPage = (len(requestsTotPage) / limit)
-			} else {
-				totalPage = (len(requestsTotPage) / limit) + 1
+		// get id from param
+		idKitRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var statusReq KitStatusFormat
+		if err := c.Bind(&statusReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		switch idRole {
+		case 1:
+			if !kitStatuses[idRole][statusReq.Id_status] {
+				return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 2 || 5 || 6 || 7"))
+			}
+		case 3:
+			if !kitStatuses[idRole][statusReq.Id_status] {
+				return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "id_status must be 3 || 4"))
 			}
+		default:
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
 		}
-		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all assets", map[string]interface{}{
-			"total_page": totalPage,
-			"data":       request,
-		}))
+
+		if err := rc.repository.UpdateKitRequestStatus(idKitRequest, statusReq.Id_status, idUser); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update kit request"))
+	}
+}
+
+// return some or all components of a kit, each with the assessment of its condition
+func (rc RequestController) ReturnKitRequestController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		// get id from param
+		idKitRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var returnReq KitReturnFormat
+		if err := c.Bind(&returnReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if len(returnReq.Items) == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "items is required"))
+		}
+
+		var assessments []entities.ReturnAssessment
+		for _, item := range returnReq.Items {
+			if err := checkGrade(item.Condition_grade); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+			}
+			assessment := entities.ReturnAssessment{
+				Id_request:   item.Id_request,
+				Grade:        item.Condition_grade,
+				Damage_notes: item.Damage_notes,
+			}
+			if idRole == 1 {
+				assessment.Id_assessor = idUser
+			}
+			assessments = append(assessments, assessment)
+		}
+
+		onLoan, err := rc.repository.ReturnKitItems(idKitRequest, assessments)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success return kit items", map[string]int{"on_loan": onLoan}))
+	}
+}
+
+// ask for a later return date on an active loan, the loan keeps its return date until the extension is approved
+func (rc RequestController) CreateExtensionController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		// get id from param
+		idRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var extensionReq ExtensionFormat
+		if err := c.Bind(&extensionReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		returnDate, err := time.Parse("2006-01-02", extensionReq.Return_date)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "return_date must be formatted as YYYY-MM-DD"))
+		}
+		if !returnDate.After(time.Now()) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "return_date must be in the future"))
+		}
+
+		id, err := rc.repository.CreateExtension(entities.RequestExtension{
+			Id_request:  idRequest,
+			Id_user:     idUser,
+			Return_date: extensionReq.Return_date,
+			Reason:      extensionReq.Reason,
+		})
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success request extension", map[string]int{"id": id}))
+	}
+}
+
+// get the extensions of a request
+func (rc RequestController) GetExtensionsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		extensions, err := rc.repository.GetExtensions(idRequest)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get extensions", extensions))
+	}
+}
+
+// approve or reject an extension, by the admin or the manager
+func (rc RequestController) DecideExtensionController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 2 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		// get id from param
+		idExtension, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var decisionReq ExtensionDecisionFormat
+		if err := c.Bind(&decisionReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if decisionReq.Status != "approved" && decisionReq.Status != "rejected" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "status must be approved || rejected"))
+		}
+
+		if err := rc.repository.DecideExtension(idExtension, decisionReq.Status, idUser); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update extension"))
+	}
+}
+
+// get the decisions on a request, with the manager a delegate decided for
+func (rc RequestController) GetRequestEventsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idRequest, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		events, err := rc.repository.GetEvents(idRequest)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get request events", events))
 	}
 }
@@ -2,13 +2,17 @@ package request
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	middlewares "sirclo/project/capstone/delivery/middleware"
 	"sirclo/project/capstone/entities"
+	requestRepo "sirclo/project/capstone/repository/request"
+	"sirclo/project/capstone/util/pagination"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -472,7 +476,7 @@ func TestUpdateRequest(t *testing.T) {
 
 			assert.Equal(t, http.StatusUnauthorized, res.Code)
 			assert.Equal(t, "unauthorized", response.Status)
-			assert.Equal(t, "id_status must be 8", response.Message)
+			assert.Equal(t, "id_status must be 8 || 9", response.Message)
 		}
 	})
 	t.Run("unauthorized manager", func(t *testing.T) {
@@ -1010,31 +1014,1309 @@ func TestGetRequestHistory(t *testing.T) {
 	})
 }
 
+// test desk check out
+func TestCheckOut(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, map[string]interface{}{"tag": "AST-000001", "badge": "USR-000001"}, http.StatusUnauthorized, "unauthorized access"},
+		{"tag and badge are required", 1, map[string]interface{}{"tag": "AST-000001"}, http.StatusBadRequest, "tag and badge are required"},
+		{"invalid badge", 1, map[string]interface{}{"tag": "AST-000001", "badge": "EMP-1"}, http.StatusBadRequest, "invalid badge"},
+		{"asset not found", 1, map[string]interface{}{"tag": "AST-000100", "badge": "USR-000001"}, http.StatusBadRequest, "asset not found"},
+		{"no approved request", 1, map[string]interface{}{"tag": "AST-000001", "badge": "USR-000002"}, http.StatusBadRequest, "no approved request for this asset and user"},
+		{"success check out", 1, map[string]interface{}{"tag": "AST-000001", "badge": "USR-000001", "condition_notes": "small scratch on the lid"}, http.StatusOK, "success check out asset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/desk/check-out")
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.CheckOutController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test desk check in
+func TestCheckIn(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, map[string]interface{}{"tag": "AST-000001"}, http.StatusUnauthorized, "unauthorized access"},
+		{"tag is required", 1, map[string]interface{}{"badge": "USR-000001"}, http.StatusBadRequest, "tag is required"},
+		{"invalid condition grade", 1, map[string]interface{}{"tag": "AST-000001", "condition_grade": "broken"}, http.StatusBadRequest, "condition_grade must be good || fair || poor || damaged"},
+		{"invalid badge", 1, map[string]interface{}{"tag": "AST-000001", "badge": "USR-abc", "condition_grade": "good"}, http.StatusBadRequest, "invalid badge"},
+		{"several loans without badge", 1, map[string]interface{}{"tag": "AST-000003", "condition_grade": "good"}, http.StatusBadRequest, "several loans found for this asset, scan the user badge"},
+		{"success check in with badge", 1, map[string]interface{}{"tag": "AST-000003", "badge": "USR-000001", "condition_grade": "fair"}, http.StatusOK, "success check in asset"},
+		{"success check in damaged", 1, map[string]interface{}{"tag": "AST-000001", "condition_grade": "damaged", "damage_notes": "cracked screen"}, http.StatusOK, "success check in asset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/desk/check-in")
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.CheckInController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test return with condition assessment
+func TestReturnRequest(t *testing.T) {
+	testCases := []struct {
+		name      string
+		idRequest string
+		body      map[string]interface{}
+		code      int
+		message   string
+	}{
+		{"condition grade is required", "1", map[string]interface{}{"id_status": 8}, http.StatusBadRequest, "condition_grade must be good || fair || poor || damaged"},
+		{"request not on loan", "3", map[string]interface{}{"id_status": 8, "condition_grade": "good"}, http.StatusBadRequest, "request is not on loan"},
+		{"success return good", "1", map[string]interface{}{"id_status": 8, "condition_grade": "good"}, http.StatusOK, "success update request"},
+		{"success return damaged", "1", map[string]interface{}{"id_status": 8, "condition_grade": "damaged", "damage_notes": "broken hinge"}, http.StatusOK, "success update request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idRequest)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.UpdateRequestStatus())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test damage history of a user
+func TestGetDamageHistory(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		idUser  string
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "1", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"failed to fetch data", 1, "100", http.StatusBadRequest, "failed to fetch data"},
+		{"success get damage history", 1, "1", http.StatusOK, "success get damage history"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/users/:id/damages")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idUser)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.GetDamageHistoryController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test requests of several units
+func TestMultiQuantityRequest(t *testing.T) {
+	testCases := []struct {
+		name      string
+		idRole    int
+		method    string
+		idRequest string
+		body      map[string]interface{}
+		code      int
+		message   string
+	}{
+		{"negative quantity", 2, http.MethodPost, "", map[string]interface{}{"id_asset": 1, "quantity": -2, "return_date": "2026-12-01"}, http.StatusBadRequest, "quantity must be greater than 0"},
+		{"not enough units at creation", 2, http.MethodPost, "", map[string]interface{}{"id_asset": 5, "quantity": 3, "return_date": "2026-12-01"}, http.StatusBadRequest, "not enough units available"},
+		{"success create request of 2 units", 2, http.MethodPost, "", map[string]interface{}{"id_asset": 5, "quantity": 2, "return_date": "2026-12-01"}, http.StatusOK, "success create request"},
+		{"not enough units at approval", 3, http.MethodPut, "4", map[string]interface{}{"id_status": 3}, http.StatusBadRequest, "not enough units available"},
+		{"not enough units at hand over", 1, http.MethodPut, "4", map[string]interface{}{"id_status": 6}, http.StatusBadRequest, "not enough units available"},
+		{"success approve", 3, http.MethodPut, "1", map[string]interface{}{"id_status": 3}, http.StatusOK, "success update request"},
+		{"success hand over", 1, http.MethodPut, "1", map[string]interface{}{"id_status": 6}, http.StatusOK, "success update request"},
+		{"negative return quantity", 2, http.MethodPut, "1", map[string]interface{}{"id_status": 8, "condition_grade": "good", "quantity": -1}, http.StatusBadRequest, "quantity must be greater than 0"},
+		{"too many units returned", 2, http.MethodPut, "2", map[string]interface{}{"id_status": 8, "condition_grade": "good", "quantity": 4}, http.StatusBadRequest, "only 3 units left to return"},
+		{"success partial return", 2, http.MethodPut, "2", map[string]interface{}{"id_status": 8, "condition_grade": "good", "quantity": 2}, http.StatusOK, "success update request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(tc.method, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+
+			reqController := NewRequestController(mockRequestRepository{})
+			handler := reqController.CreateRequestEmployee()
+			context.SetPath("/requests")
+			if tc.method == http.MethodPut {
+				handler = reqController.UpdateRequestStatus()
+				context.SetPath("/requests/:id")
+				context.SetParamNames("id")
+				context.SetParamValues(tc.idRequest)
+			}
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(handler)(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test request a kit
+func TestCreateKitRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		idKit   string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, "1", map[string]interface{}{}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 2, "a", map[string]interface{}{}, http.StatusBadRequest, "failed to convert id"},
+		{"id_user is required", 1, "1", map[string]interface{}{"return_date": "2026-12-01"}, http.StatusBadRequest, "id_user is required"},
+		{"component not available", 2, "2", map[string]interface{}{"return_date": "2026-12-01"}, http.StatusBadRequest, "not enough units available for headset"},
+		{"success employee request", 2, "1", map[string]interface{}{"return_date": "2026-12-01"}, http.StatusOK, "success create kit request"},
+		{"success admin request", 1, "1", map[string]interface{}{"id_user": 2, "return_date": "2026-12-01"}, http.StatusOK, "success create kit request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kits/:id/requests")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idKit)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.CreateKitRequestController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test get kit request
+func TestGetKitRequest(t *testing.T) {
+	testCases := []struct {
+		name         string
+		idKitRequest string
+		code         int
+		message      string
+	}{
+		{"failed to convert id", "a", http.StatusBadRequest, "failed to convert id"},
+		{"kit request not found", "100", http.StatusNotFound, "kit request not found"},
+		{"success get kit request", "1", http.StatusOK, "success get kit request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kit-requests/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idKitRequest)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.GetKitRequestController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test update status of a kit request
+func TestUpdateKitRequestStatus(t *testing.T) {
+	testCases := []struct {
+		name         string
+		idRole       int
+		idKitRequest string
+		idStatus     int
+		code         int
+		message      string
+	}{
+		{"employee cannot update status", 2, "1", 6, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", 6, http.StatusBadRequest, "failed to convert id"},
+		{"admin wrong status", 1, "1", 3, http.StatusUnauthorized, "id_status must be 2 || 5 || 6 || 7"},
+		{"manager wrong status", 3, "1", 6, http.StatusUnauthorized, "id_status must be 3 || 4"},
+		{"hand over not approved kit", 1, "2", 6, http.StatusBadRequest, "kit request is not approved by manager"},
+		{"success manager approve", 3, "1", 3, http.StatusOK, "success update kit request"},
+		{"success hand over", 1, "1", 6, http.StatusOK, "success update kit request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"id_status": tc.idStatus})
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kit-requests/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idKitRequest)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.UpdateKitRequestStatusController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test partial and full return of a kit
+func TestReturnKitRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		items   []map[string]interface{}
+		code    int
+		message string
+		onLoan  int
+	}{
+		{"unauthorized access", 3, []map[string]interface{}{{"id_request": 1, "condition_grade": "good"}}, http.StatusUnauthorized, "unauthorized access", 0},
+		{"items is required", 2, nil, http.StatusBadRequest, "items is required", 0},
+		{"condition grade is required", 2, []map[string]interface{}{{"id_request": 1}}, http.StatusBadRequest, "condition_grade must be good || fair || poor || damaged", 0},
+		{"request outside the kit", 2, []map[string]interface{}{{"id_request": 100, "condition_grade": "good"}}, http.StatusBadRequest, "request 100 is not part of this kit request", 0},
+		{"success partial return", 2, []map[string]interface{}{{"id_request": 1, "condition_grade": "good"}}, http.StatusOK, "success return kit items", 3},
+		{"success full return", 1, []map[string]interface{}{
+			{"id_request": 1, "condition_grade": "good"},
+			{"id_request": 2, "condition_grade": "fair"},
+			{"id_request": 3, "condition_grade": "good"},
+			{"id_request": 4, "condition_grade": "damaged", "damage_notes": "cracked headband"},
+		}, http.StatusOK, "success return kit items", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"items": tc.items})
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/kit-requests/:id/return")
+			context.SetParamNames("id")
+			context.SetParamValues("1")
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int            `json:"code"`
+				Status  string         `json:"status"`
+				Message string         `json:"message"`
+				Data    map[string]int `json:"data"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.ReturnKitRequestController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				assert.Equal(t, tc.onLoan, response.Data["on_loan"])
+			}
+		})
+	}
+}
+
+// test cancel request by the employee
+func TestCancelRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"manager can not cancel", 3, "1", http.StatusUnauthorized, "id_status must be 3 || 4"},
+		{"already handed over", 2, "2", http.StatusBadRequest, "request can no longer be cancelled"},
+		{"success cancel", 2, "1", http.StatusOK, "success update request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"id_status": 9})
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.UpdateRequestStatus())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test request loan extension
+func TestCreateExtension(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, "1", map[string]interface{}{"return_date": tomorrow}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 2, "a", map[string]interface{}{"return_date": tomorrow}, http.StatusBadRequest, "failed to convert id"},
+		{"failed to bind data", 2, "1", map[string]interface{}{"return_date": 1}, http.StatusBadRequest, "failed to bind data"},
+		{"invalid date", 2, "1", map[string]interface{}{"return_date": "01-12-2026"}, http.StatusBadRequest, "return_date must be formatted as YYYY-MM-DD"},
+		{"date in the past", 2, "1", map[string]interface{}{"return_date": yesterday}, http.StatusBadRequest, "return_date must be in the future"},
+		{"not on loan", 2, "2", map[string]interface{}{"return_date": tomorrow}, http.StatusBadRequest, "request is not on loan"},
+		{"success request extension", 2, "1", map[string]interface{}{"return_date": tomorrow, "reason": "project extended"}, http.StatusOK, "success request extension"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id/extensions")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.CreateExtensionController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test get extensions of a request
+func TestGetExtensions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		repo    requestRepo.RequestRepo
+		code    int
+		message string
+	}{
+		{"failed to convert id", 1, "a", mockRequestRepository{}, http.StatusBadRequest, "failed to convert id"},
+		{"failed to fetch data", 3, "1", mockErrorRequestRepository{}, http.StatusBadRequest, "failed to fetch data"},
+		{"success employee", 2, "1", mockRequestRepository{}, http.StatusOK, "success get extensions"},
+		{"success manager", 3, "2", mockRequestRepository{}, http.StatusOK, "success get extensions"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id/extensions")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := NewRequestController(tc.repo)
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.GetExtensionsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test approve or reject extension
+func TestDecideExtension(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "1", map[string]interface{}{"status": "approved"}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 3, "a", map[string]interface{}{"status": "approved"}, http.StatusBadRequest, "failed to convert id"},
+		{"invalid status", 3, "1", map[string]interface{}{"status": "pending"}, http.StatusBadRequest, "status must be approved || rejected"},
+		{"already decided", 3, "2", map[string]interface{}{"status": "rejected"}, http.StatusBadRequest, "extension not found or already decided"},
+		{"success manager approve", 3, "1", map[string]interface{}{"status": "approved"}, http.StatusOK, "success update extension"},
+		{"success admin reject", 1, "1", map[string]interface{}{"status": "rejected"}, http.StatusOK, "success update extension"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/extensions/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.DecideExtensionController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test rejection with a reason
+func TestRejectRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"manager without reason", 3, "1", map[string]interface{}{"id_status": 4}, http.StatusBadRequest, "reason is required to reject a request"},
+		{"admin with blank reason", 1, "1", map[string]interface{}{"id_status": 5, "reason": "  "}, http.StatusBadRequest, "reason is required to reject a request"},
+		{"request not found", 3, "3", map[string]interface{}{"id_status": 4, "reason": "budget frozen"}, http.StatusBadRequest, "id not found"},
+		{"success manager reject", 3, "1", map[string]interface{}{"id_status": 4, "reason": "budget frozen"}, http.StatusOK, "success update request"},
+		{"success admin reject", 1, "1", map[string]interface{}{"id_status": 5, "reason": "asset reserved for the audit"}, http.StatusOK, "success update request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.UpdateRequestStatus())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test bulk status update
+func TestBulkUpdateRequestStatus(t *testing.T) {
+	type result struct {
+		Id      int    `json:"id"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+		results []result
+	}{
+		{"employee can not bulk update", 2, map[string]interface{}{"ids": []int{1}, "id_status": 8}, http.StatusUnauthorized, "unauthorized access", nil},
+		{"failed to bind data", 1, map[string]interface{}{"ids": "1"}, http.StatusBadRequest, "failed to bind data", nil},
+		{"ids is required", 1, map[string]interface{}{"ids": []int{}, "id_status": 2}, http.StatusBadRequest, "ids is required", nil},
+		{"too many requests", 1, map[string]interface{}{"ids": make([]int, 101), "id_status": 2}, http.StatusBadRequest, "at most 100 requests at once", nil},
+		{"manager wrong status", 3, map[string]interface{}{"ids": []int{1}, "id_status": 6}, http.StatusUnauthorized, "id_status must be 3 || 4", nil},
+		{"admin wrong status", 1, map[string]interface{}{"ids": []int{1}, "id_status": 8}, http.StatusUnauthorized, "id_status must be 2 || 5 || 6 || 7", nil},
+		{"reject without reason", 3, map[string]interface{}{"ids": []int{1}, "id_status": 4}, http.StatusBadRequest, "reason is required to reject a request", nil},
+		{"manager approves", 3, map[string]interface{}{"ids": []int{1, 2, 9, 3, 1}, "id_status": 3}, http.StatusOK, "success bulk update request", []result{
+			{1, "success", ""},
+			{2, "failed", "unauthorized access"},
+			{9, "failed", "id not found"},
+			{3, "failed", "request can not move from status 3 to 3"},
+		}},
+		{"manager rejects", 3, map[string]interface{}{"ids": []int{1}, "id_status": 4, "reason": "budget frozen"}, http.StatusOK, "success bulk update request", []result{
+			{1, "success", ""},
+		}},
+		{"manager delegate of another division approves", 3, map[string]interface{}{"ids": []int{6, 2}, "id_status": 3}, http.StatusOK, "success bulk update request", []result{
+			{6, "success", ""},
+			{2, "failed", "unauthorized access"},
+		}},
+		{"admin hands over", 1, map[string]interface{}{"ids": []int{3, 4}, "id_status": 6}, http.StatusOK, "success bulk update request", []result{
+			{3, "success", ""},
+			{4, "failed", "not enough units available"},
+		}},
+		{"admin forwards new requests", 1, map[string]interface{}{"ids": []int{5, 2}, "id_status": 2}, http.StatusOK, "success bulk update request", []result{
+			{5, "success", ""},
+			{2, "failed", "request can not move from status 0 to 2"},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/bulk")
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+				Data    struct {
+					Results []result `json:"results"`
+				} `json:"data"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.BulkUpdateRequestStatus())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				assert.Equal(t, tc.results, response.Data.Results)
+			}
+		})
+	}
+}
+
+// test ownership checks on the request routes, request 1 belongs to the employee, request 2 to another division
+func TestRequestAccess(t *testing.T) {
+	testCases := []struct {
+		name       string
+		idRole     int
+		method     string
+		path       string
+		id         string
+		body       map[string]interface{}
+		repo       requestRepo.RequestRepo
+		access     func(RequestController) echo.MiddlewareFunc
+		controller func(RequestController) echo.HandlerFunc
+		code       int
+		message    string
+	}{
+		{"employee reads own request", 2, http.MethodGet, "/requests/:id", "1", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusOK, "success get asset"},
+		{"employee reads request of another user", 2, http.MethodGet, "/requests/:id", "2", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusUnauthorized, "unauthorized access"},
+		{"employee reads loan of another user", 2, http.MethodGet, "/employee/request_loan/:id", "2", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusUnauthorized, "unauthorized access"},
+		{"employee returns request of another user", 2, http.MethodPut, "/requests/:id", "2", map[string]interface{}{"id_status": 8, "condition_grade": "good"}, mockRequestRepository{}, RequestController.RequestDecisionAccess, RequestController.UpdateRequestStatus, http.StatusUnauthorized, "unauthorized access"},
+		{"employee cancels request of another user", 2, http.MethodPut, "/requests/:id", "2", map[string]interface{}{"id_status": 9}, mockRequestRepository{}, RequestController.RequestDecisionAccess, RequestController.UpdateRequestStatus, http.StatusUnauthorized, "unauthorized access"},
+		{"employee extends loan of another user", 2, http.MethodPost, "/requests/:id/extensions", "2", map[string]interface{}{"return_date": "2099-01-01"}, mockRequestRepository{}, RequestController.RequestAccess, RequestController.CreateExtensionController, http.StatusUnauthorized, "unauthorized access"},
+		{"employee reads extensions of another user", 2, http.MethodGet, "/requests/:id/extensions", "2", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetExtensionsController, http.StatusUnauthorized, "unauthorized access"},
+		{"employee returns kit of another user", 2, http.MethodPost, "/kit-requests/:id/return", "2", nil, mockRequestRepository{}, RequestController.KitRequestAccess, RequestController.ReturnKitRequestController, http.StatusUnauthorized, "unauthorized access"},
+		{"manager approves request of their division", 3, http.MethodPut, "/requests/:id", "1", map[string]interface{}{"id_status": 3}, mockRequestRepository{}, RequestController.RequestDecisionAccess, RequestController.UpdateRequestStatus, http.StatusOK, "success update request"},
+		{"manager approves request of another division", 3, http.MethodPut, "/requests/:id", "2", map[string]interface{}{"id_status": 3}, mockRequestRepository{}, RequestController.RequestDecisionAccess, RequestController.UpdateRequestStatus, http.StatusUnauthorized, "unauthorized access"},
+		{"manager reads kit request of another division", 3, http.MethodGet, "/kit-requests/:id", "2", nil, mockRequestRepository{}, RequestController.KitRequestAccess, RequestController.GetKitRequestController, http.StatusUnauthorized, "unauthorized access"},
+		{"manager decides extension of another division", 3, http.MethodPut, "/extensions/:id", "2", map[string]interface{}{"status": "approved"}, mockRequestRepository{}, RequestController.ExtensionAccess, RequestController.DecideExtensionController, http.StatusUnauthorized, "unauthorized access"},
+		{"delegate reads request of another division", 2, http.MethodGet, "/requests/:id", "6", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusUnauthorized, "unauthorized access"},
+		{"delegate extends loan of another division", 2, http.MethodPost, "/requests/:id/extensions", "6", map[string]interface{}{"return_date": "2099-01-01"}, mockRequestRepository{}, RequestController.RequestAccess, RequestController.CreateExtensionController, http.StatusUnauthorized, "unauthorized access"},
+		{"fallback approver reads escalated request", 2, http.MethodGet, "/requests/:id", "7", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusUnauthorized, "unauthorized access"},
+		{"admin reads request of any division", 1, http.MethodGet, "/requests/:id", "2", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusOK, "success get asset"},
+		{"request not found", 1, http.MethodGet, "/requests/:id", "9", nil, mockRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusNotFound, "id not found"},
+		{"failed to fetch owner", 2, http.MethodGet, "/requests/:id", "1", nil, mockErrorRequestRepository{}, RequestController.RequestAccess, RequestController.GetRequestByIdController, http.StatusBadRequest, "failed to fetch data"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(tc.method, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath(tc.path)
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := *NewRequestController(tc.repo)
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(tc.access(reqController)(tc.controller(reqController)))(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test decisions of a delegate while the manager is out of office, request 6 belongs to the division of manager 7
+func TestDelegatedDecision(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"employee without delegation", 2, "1", map[string]interface{}{"id_status": 3}, http.StatusUnauthorized, "id_status must be 8 || 9"},
+		{"employee delegate approves", 2, "6", map[string]interface{}{"id_status": 3}, http.StatusOK, "success update request"},
+		{"employee delegate rejects", 2, "6", map[string]interface{}{"id_status": 4, "reason": "no budget left"}, http.StatusOK, "success update request"},
+		{"delegate can not hand over", 2, "6", map[string]interface{}{"id_status": 6}, http.StatusUnauthorized, "unauthorized access"},
+		{"delegate can not return", 2, "6", map[string]interface{}{"id_status": 8, "condition_grade": "good"}, http.StatusUnauthorized, "unauthorized access"},
+		{"delegate can not cancel", 2, "6", map[string]interface{}{"id_status": 9}, http.StatusUnauthorized, "unauthorized access"},
+		{"manager delegate of another division approves", 3, "6", map[string]interface{}{"id_status": 3}, http.StatusOK, "success update request"},
+		{"admin delegate keeps the admin rules", 1, "6", map[string]interface{}{"id_status": 3}, http.StatusUnauthorized, "id_status must be 2 || 5 || 6 || 7"},
+		{"fallback approver approves escalated request", 2, "7", map[string]interface{}{"id_status": 3}, http.StatusOK, "success update request"},
+		{"fallback approver can not hand over", 2, "7", map[string]interface{}{"id_status": 6}, http.StatusUnauthorized, "unauthorized access"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := *NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.RequestDecisionAccess()(reqController.UpdateRequestStatus()))(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test get decisions on a request
+func TestGetRequestEvents(t *testing.T) {
+	testCases := []struct {
+		name    string
+		id      string
+		repo    requestRepo.RequestRepo
+		code    int
+		message string
+	}{
+		{"failed to convert id", "a", mockRequestRepository{}, http.StatusBadRequest, "failed to convert id"},
+		{"failed to fetch data", "1", mockErrorRequestRepository{}, http.StatusBadRequest, "failed to fetch data"},
+		{"success get events", "1", mockRequestRepository{}, http.StatusOK, "success get request events"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 1)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id/events")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := NewRequestController(tc.repo)
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.GetRequestEventsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test transfer a loan to another employee
+func TestTransferRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, "1", map[string]interface{}{"id_user": 2}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", map[string]interface{}{"id_user": 2}, http.StatusBadRequest, "failed to convert id"},
+		{"failed to bind data", 1, "1", map[string]interface{}{"id_user": "2"}, http.StatusBadRequest, "failed to bind data"},
+		{"id_user is required", 1, "1", map[string]interface{}{"condition_notes": "good"}, http.StatusBadRequest, "id_user is required"},
+		{"not on loan", 1, "3", map[string]interface{}{"id_user": 2}, http.StatusBadRequest, "request is not on loan"},
+		{"already lent to the recipient", 1, "1", map[string]interface{}{"id_user": 1}, http.StatusBadRequest, "asset is already lent to the recipient"},
+		{"recipient not an employee", 1, "1", map[string]interface{}{"id_user": 5}, http.StatusBadRequest, "recipient must be an employee"},
+		{"success transfer", 1, "1", map[string]interface{}{"id_user": 2, "condition_notes": "good"}, http.StatusOK, "success transfer request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests/:id/transfer")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			reqController := NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reqController.TransferRequestController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// test pages of the request lists, by offset with a count or by cursor
+func TestRequestPagination(t *testing.T) {
+	cursor := pagination.Cursor{Id: 4}.Encode()
+	lastCursor := pagination.Cursor{Id: 2}.Encode()
+
+	testCases := []struct {
+		name       string
+		handler    func(RequestController) echo.HandlerFunc
+		idRole     int
+		query      string
+		code       int
+		message    string
+		totalItems int
+		totalPages int
+		page       int
+		ids        []int
+		nextCursor string
+	}{
+		{"admin pages", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestsController() }, 1, "/?limit=2", http.StatusOK, "success get all request", 5, 3, 1, nil, ""},
+		{"manager exact pages", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestsController() }, 3, "/?limit=2&page=2", http.StatusOK, "success get all request", 4, 2, 2, nil, ""},
+		{"cursor on requests", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestsController() }, 1, "/?cursor=", http.StatusBadRequest, "cursor is only supported on the request activity and history", 0, 0, 0, nil, ""},
+		{"history by offset", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestHistoryController() }, 2, "/?limit=2&offset=2", http.StatusOK, "success get all assets", 5, 3, 2, []int{3, 2}, ""},
+		{"history without limit", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestHistoryController() }, 2, "/", http.StatusOK, "success get all assets", 5, 1, 1, []int{5, 4, 3, 2, 1}, ""},
+		{"invalid cursor", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestHistoryController() }, 2, "/?cursor=abc", http.StatusBadRequest, "invalid cursor", 0, 0, 0, nil, ""},
+		{"first page by cursor", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestHistoryController() }, 2, "/?cursor=&limit=2", http.StatusOK, "success get all assets", 0, 0, 0, []int{5, 4}, cursor},
+		{"next page by cursor", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestActivityController() }, 2, "/?cursor=" + cursor + "&limit=2", http.StatusOK, "success get all assets", 0, 0, 0, []int{3, 2}, lastCursor},
+		{"last page by cursor", func(rc RequestController) echo.HandlerFunc { return rc.GetRequestActivityController() }, 2, "/?cursor=" + lastCursor + "&limit=2", http.StatusOK, "success get all assets", 0, 0, 0, []int{1}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/requests")
+
+			reqController := *NewRequestController(mockRequestRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+				Data    struct {
+					Total_items int                        `json:"total_items"`
+					Total_pages int                        `json:"total_pages"`
+					Page        int                        `json:"page"`
+					Next_cursor string                     `json:"next_cursor"`
+					Data        []entities.RequestResponse `json:"data"`
+				} `json:"data"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(tc.handler(reqController))(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				assert.Equal(t, tc.totalItems, response.Data.Total_items)
+				assert.Equal(t, tc.totalPages, response.Data.Total_pages)
+				assert.Equal(t, tc.page, response.Data.Page)
+				assert.Equal(t, tc.nextCursor, response.Data.Next_cursor)
+				if tc.ids != nil {
+					var ids []int
+					for _, request := range response.Data.Data {
+						ids = append(ids, request.Id)
+					}
+					assert.Equal(t, tc.ids, ids)
+				}
+			}
+		})
+	}
+}
+
 type mockRequestRepository struct{}
 
 func (m mockRequestRepository) Create(entities.Request) error {
 	return nil
 }
-func (m mockRequestRepository) GetAdmin(return_date, request_date, status, filter_date, category string, limit, offset int) ([]entities.RequestResponse, error) {
+func (m mockRequestRepository) GetAdmin(return_date, request_date, status, filter_date, category string, page pagination.Page) ([]entities.RequestResponse, error) {
 	return nil, nil
 }
-func (m mockRequestRepository) GetManager(return_date, request_date, status, filter_date, category string, limit, offset int) ([]entities.RequestResponse, error) {
+func (m mockRequestRepository) CountAdmin(status, filter_date, category string) (int, error) {
+	return 5, nil
+}
+func (m mockRequestRepository) GetManager(idManager int, return_date, request_date, status, filter_date, category string, page pagination.Page) ([]entities.RequestResponse, error) {
 	return nil, nil
 }
-func (m mockRequestRepository) GetById(int) (entities.RequestResponse, error) {
-	return entities.RequestResponse{}, nil
+func (m mockRequestRepository) CountManager(idManager int, status, filter_date, category string) (int, error) {
+	return 4, nil
+}
+func (m mockRequestRepository) GetById(id int) (entities.RequestResponse, error) {
+	// status of the requests used in bulk updates: 1 and 6 wait for the manager, 3 and 4 are approved, 5 is new
+	statuses := map[int]int{1: 2, 3: 3, 4: 3, 5: 1, 6: 2}
+	return entities.RequestResponse{Id: id, Id_user: id, Id_status: statuses[id]}, nil
 }
 func (m mockRequestRepository) Update(entities.Request, int) error {
 	return nil
 }
-func (m mockRequestRepository) UpdateAvailQty(int, int) error {
+func (m mockRequestRepository) HandOver(idRequest int, returnDate string) error {
+	// request 4 asks for more units than left in stock
+	if idRequest == 4 {
+		return fmt.Errorf("not enough units available")
+	}
 	return nil
 }
-func (m mockRequestRepository) GetAvailQty(int) (entities.Request, error) {
-	return entities.Request{}, nil
+func (m mockRequestRepository) GetAvailQty(id int) (entities.Request, error) {
+	// request 4 asks for more units than left in stock
+	if id == 4 {
+		return entities.Request{Id: id, Id_asset: 5, Quantity: 5, Initial_quantity: 10, Avail_quantity: 2}, nil
+	}
+	return entities.Request{Id: id, Id_asset: 1, Quantity: 1, Initial_quantity: 10, Avail_quantity: 10}, nil
 }
-func (m mockRequestRepository) GetEmployee(id_employee int, is_history bool, limit, offset int) ([]entities.RequestResponse, error) {
-	return nil, nil
+
+// the employee has 5 requests, request 5 is the newest and updated last
+func (m mockRequestRepository) GetEmployee(id_employee int, is_history bool, page pagination.Page) ([]entities.RequestResponse, error) {
+	var requests []entities.RequestResponse
+	for id := 5; id > 0; id-- {
+		if page.After.Id != 0 && id >= page.After.Id {
+			continue
+		}
+		requests = append(requests, entities.RequestResponse{Id: id, Id_user: id_employee})
+	}
+
+	limit := page.Limit
+	if page.Keyset {
+		limit++
+	} else if page.Offset < len(requests) {
+		requests = requests[page.Offset:]
+	} else {
+		requests = nil
+	}
+	if limit > 0 && limit < len(requests) {
+		requests = requests[:limit]
+	}
+	return requests, nil
+}
+func (m mockRequestRepository) CountEmployee(id_employee int, is_history bool) (int, error) {
+	return 5, nil
+}
+func (m mockRequestRepository) CheckOut(tag string, idUser, idAdmin int, notes string) (int, error) {
+	if tag == "AST-000100" {
+		return 0, fmt.Errorf("asset not found")
+	}
+	if idUser == 2 {
+		return 0, fmt.Errorf("no approved request for this asset and user")
+	}
+	return 1, nil
+}
+func (m mockRequestRepository) CheckIn(tag string, idUser int, notes string, assessment entities.ReturnAssessment) (int, error) {
+	if tag == "AST-000003" && idUser == 0 {
+		return 0, fmt.Errorf("several loans found for this asset, scan the user badge")
+	}
+	return 1, nil
+}
+func (m mockRequestRepository) Return(idRequest int, assessment entities.ReturnAssessment) error {
+	if idRequest == 3 {
+		return fmt.Errorf("request is not on loan")
+	}
+	// request 2 has 3 units left on loan
+	if idRequest == 2 && assessment.Quantity > 3 {
+		return fmt.Errorf("only %d units left to return", 3)
+	}
+	return nil
+}
+func (m mockRequestRepository) Transfer(idRequest, idRecipient, idAdmin int, notes string) (int, error) {
+	if idRequest == 3 {
+		return 0, fmt.Errorf("request is not on loan")
+	}
+	// the loan of request 1 is held by user 1
+	if idRequest == idRecipient {
+		return 0, fmt.Errorf("asset is already lent to the recipient")
+	}
+	// user 5 is a manager
+	if idRecipient == 5 {
+		return 0, fmt.Errorf("recipient must be an employee")
+	}
+	return 10, nil
+}
+func (m mockRequestRepository) GetDamageHistory(idUser int) ([]entities.ReturnAssessment, error) {
+	if idUser == 100 {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.ReturnAssessment{{Id: 1, Id_user: idUser, Grade: "damaged", Id_maintenance: 1}}, nil
+}
+func (m mockRequestRepository) GetAssetAvailQty(idAsset int, returnDate string) (int, error) {
+	// asset 5 has 2 units left
+	if idAsset == 5 {
+		return 2, nil
+	}
+	return 10, nil
+}
+func (m mockRequestRepository) CreateKitRequest(kitRequest entities.KitRequest, idStatus int) (int, error) {
+	if kitRequest.Id_kit == 2 {
+		return 0, fmt.Errorf("not enough units available for headset")
+	}
+	return 1, nil
+}
+func (m mockRequestRepository) GetKitRequest(id int) (entities.KitRequest, error) {
+	if id == 100 {
+		return entities.KitRequest{}, sql.ErrNoRows
+	}
+	return entities.KitRequest{Id: id, Id_kit: 1, Kit_name: "new hire", On_loan: 4}, nil
+}
+func (m mockRequestRepository) UpdateKitRequestStatus(id, idStatus, idAdmin int) error {
+	if id == 2 && idStatus == 6 {
+		return fmt.Errorf("kit request is not approved by manager")
+	}
+	return nil
+}
+func (m mockRequestRepository) Cancel(idRequest, idUser int) error {
+	if idRequest == 2 {
+		return fmt.Errorf("request can no longer be cancelled")
+	}
+	return nil
+}
+func (m mockRequestRepository) CreateExtension(extension entities.RequestExtension) (int, error) {
+	if extension.Id_request == 2 {
+		return 0, fmt.Errorf("request is not on loan")
+	}
+	return 1, nil
+}
+func (m mockRequestRepository) GetExtensions(idRequest int) ([]entities.RequestExtension, error) {
+	return []entities.RequestExtension{{Id: 1, Id_request: idRequest, Status: "pending"}}, nil
+}
+func (m mockRequestRepository) DecideExtension(id int, status string, idApprover int) error {
+	if id == 2 {
+		return fmt.Errorf("extension not found or already decided")
+	}
+	return nil
+}
+func (m mockRequestRepository) Reject(idRequest, idStatus, idUser int, reason string) error {
+	if idRequest == 3 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
+func (m mockRequestRepository) AddEvent(event entities.RequestEvent) error {
+	return nil
+}
+func (m mockRequestRepository) GetEvents(idRequest int) ([]entities.RequestEvent, error) {
+	return []entities.RequestEvent{{Id: 1, Id_request: idRequest, Id_user: 1, Id_on_behalf: 7, Id_status: 3}}, nil
+}
+func (m mockRequestRepository) GetOwnership(id, idUser int) (entities.Ownership, error) {
+	switch id {
+	case 2:
+		return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT"}, nil
+	case 6:
+		// the user approves for manager 7 of the division of the owner
+		return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 2}, nil
+	case 7:
+		// the request is overdue and escalated to the user
+		return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Escalated: true, Id_status: 2}, nil
+	case 9:
+		return entities.Ownership{}, sql.ErrNoRows
+	}
+	return entities.Ownership{Id_owner: 1, Owner_divisi: "IT", User_divisi: "IT"}, nil
+}
+func (m mockRequestRepository) GetKitOwnership(id, idUser int) (entities.Ownership, error) {
+	return m.GetOwnership(id, idUser)
+}
+func (m mockRequestRepository) GetExtensionOwnership(id, idUser int) (entities.Ownership, error) {
+	return m.GetOwnership(id, idUser)
+}
+func (m mockRequestRepository) ReturnKitItems(id int, assessments []entities.ReturnAssessment) (int, error) {
+	for _, assessment := range assessments {
+		if assessment.Id_request == 100 {
+			return 0, fmt.Errorf("request %d is not part of this kit request", assessment.Id_request)
+		}
+	}
+	// the kit of the mock has 4 units on loan
+	return 4 - len(assessments), nil
 }
 
 type mockErrorRequestRepository struct{}
@@ -1042,24 +2324,93 @@ type mockErrorRequestRepository struct{}
 func (m mockErrorRequestRepository) Create(entities.Request) error {
 	return fmt.Errorf("error")
 }
-func (m mockErrorRequestRepository) GetAdmin(return_date, request_date, status, filter_date, category string, limit, offset int) ([]entities.RequestResponse, error) {
+func (m mockErrorRequestRepository) GetAdmin(return_date, request_date, status, filter_date, category string, page pagination.Page) ([]entities.RequestResponse, error) {
 	return nil, fmt.Errorf("error")
 }
-func (m mockErrorRequestRepository) GetManager(return_date, request_date, status, filter_date, category string, limit, offset int) ([]entities.RequestResponse, error) {
+func (m mockErrorRequestRepository) CountAdmin(status, filter_date, category string) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetManager(idManager int, return_date, request_date, status, filter_date, category string, page pagination.Page) ([]entities.RequestResponse, error) {
 	return nil, fmt.Errorf("error")
 }
+func (m mockErrorRequestRepository) CountManager(idManager int, status, filter_date, category string) (int, error) {
+	return 0, fmt.Errorf("error")
+}
 func (m mockErrorRequestRepository) GetById(int) (entities.RequestResponse, error) {
 	return entities.RequestResponse{}, fmt.Errorf("error")
 }
 func (m mockErrorRequestRepository) Update(entities.Request, int) error {
 	return fmt.Errorf("error")
 }
-func (m mockErrorRequestRepository) UpdateAvailQty(int, int) error {
+func (m mockErrorRequestRepository) HandOver(idRequest int, returnDate string) error {
 	return fmt.Errorf("error")
 }
 func (m mockErrorRequestRepository) GetAvailQty(int) (entities.Request, error) {
 	return entities.Request{}, fmt.Errorf("error")
 }
-func (m mockErrorRequestRepository) GetEmployee(id_employee int, is_history bool, limit, offset int) ([]entities.RequestResponse, error) {
+func (m mockErrorRequestRepository) GetEmployee(id_employee int, is_history bool, page pagination.Page) ([]entities.RequestResponse, error) {
 	return nil, fmt.Errorf("error")
 }
+func (m mockErrorRequestRepository) CountEmployee(id_employee int, is_history bool) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) CheckOut(tag string, idUser, idAdmin int, notes string) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) CheckIn(tag string, idUser int, notes string, assessment entities.ReturnAssessment) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) Return(idRequest int, assessment entities.ReturnAssessment) error {
+	return fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) Transfer(idRequest, idRecipient, idAdmin int, notes string) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetDamageHistory(idUser int) ([]entities.ReturnAssessment, error) {
+	return nil, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetAssetAvailQty(idAsset int, returnDate string) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) CreateKitRequest(kitRequest entities.KitRequest, idStatus int) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetKitRequest(id int) (entities.KitRequest, error) {
+	return entities.KitRequest{}, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) UpdateKitRequestStatus(id, idStatus, idAdmin int) error {
+	return fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) Cancel(idRequest, idUser int) error {
+	return fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) CreateExtension(extension entities.RequestExtension) (int, error) {
+	return 0, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetExtensions(idRequest int) ([]entities.RequestExtension, error) {
+	return nil, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) DecideExtension(id int, status string, idApprover int) error {
+	return fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) Reject(idRequest, idStatus, idUser int, reason string) error {
+	return fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) AddEvent(event entities.RequestEvent) error {
+	return fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetEvents(idRequest int) ([]entities.RequestEvent, error) {
+	return nil, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetOwnership(id, idUser int) (entities.Ownership, error) {
+	return entities.Ownership{}, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetKitOwnership(id, idUser int) (entities.Ownership, error) {
+	return entities.Ownership{}, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) GetExtensionOwnership(id, idUser int) (entities.Ownership, error) {
+	return entities.Ownership{}, fmt.Errorf("error")
+}
+func (m mockErrorRequestRepository) ReturnKitItems(id int, assessments []entities.ReturnAssessment) (int, error) {
+	return 0, fmt.Errorf("error")
+}
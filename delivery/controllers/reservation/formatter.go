@@ -0,0 +1,14 @@
+package reservation
+
+type ReservationRequestFormat struct {
+	Id_user     int    `json:"id_user" form:"id_user"`
+	Id_asset    int    `json:"id_asset" form:"id_asset"`
+	Quantity    int    `json:"quantity" form:"quantity"`
+	Start_at    string `json:"start_at" form:"start_at"`
+	End_at      string `json:"end_at" form:"end_at"`
+	Description string `json:"description" form:"description"`
+}
+
+type StatusRequestFormat struct {
+	Status string `json:"status" form:"status"`
+}
@@ -0,0 +1,230 @@
+package reservation
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	reservationRepo "sirclo/project/capstone/repository/reservation"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// layout of start_at and end_at sent by the client
+	reservationLayout = "2006-01-02 15:04"
+	// longest period of the availability calendar
+	maxCalendarDays = 92
+)
+
+type ReservationController struct {
+	repository reservationRepo.ReservationRepo
+}
+
+func NewReservationController(reservation reservationRepo.ReservationRepo) *ReservationController {
+	return &ReservationController{repository: reservation}
+}
+
+// ReservationAccess checks the reservation of the id param against the logged in user, a manager only gets to the
+// reservations of their division
+func (rc ReservationController) ReservationAccess() echo.MiddlewareFunc {
+	return middlewares.ResourceAccess(rc.repository.GetOwnership)
+}
+
+// calendar is the free quantity of every day from the first to the last day, the busiest moment of the day counts
+func calendar(capacity int, bookings []util.Booking, first, last time.Time) []entities.DayAvailability {
+	days := []entities.DayAvailability{}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		free := capacity - util.PeakUsage(bookings, day, day.AddDate(0, 0, 1))
+		if free < 0 {
+			free = 0
+		}
+		days = append(days, entities.DayAvailability{Date: day.Format("2006-01-02"), Free_quantity: free})
+	}
+	return days
+}
+
+// 1. create reservation controller
+func (rc ReservationController) CreateReservationController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		var reservationReq ReservationRequestFormat
+		if err := c.Bind(&reservationReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		// an employee reserves for themselves, the admin for any user
+		if idRole == 2 {
+			reservationReq.Id_user, _ = middlewares.GetId(c)
+		}
+		if reservationReq.Id_user == 0 || reservationReq.Id_asset == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "id_user and id_asset are required"))
+		}
+
+		if reservationReq.Quantity == 0 {
+			reservationReq.Quantity = 1
+		}
+		if reservationReq.Quantity < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "quantity must be greater than 0"))
+		}
+
+		start, errStart := time.ParseInLocation(reservationLayout, reservationReq.Start_at, time.Local)
+		end, errEnd := time.ParseInLocation(reservationLayout, reservationReq.End_at, time.Local)
+		if errStart != nil || errEnd != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "start_at and end_at must be formatted as YYYY-MM-DD HH:MM"))
+		}
+		if !start.After(time.Now()) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "start_at must be in the future"))
+		}
+		if !end.After(start) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "end_at must be after start_at"))
+		}
+
+		reservation := entities.Reservation{
+			Id_asset:    reservationReq.Id_asset,
+			Id_user:     reservationReq.Id_user,
+			Quantity:    reservationReq.Quantity,
+			Start_at:    start.Format(util.DateTimeLayout),
+			End_at:      end.Format(util.DateTimeLayout),
+			Description: reservationReq.Description,
+		}
+
+		id, err := rc.repository.Create(reservation)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success create reservation", map[string]int{"id": id}))
+	}
+}
+
+// 2. get reservations controller, an employee only gets their own reservations
+func (rc ReservationController) GetReservationsController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idUser := 0
+		if idRole == 2 {
+			idUser, _ = middlewares.GetId(c)
+		}
+
+		status := c.QueryParam("status")
+		switch status {
+		case "", "pending", "approved", "rejected", "cancelled", "expired", "active":
+		default:
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "status must be pending || approved || rejected || cancelled || expired || active"))
+		}
+
+		reservations, err := rc.repository.Get(idUser, status)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get reservations", reservations))
+	}
+}
+
+// 3. approve, reject or cancel reservation controller
+func (rc ReservationController) UpdateReservationStatusController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+		idUser, _ := middlewares.GetId(c)
+
+		// get id from param
+		idReservation, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var statusReq StatusRequestFormat
+		if err := c.Bind(&statusReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		switch statusReq.Status {
+		// approved by the manager or the admin
+		case "approved", "rejected":
+			if idRole == 2 {
+				return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+			}
+		// cancelled by the admin or the user of the reservation
+		case "cancelled":
+			if idRole != 1 {
+				reservation, err := rc.repository.GetById(idReservation)
+				if err == sql.ErrNoRows {
+					return c.JSON(http.StatusNotFound, response.NotFound("not found", "reservation not found"))
+				}
+				if err != nil {
+					return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+				}
+				if reservation.Id_user != idUser {
+					return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+				}
+			}
+		default:
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "status must be approved || rejected || cancelled"))
+		}
+
+		if err := rc.repository.UpdateStatus(idReservation, statusReq.Status); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update reservation"))
+	}
+}
+
+// 4. availability calendar of an asset controller, free quantity per day between from and to
+func (rc ReservationController) GetAvailabilityController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		_, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idAsset, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		// from today for 30 days by default
+		now := time.Now()
+		first := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		if from := c.QueryParam("from"); from != "" {
+			if first, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "from must be formatted as YYYY-MM-DD"))
+			}
+		}
+		last := first.AddDate(0, 0, 29)
+		if to := c.QueryParam("to"); to != "" {
+			if last, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "to must be formatted as YYYY-MM-DD"))
+			}
+		}
+		if last.Before(first) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "to must not be before from"))
+		}
+		if last.After(first.AddDate(0, 0, maxCalendarDays-1)) {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "maximum "+strconv.Itoa(maxCalendarDays)+" days"))
+		}
+
+		capacity, bookings, err := rc.repository.GetAvailability(idAsset, first, last.AddDate(0, 0, 1))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get availability", calendar(capacity, bookings, first, last)))
+	}
+}
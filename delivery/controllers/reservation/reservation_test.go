@@ -0,0 +1,331 @@
+package reservation
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test create reservation
+func TestCreateReservation(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, map[string]interface{}{"id_asset": 1}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to bind data", 2, map[string]interface{}{"id_asset": "1"}, http.StatusBadRequest, "failed to bind data"},
+		{"admin without user", 1, map[string]interface{}{"id_asset": 1, "start_at": tomorrow + " 09:00", "end_at": tomorrow + " 17:00"}, http.StatusBadRequest, "id_user and id_asset are required"},
+		{"negative quantity", 2, map[string]interface{}{"id_asset": 1, "quantity": -1, "start_at": tomorrow + " 09:00", "end_at": tomorrow + " 17:00"}, http.StatusBadRequest, "quantity must be greater than 0"},
+		{"invalid datetime", 2, map[string]interface{}{"id_asset": 1, "start_at": tomorrow, "end_at": tomorrow + " 17:00"}, http.StatusBadRequest, "start_at and end_at must be formatted as YYYY-MM-DD HH:MM"},
+		{"start in the past", 2, map[string]interface{}{"id_asset": 1, "start_at": yesterday + " 09:00", "end_at": tomorrow + " 17:00"}, http.StatusBadRequest, "start_at must be in the future"},
+		{"end before start", 2, map[string]interface{}{"id_asset": 1, "start_at": tomorrow + " 17:00", "end_at": tomorrow + " 09:00"}, http.StatusBadRequest, "end_at must be after start_at"},
+		{"conflict", 2, map[string]interface{}{"id_asset": 2, "start_at": tomorrow + " 09:00", "end_at": tomorrow + " 17:00"}, http.StatusBadRequest, "not enough units available for this period"},
+		{"success employee reservation", 2, map[string]interface{}{"id_asset": 1, "quantity": 2, "start_at": tomorrow + " 09:00", "end_at": tomorrow + " 17:00"}, http.StatusOK, "success create reservation"},
+		{"success admin reservation", 1, map[string]interface{}{"id_user": 2, "id_asset": 1, "start_at": tomorrow + " 09:00", "end_at": tomorrow + " 17:00"}, http.StatusOK, "success create reservation"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/reservations")
+
+			reservationController := NewReservationController(mockReservationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reservationController.CreateReservationController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test get reservations
+func TestGetReservations(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		query   string
+		code    int
+		message string
+	}{
+		{"invalid status", 1, "/?status=done", http.StatusBadRequest, "status must be pending || approved || rejected || cancelled || expired || active"},
+		{"failed to fetch data", 1, "/?status=expired", http.StatusBadRequest, "failed to fetch data"},
+		{"success get own reservations", 2, "/", http.StatusOK, "success get reservations"},
+		{"success get approved reservations", 3, "/?status=approved", http.StatusOK, "success get reservations"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/reservations")
+
+			reservationController := NewReservationController(mockReservationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reservationController.GetReservationsController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 3. test approve, reject or cancel reservation
+func TestUpdateReservationStatus(t *testing.T) {
+	testCases := []struct {
+		name          string
+		idRole        int
+		idReservation string
+		status        string
+		code          int
+		message       string
+	}{
+		{"failed to convert id", 1, "a", "approved", http.StatusBadRequest, "failed to convert id"},
+		{"invalid status", 1, "1", "active", http.StatusBadRequest, "status must be approved || rejected || cancelled"},
+		{"employee cannot approve", 2, "1", "approved", http.StatusUnauthorized, "unauthorized access"},
+		{"cancel reservation of another user", 2, "2", "cancelled", http.StatusUnauthorized, "unauthorized access"},
+		{"cancel unknown reservation", 2, "100", "cancelled", http.StatusNotFound, "reservation not found"},
+		{"approve rejected reservation", 3, "3", "approved", http.StatusBadRequest, "reservation not found or cannot be approved"},
+		{"success manager approve", 3, "1", "approved", http.StatusOK, "success update reservation"},
+		{"success employee cancel", 2, "1", "cancelled", http.StatusOK, "success update reservation"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"status": tc.status})
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/reservations/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idReservation)
+
+			reservationController := NewReservationController(mockReservationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reservationController.UpdateReservationStatusController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 4. test the division of a manager is checked before a reservation is approved or rejected
+func TestReservationAccess(t *testing.T) {
+	testCases := []struct {
+		name          string
+		idRole        int
+		idReservation string
+		status        string
+		code          int
+		message       string
+	}{
+		{"manager approves reservation of their division", 3, "1", "approved", http.StatusOK, "success update reservation"},
+		{"manager approves reservation of another division", 3, "2", "approved", http.StatusUnauthorized, "unauthorized access"},
+		{"manager rejects reservation of another division", 3, "2", "rejected", http.StatusUnauthorized, "unauthorized access"},
+		{"employee cancels reservation of another user", 2, "2", "cancelled", http.StatusUnauthorized, "unauthorized access"},
+		{"admin approves reservation of any division", 1, "2", "approved", http.StatusOK, "success update reservation"},
+		{"reservation not found", 3, "100", "approved", http.StatusNotFound, "id not found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(map[string]interface{}{"status": tc.status})
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/reservations/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idReservation)
+
+			reservationController := NewReservationController(mockReservationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reservationController.ReservationAccess()(reservationController.UpdateReservationStatusController()))(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 5. test availability calendar
+func TestGetAvailability(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idAsset string
+		query   string
+		code    int
+		message string
+		free    []int
+	}{
+		{"failed to convert id", "a", "/", http.StatusBadRequest, "failed to convert id", nil},
+		{"invalid from", "1", "/?from=01-12-2026", http.StatusBadRequest, "from must be formatted as YYYY-MM-DD", nil},
+		{"to before from", "1", "/?from=2026-12-05&to=2026-12-01", http.StatusBadRequest, "to must not be before from", nil},
+		{"period too long", "1", "/?from=2026-01-01&to=2026-12-31", http.StatusBadRequest, "maximum 92 days", nil},
+		{"asset not found", "100", "/?from=2026-12-01&to=2026-12-05", http.StatusBadRequest, "asset not found", nil},
+		{"success get availability", "1", "/?from=2026-12-01&to=2026-12-05", http.StatusOK, "success get availability", []int{4, 1, 2, 5, 5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", 2)
+
+			req := httptest.NewRequest(http.MethodGet, tc.query, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/assets/:id/availability")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.idAsset)
+
+			reservationController := NewReservationController(mockReservationRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(reservationController.GetAvailabilityController())(context)) {
+				var response struct {
+					Responses
+					Data []entities.DayAvailability `json:"data"`
+				}
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+				if tc.free != nil {
+					var free []int
+					for _, day := range response.Data {
+						free = append(free, day.Free_quantity)
+					}
+					assert.Equal(t, tc.free, free)
+					assert.Equal(t, "2026-12-01", response.Data[0].Date)
+				}
+			}
+		})
+	}
+}
+
+func at(value string) time.Time {
+	date, _ := time.ParseInLocation(util.DateTimeLayout, value, time.Local)
+	return date
+}
+
+type mockReservationRepository struct{}
+
+func (m mockReservationRepository) Create(reservation entities.Reservation) (int, error) {
+	if reservation.Id_asset == 2 {
+		return 0, fmt.Errorf("not enough units available for this period")
+	}
+	return 1, nil
+}
+
+func (m mockReservationRepository) Get(idUser int, status string) ([]entities.Reservation, error) {
+	if status == "expired" {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.Reservation{{Id: 1, Id_asset: 1, Id_user: 1, Quantity: 1, Status: "pending"}}, nil
+}
+
+func (m mockReservationRepository) GetById(id int) (entities.Reservation, error) {
+	switch id {
+	case 1:
+		return entities.Reservation{Id: 1, Id_user: 1, Status: "pending"}, nil
+	case 2:
+		return entities.Reservation{Id: 2, Id_user: 2, Status: "pending"}, nil
+	case 3:
+		return entities.Reservation{Id: 3, Id_user: 1, Status: "rejected"}, nil
+	}
+	return entities.Reservation{}, sql.ErrNoRows
+}
+
+// reservation 2 belongs to a user of another division
+func (m mockReservationRepository) GetOwnership(id, idUser int) (entities.Ownership, error) {
+	switch id {
+	case 1, 3:
+		return entities.Ownership{Id_owner: 1, Owner_divisi: "IT", User_divisi: "IT"}, nil
+	case 2:
+		return entities.Ownership{Id_owner: 2, Owner_divisi: "HR", User_divisi: "IT"}, nil
+	}
+	return entities.Ownership{}, sql.ErrNoRows
+}
+
+func (m mockReservationRepository) UpdateStatus(id int, status string) error {
+	if id == 3 {
+		return fmt.Errorf("reservation not found or cannot be %s", status)
+	}
+	return nil
+}
+
+// 5 units, a loan of 1 unit until the 2nd at noon, reservations of 3 units on the 2nd afternoon and of 3 units
+// on the 3rd which overlaps a reservation of 1 unit only in the morning
+func (m mockReservationRepository) GetAvailability(idAsset int, from, to time.Time) (int, []util.Booking, error) {
+	if idAsset == 100 {
+		return 0, nil, fmt.Errorf("asset not found")
+	}
+	return 5, []util.Booking{
+		{Start: at("2026-11-28 09:00:00"), End: at("2026-12-02 12:00:00"), Quantity: 1},
+		{Start: at("2026-12-02 11:00:00"), End: at("2026-12-02 18:00:00"), Quantity: 3},
+		{Start: at("2026-12-03 08:00:00"), End: at("2026-12-03 10:00:00"), Quantity: 1},
+		{Start: at("2026-12-03 13:00:00"), End: at("2026-12-03 17:00:00"), Quantity: 3},
+	}, nil
+}
+
+func (m mockReservationRepository) StartDue() (int, error) {
+	return 0, nil
+}
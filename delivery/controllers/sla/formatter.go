@@ -0,0 +1,7 @@
+package sla
+
+type SlaRequestFormat struct {
+	Business_days    int `json:"business_days" form:"business_days"`
+	Max_age_days     int `json:"max_age_days" form:"max_age_days"`
+	Id_fallback_user int `json:"id_fallback_user" form:"id_fallback_user"`
+}
@@ -0,0 +1,76 @@
+package sla
+
+import (
+	"net/http"
+	"strconv"
+
+	"sirclo/project/capstone/entities"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	slaRepo "sirclo/project/capstone/repository/sla"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SlaController struct {
+	repository slaRepo.SlaRepo
+}
+
+func NewSlaController(sla slaRepo.SlaRepo) *SlaController {
+	return &SlaController{repository: sla}
+}
+
+// 1. get slas controller
+func (sc SlaController) GetSlasController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		slas, err := sc.repository.Get()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get slas", slas))
+	}
+}
+
+// 2. update sla controller, 0 business days turns escalation off and 0 max age days turns expiry off
+func (sc SlaController) UpdateSlaController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id status from param
+		idStatus, err := strconv.Atoi(c.Param("id_status"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+		if idStatus != 1 && idStatus != 2 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "sla is only set for status 1 || 2"))
+		}
+
+		var slaReq SlaRequestFormat
+		if err := c.Bind(&slaReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if slaReq.Business_days < 0 || slaReq.Max_age_days < 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "business_days and max_age_days must not be negative"))
+		}
+
+		err = sc.repository.Update(entities.RequestSla{
+			Id_status:        idStatus,
+			Business_days:    slaReq.Business_days,
+			Max_age_days:     slaReq.Max_age_days,
+			Id_fallback_user: slaReq.Id_fallback_user,
+		})
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update sla"))
+	}
+}
@@ -0,0 +1,134 @@
+package sla
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test get slas
+func TestGetSlas(t *testing.T) {
+	testCases := []struct {
+		name    string
+		repo    mockSlaRepository
+		idRole  int
+		code    int
+		message string
+	}{
+		{"unauthorized access", mockSlaRepository{}, 3, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to fetch data", mockSlaRepository{fail: true}, 1, http.StatusBadRequest, "failed to fetch data"},
+		{"success", mockSlaRepository{}, 1, http.StatusOK, "success get slas"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/slas")
+
+			slaController := NewSlaController(tc.repo)
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(slaController.GetSlasController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test update sla
+func TestUpdateSla(t *testing.T) {
+	testCases := []struct {
+		name     string
+		idRole   int
+		idStatus string
+		body     map[string]interface{}
+		code     int
+		message  string
+	}{
+		{"unauthorized access", 3, "1", map[string]interface{}{"business_days": 2}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", map[string]interface{}{"business_days": 2}, http.StatusBadRequest, "failed to convert id"},
+		{"status without sla", 1, "3", map[string]interface{}{"business_days": 2}, http.StatusBadRequest, "sla is only set for status 1 || 2"},
+		{"failed to bind data", 1, "1", map[string]interface{}{"business_days": "2"}, http.StatusBadRequest, "failed to bind data"},
+		{"negative days", 1, "2", map[string]interface{}{"business_days": 2, "max_age_days": -1}, http.StatusBadRequest, "business_days and max_age_days must not be negative"},
+		{"fallback not admin", 1, "1", map[string]interface{}{"business_days": 2, "id_fallback_user": 3}, http.StatusBadRequest, "fallback approver of status 1 must be an admin"},
+		{"success", 1, "2", map[string]interface{}{"business_days": 3, "max_age_days": 14, "id_fallback_user": 3}, http.StatusOK, "success update sla"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/slas/:id_status")
+			context.SetParamNames("id_status")
+			context.SetParamValues(tc.idStatus)
+
+			slaController := NewSlaController(mockSlaRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(slaController.UpdateSlaController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// user 3 is a manager, only admins fall back for new requests
+type mockSlaRepository struct {
+	fail bool
+}
+
+func (m mockSlaRepository) Get() ([]entities.RequestSla, error) {
+	if m.fail {
+		return nil, fmt.Errorf("error")
+	}
+	return []entities.RequestSla{{Id_status: 1, Status: "waiting admin", Business_days: 2, Max_age_days: 14}}, nil
+}
+
+func (m mockSlaRepository) Update(sla entities.RequestSla) error {
+	if sla.Id_status == 1 && sla.Id_fallback_user == 3 {
+		return fmt.Errorf("fallback approver of status 1 must be an admin")
+	}
+	return nil
+}
+
+func (m mockSlaRepository) Escalate(now time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m mockSlaRepository) ExpireStale() (int, error) {
+	return 0, nil
+}
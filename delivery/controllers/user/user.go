@@ -9,6 +9,7 @@ import (
 	"sirclo/project/capstone/entities"
 
 	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
 	userRepo "sirclo/project/capstone/repository/user"
 
 	"github.com/labstack/echo/v4"
@@ -97,3 +98,24 @@ func (uc UserController) GetUsersController() echo.HandlerFunc {
 		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get all user", users))
 	}
 }
+
+// 4. deactivate user controller, a user who still holds assets can not be deactivated
+func (uc UserController) DeactivateUserController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		userId, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		if err := uc.repository.Deactivate(userId); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success deactivate user"))
+	}
+}
@@ -353,6 +353,53 @@ func TestGetAllUser(t *testing.T) {
 	})
 }
 
+// 4. test deactivate user
+func TestDeactivateUser(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", http.StatusBadRequest, "failed to convert id"},
+		{"user still holds assets", 1, "3", http.StatusBadRequest, "user still holds 2 assets, return or write them off first"},
+		{"success deactivate user", 1, "2", http.StatusOK, "success deactivate user"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/users/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			userController := NewUserController(mockUserRepository{})
+
+			type Responses struct {
+				Code    int    `json:"code"`
+				Status  string `json:"status"`
+				Message string `json:"message"`
+			}
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(userController.DeactivateUserController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
 type mockUserRepository struct{}
 
 func (m mockUserRepository) Create(entities.User) error {
@@ -384,6 +431,14 @@ func (m mockUserRepository) GetEmail() ([]entities.User, error) {
 	}, nil
 }
 
+// user 3 still holds 2 assets
+func (m mockUserRepository) Deactivate(id int) error {
+	if id == 3 {
+		return fmt.Errorf("user still holds 2 assets, return or write them off first")
+	}
+	return nil
+}
+
 type mockErrorUserRepository struct{}
 
 func (m mockErrorUserRepository) Create(entities.User) error {
@@ -414,3 +469,6 @@ func (m mockErrorUserRepository) GetEmail() ([]entities.User, error) {
 		},
 	}, nil
 }
+func (m mockErrorUserRepository) Deactivate(id int) error {
+	return fmt.Errorf("error")
+}
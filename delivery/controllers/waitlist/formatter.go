@@ -0,0 +1,15 @@
+package waitlist
+
+type JoinRequestFormat struct {
+	Id_user  int `json:"id_user" form:"id_user"`
+	Id_asset int `json:"id_asset" form:"id_asset"`
+}
+
+type PriorityRequestFormat struct {
+	Priority int `json:"priority" form:"priority"`
+}
+
+type ClaimRequestFormat struct {
+	Return_date string `json:"return_date" form:"return_date"`
+	Description string `json:"description" form:"description"`
+}
@@ -0,0 +1,187 @@
+package waitlist
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"sirclo/project/capstone/entities"
+
+	response "sirclo/project/capstone/delivery/common"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	waitlistRepo "sirclo/project/capstone/repository/waitlist"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WaitlistController struct {
+	repository waitlistRepo.WaitlistRepo
+}
+
+func NewWaitlistController(waitlist waitlistRepo.WaitlistRepo) *WaitlistController {
+	return &WaitlistController{repository: waitlist}
+}
+
+// entryOwner gets the entry of the id param, only the admin can act on the entry of another user
+func (wc WaitlistController) entryOwner(c echo.Context, idRole int) (int, error) {
+	idEntry, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+	}
+
+	entry, err := wc.repository.GetById(idEntry)
+	if err == sql.ErrNoRows {
+		return 0, c.JSON(http.StatusNotFound, response.NotFound("not found", "entry not found"))
+	}
+	if err != nil {
+		return 0, c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+	}
+
+	idUser, _ := middlewares.GetId(c)
+	if idRole != 1 && entry.Id_user != idUser {
+		return 0, c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+	}
+	return idEntry, nil
+}
+
+// 1. join waitlist controller
+func (wc WaitlistController) JoinWaitlistController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		var joinReq JoinRequestFormat
+		if err := c.Bind(&joinReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		// an employee joins for themselves, the admin for any user
+		if idRole == 2 {
+			joinReq.Id_user, _ = middlewares.GetId(c)
+		}
+		if joinReq.Id_user == 0 || joinReq.Id_asset == 0 {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "id_user and id_asset are required"))
+		}
+
+		id, err := wc.repository.Join(entities.WaitlistEntry{Id_asset: joinReq.Id_asset, Id_user: joinReq.Id_user})
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success join waitlist", map[string]int{"id": id}))
+	}
+}
+
+// 2. get waitlist controller, an employee only gets their own entries
+func (wc WaitlistController) GetWaitlistController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idAsset := 0
+		if asset := c.QueryParam("id_asset"); asset != "" {
+			if idAsset, err = strconv.Atoi(asset); err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id_asset"))
+			}
+		}
+
+		idUser := 0
+		if idRole == 2 {
+			idUser, _ = middlewares.GetId(c)
+		}
+
+		entries, err := wc.repository.Get(idAsset, idUser)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success get waitlist", entries))
+	}
+}
+
+// 3. override priority of a waiting entry controller
+func (wc WaitlistController) SetPriorityController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole != 1 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		// get id from param
+		idEntry, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+		}
+
+		var priorityReq PriorityRequestFormat
+		if err := c.Bind(&priorityReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+
+		if err := wc.repository.SetPriority(idEntry, priorityReq.Priority); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success update priority"))
+	}
+}
+
+// 4. leave waitlist controller
+func (wc WaitlistController) CancelWaitlistController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idEntry, err := wc.entryOwner(c, idRole)
+		if idEntry == 0 {
+			return err
+		}
+
+		if err := wc.repository.Cancel(idEntry); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperationDefault("success", "success leave waitlist"))
+	}
+}
+
+// 5. claim held unit controller, the unit becomes a request of the user
+func (wc WaitlistController) ClaimWaitlistController() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idRole, err := middlewares.GetIdRole(c)
+		if err != nil || idRole == 3 {
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+
+		idEntry, err := wc.entryOwner(c, idRole)
+		if idEntry == 0 {
+			return err
+		}
+
+		var claimReq ClaimRequestFormat
+		if err := c.Bind(&claimReq); err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to bind data"))
+		}
+		if claimReq.Return_date == "" {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "return_date is required"))
+		}
+
+		// same status as a new request, the admin request waits for the manager
+		request := entities.Request{
+			Id_status:   1,
+			Return_date: claimReq.Return_date,
+			Description: claimReq.Description,
+		}
+		if idRole == 1 {
+			request.Id_status = 2
+		}
+
+		idRequest, err := wc.repository.Claim(idEntry, request)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.BadRequest("failed", err.Error()))
+		}
+		return c.JSON(http.StatusOK, response.SuccessOperation("success", "success claim unit", map[string]int{"id_request": idRequest}))
+	}
+}
@@ -0,0 +1,293 @@
+package waitlist
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	middlewares "sirclo/project/capstone/delivery/middleware"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test join waitlist
+func TestJoinWaitlist(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 3, map[string]interface{}{"id_asset": 1}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to bind data", 2, map[string]interface{}{"id_asset": "1"}, http.StatusBadRequest, "failed to bind data"},
+		{"admin without user", 1, map[string]interface{}{"id_asset": 1}, http.StatusBadRequest, "id_user and id_asset are required"},
+		{"asset available", 2, map[string]interface{}{"id_asset": 2}, http.StatusBadRequest, "asset is available, request it instead"},
+		{"success employee join", 2, map[string]interface{}{"id_asset": 1}, http.StatusOK, "success join waitlist"},
+		{"success admin join", 1, map[string]interface{}{"id_user": 2, "id_asset": 1}, http.StatusOK, "success join waitlist"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/waitlist")
+
+			waitlistController := NewWaitlistController(mockWaitlistRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(waitlistController.JoinWaitlistController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 2. test get waitlist
+func TestGetWaitlist(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		idAsset string
+		code    int
+		message string
+	}{
+		{"failed to convert id_asset", 1, "a", http.StatusBadRequest, "failed to convert id_asset"},
+		{"failed to fetch data", 1, "9", http.StatusBadRequest, "failed to fetch data"},
+		{"success employee", 2, "", http.StatusOK, "success get waitlist"},
+		{"success manager", 3, "1", http.StatusOK, "success get waitlist"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/waitlist?id_asset="+tc.idAsset, nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/waitlist")
+
+			waitlistController := NewWaitlistController(mockWaitlistRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(waitlistController.GetWaitlistController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 3. test set priority
+func TestSetPriority(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized access", 2, "1", map[string]interface{}{"priority": 1}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 1, "a", map[string]interface{}{"priority": 1}, http.StatusBadRequest, "failed to convert id"},
+		{"failed to bind data", 1, "1", map[string]interface{}{"priority": "1"}, http.StatusBadRequest, "failed to bind data"},
+		{"not waiting", 1, "3", map[string]interface{}{"priority": 1}, http.StatusBadRequest, "entry not found or not waiting"},
+		{"success set priority", 1, "1", map[string]interface{}{"priority": 5}, http.StatusOK, "success update priority"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPut, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/waitlist/:id/priority")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			waitlistController := NewWaitlistController(mockWaitlistRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(waitlistController.SetPriorityController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 4. test leave waitlist
+func TestCancelWaitlist(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"unauthorized role", 3, "1", http.StatusUnauthorized, "unauthorized access"},
+		{"failed to convert id", 2, "a", http.StatusBadRequest, "failed to convert id"},
+		{"entry not found", 2, "9", http.StatusNotFound, "entry not found"},
+		{"entry of another user", 2, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"already closed", 2, "3", http.StatusBadRequest, "entry not found or already closed"},
+		{"success owner", 2, "1", http.StatusOK, "success leave waitlist"},
+		{"success admin", 1, "2", http.StatusOK, "success leave waitlist"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/waitlist/:id")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			waitlistController := NewWaitlistController(mockWaitlistRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(waitlistController.CancelWaitlistController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// 5. test claim held unit
+func TestClaimWaitlist(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		body    map[string]interface{}
+		code    int
+		message string
+	}{
+		{"unauthorized role", 3, "1", map[string]interface{}{"return_date": "2026-12-01"}, http.StatusUnauthorized, "unauthorized access"},
+		{"entry of another user", 2, "2", map[string]interface{}{"return_date": "2026-12-01"}, http.StatusUnauthorized, "unauthorized access"},
+		{"failed to bind data", 2, "1", map[string]interface{}{"return_date": 1}, http.StatusBadRequest, "failed to bind data"},
+		{"without return date", 2, "1", map[string]interface{}{"description": "need it"}, http.StatusBadRequest, "return_date is required"},
+		{"nothing held", 2, "3", map[string]interface{}{"return_date": "2026-12-01"}, http.StatusBadRequest, "no unit held for this entry"},
+		{"success claim", 2, "1", map[string]interface{}{"return_date": "2026-12-01"}, http.StatusOK, "success claim unit"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			token, _ := middlewares.CreateToken(1, "asd@mail.com", tc.idRole)
+
+			requestBody, _ := json.Marshal(tc.body)
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBuffer(requestBody))
+			req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+			context.SetPath("/waitlist/:id/claim")
+			context.SetParamNames("id")
+			context.SetParamValues(tc.id)
+
+			waitlistController := NewWaitlistController(mockWaitlistRepository{})
+
+			if assert.NoError(t, middlewares.JWTMiddleware()(waitlistController.ClaimWaitlistController())(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
+
+// entry 1 is waiting and held for user 1, entry 2 belongs to user 2, entry 3 is closed
+type mockWaitlistRepository struct{}
+
+func (m mockWaitlistRepository) Join(entry entities.WaitlistEntry) (int, error) {
+	if entry.Id_asset == 2 {
+		return 0, fmt.Errorf("asset is available, request it instead")
+	}
+	return 1, nil
+}
+
+func (m mockWaitlistRepository) Get(idAsset, idUser int) ([]entities.WaitlistEntry, error) {
+	if idAsset == 9 {
+		return nil, fmt.Errorf("failed to fetch data")
+	}
+	return []entities.WaitlistEntry{{Id: 1, Id_asset: 1, Id_user: 1, Position: 1, Status: "held"}}, nil
+}
+
+func (m mockWaitlistRepository) GetById(id int) (entities.WaitlistEntry, error) {
+	switch id {
+	case 1:
+		return entities.WaitlistEntry{Id: 1, Id_asset: 1, Id_user: 1, Status: "held"}, nil
+	case 2:
+		return entities.WaitlistEntry{Id: 2, Id_asset: 1, Id_user: 2, Status: "waiting"}, nil
+	case 3:
+		return entities.WaitlistEntry{Id: 3, Id_asset: 1, Id_user: 1, Status: "fulfilled"}, nil
+	}
+	return entities.WaitlistEntry{}, sql.ErrNoRows
+}
+
+func (m mockWaitlistRepository) SetPriority(id, priority int) error {
+	if id == 3 {
+		return fmt.Errorf("entry not found or not waiting")
+	}
+	return nil
+}
+
+func (m mockWaitlistRepository) Cancel(id int) error {
+	if id == 3 {
+		return fmt.Errorf("entry not found or already closed")
+	}
+	return nil
+}
+
+func (m mockWaitlistRepository) Claim(id int, request entities.Request) (int, error) {
+	if id != 1 {
+		return 0, fmt.Errorf("no unit held for this entry")
+	}
+	return 10, nil
+}
+
+func (m mockWaitlistRepository) ExpireHolds() (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// RunEvery runs the job in the background, once at start and then every interval
+func RunEvery(name string, interval time.Duration, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := job(); err != nil {
+				log.Println("job "+name+" failed: ", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 1. test a job runs at start and keeps running every interval, even after it failed
+func TestRunEvery(t *testing.T) {
+	t.Run("at start", func(t *testing.T) {
+		var runs int32
+		RunEvery("test", time.Hour, func() error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		})
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 1 }, time.Second, 5*time.Millisecond)
+	})
+	t.Run("every interval after a failure", func(t *testing.T) {
+		var runs int32
+		RunEvery("test", 20*time.Millisecond, func() error {
+			if atomic.AddInt32(&runs, 1) == 1 {
+				return fmt.Errorf("error")
+			}
+			return nil
+		})
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) >= 3 }, time.Second, 5*time.Millisecond)
+	})
+}
@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"log"
+
+	reservationRepo "sirclo/project/capstone/repository/reservation"
+)
+
+// StartReservations turns the approved reservations whose start time has come into loans
+func StartReservations(repository reservationRepo.ReservationRepo) func() error {
+	return func() error {
+		started, err := repository.StartDue()
+		if started > 0 {
+			log.Println("started reservations: ", started)
+		}
+		return err
+	}
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	slaRepo "sirclo/project/capstone/repository/sla"
+)
+
+// EscalateRequests hands the overdue pending requests to their fallback approver and rejects the ones past their max age
+func EscalateRequests(repository slaRepo.SlaRepo) func() error {
+	return func() error {
+		escalated, err := repository.Escalate(time.Now())
+		if escalated > 0 {
+			log.Println("escalated requests: ", escalated)
+		}
+		if err != nil {
+			return err
+		}
+
+		expired, err := repository.ExpireStale()
+		if expired > 0 {
+			log.Println("expired requests: ", expired)
+		}
+		return err
+	}
+}
@@ -0,0 +1,103 @@
+package jobs
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	attachmentRepo "sirclo/project/capstone/repository/attachment"
+)
+
+// the storage used by the upload jobs, replaced in tests
+var (
+	downloadObject = util.DownloadFromS3
+	uploadPhoto    = util.UploadPhotoToS3
+	deleteObject   = util.DeleteFromS3
+)
+
+// CleanupUploads deletes the objects of direct uploads that were never confirmed
+func CleanupUploads(repository attachmentRepo.AttachmentRepo) func() error {
+	return func() error {
+		uploads, err := repository.GetExpiredUploads()
+		if err != nil {
+			return err
+		}
+
+		for _, upload := range uploads {
+			if err := deleteObject(upload.Object_key); err != nil {
+				// keep it pending, it will be retried on the next run
+				continue
+			}
+
+			if err := repository.ExpireUpload(upload.Id); err != nil {
+				return err
+			}
+		}
+
+		if len(uploads) > 0 {
+			log.Println("cleaned up abandoned uploads: ", len(uploads))
+		}
+		return nil
+	}
+}
+
+// ProcessUploads makes the renditions of the confirmed photos and attaches them to their asset, the raw upload is
+// replaced by the re-encoded photo. A file that is not a valid photo is rejected and deleted
+func ProcessUploads(repository attachmentRepo.AttachmentRepo) func() error {
+	return func() error {
+		uploads, err := repository.GetProcessingUploads()
+		if err != nil {
+			return err
+		}
+
+		processed := 0
+		for _, upload := range uploads {
+			// one byte more than allowed so a photo grown since its confirmation is caught by ProcessImage
+			data, err := downloadObject(upload.Object_key, util.MaxImageBytes+1)
+			if err != nil {
+				// keep it processing, it will be retried on the next run
+				continue
+			}
+
+			image, err := util.ProcessImage(bytes.NewReader(data))
+			if err != nil {
+				log.Println("rejected upload ", upload.Id, ": ", err)
+				if err := repository.RejectUpload(upload.Id); err != nil {
+					return err
+				}
+				deleteObject(upload.Object_key)
+				continue
+			}
+
+			fileName := "assets_attachment/" + strconv.Itoa(upload.Id_asset) + "/" + strconv.FormatInt(time.Now().UnixNano(), 10) + "_photo"
+			urlPhoto, err := uploadPhoto(image, fileName)
+			if err != nil {
+				continue
+			}
+
+			err = repository.ConfirmUpload(upload.Id, entities.AssetAttachment{
+				Id_asset:      upload.Id_asset,
+				Type:          upload.Type,
+				Name:          upload.Name,
+				Content_type:  image.ContentType,
+				Url:           urlPhoto.Original,
+				Url_medium:    urlPhoto.Medium,
+				Url_thumbnail: urlPhoto.Thumbnail,
+			})
+			if err != nil {
+				return err
+			}
+			deleteObject(upload.Object_key)
+			processed++
+		}
+
+		if processed > 0 {
+			log.Println("processed uploaded photos: ", processed)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,186 @@
+package jobs
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	attachmentRepo "sirclo/project/capstone/repository/attachment"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAttachmentRepository keeps what the jobs did to each upload, a repository error is returned for upload 9
+type mockAttachmentRepository struct {
+	attachmentRepo.AttachmentRepo
+	uploads   []entities.AssetUpload
+	err       error
+	expired   []int
+	rejected  []int
+	confirmed map[int]entities.AssetAttachment
+}
+
+func (m *mockAttachmentRepository) GetExpiredUploads() ([]entities.AssetUpload, error) {
+	return m.uploads, m.err
+}
+
+func (m *mockAttachmentRepository) GetProcessingUploads() ([]entities.AssetUpload, error) {
+	return m.uploads, m.err
+}
+
+func (m *mockAttachmentRepository) ExpireUpload(id int) error {
+	if id == 9 {
+		return fmt.Errorf("error")
+	}
+	m.expired = append(m.expired, id)
+	return nil
+}
+
+func (m *mockAttachmentRepository) RejectUpload(id int) error {
+	if id == 9 {
+		return fmt.Errorf("error")
+	}
+	m.rejected = append(m.rejected, id)
+	return nil
+}
+
+func (m *mockAttachmentRepository) ConfirmUpload(id int, attachment entities.AssetAttachment) error {
+	if id == 9 {
+		return fmt.Errorf("error")
+	}
+	if m.confirmed == nil {
+		m.confirmed = map[int]entities.AssetAttachment{}
+	}
+	m.confirmed[id] = attachment
+	return nil
+}
+
+// mockStorage replaces the storage of the jobs, the objects in failing cannot be read, written or deleted
+func mockStorage(t *testing.T, objects map[string][]byte, failing map[string]bool) *[]string {
+	var deleted []string
+	download, upload, remove := downloadObject, uploadPhoto, deleteObject
+	t.Cleanup(func() {
+		downloadObject, uploadPhoto, deleteObject = download, upload, remove
+	})
+
+	downloadObject = func(filename string, maxBytes int64) ([]byte, error) {
+		if failing[filename] {
+			return nil, fmt.Errorf("error")
+		}
+		return objects[filename], nil
+	}
+	uploadPhoto = func(image util.ProcessedImage, filename string) (util.PhotoURLs, error) {
+		if failing["renditions"] {
+			return util.PhotoURLs{}, fmt.Errorf("error")
+		}
+		return util.PhotoURLs{Original: filename + image.Extension, Medium: filename + "_medium" + image.Extension, Thumbnail: filename + "_thumb" + image.Extension}, nil
+	}
+	deleteObject = func(filename string) error {
+		if failing[filename] {
+			return fmt.Errorf("error")
+		}
+		deleted = append(deleted, filename)
+		return nil
+	}
+	return &deleted
+}
+
+func testPhoto() []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	return buf.Bytes()
+}
+
+// 1. test cleaning up the uploads that were never confirmed
+func TestCleanupUploads(t *testing.T) {
+	testCases := []struct {
+		name    string
+		uploads []entities.AssetUpload
+		repoErr error
+		failing map[string]bool
+		expired []int
+		deleted []string
+		err     string
+	}{
+		{"nothing to clean up", nil, nil, nil, nil, nil, ""},
+		{"failed to fetch data", nil, fmt.Errorf("error"), nil, nil, nil, "error"},
+		{"abandoned uploads", []entities.AssetUpload{{Id: 1, Object_key: "a"}, {Id: 2, Object_key: "b"}}, nil, nil, []int{1, 2}, []string{"a", "b"}, ""},
+		{"object not deleted is kept pending", []entities.AssetUpload{{Id: 1, Object_key: "a"}, {Id: 2, Object_key: "b"}}, nil, map[string]bool{"a": true}, []int{2}, []string{"b"}, ""},
+		{"failed to expire", []entities.AssetUpload{{Id: 9, Object_key: "a"}, {Id: 2, Object_key: "b"}}, nil, nil, nil, []string{"a"}, "error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deleted := mockStorage(t, nil, tc.failing)
+			repository := &mockAttachmentRepository{uploads: tc.uploads, err: tc.repoErr}
+
+			err := CleanupUploads(repository)()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expired, repository.expired)
+			assert.Equal(t, tc.deleted, *deleted)
+		})
+	}
+}
+
+// 2. test attaching the confirmed photos once their renditions are made
+func TestProcessUploads(t *testing.T) {
+	objects := map[string][]byte{"photo": testPhoto(), "text": []byte("not a photo")}
+	photo := entities.AssetUpload{Id: 1, Id_asset: 3, Type: "photo", Name: "front", Object_key: "photo"}
+
+	testCases := []struct {
+		name      string
+		uploads   []entities.AssetUpload
+		repoErr   error
+		failing   map[string]bool
+		confirmed []int
+		rejected  []int
+		deleted   []string
+		err       string
+	}{
+		{"nothing to process", nil, nil, nil, nil, nil, nil, ""},
+		{"failed to fetch data", nil, fmt.Errorf("error"), nil, nil, nil, nil, "error"},
+		{"photo attached", []entities.AssetUpload{photo}, nil, nil, []int{1}, nil, []string{"photo"}, ""},
+		{"not a photo", []entities.AssetUpload{{Id: 2, Id_asset: 3, Type: "photo", Object_key: "text"}, photo}, nil, nil, []int{1}, []int{2}, []string{"text", "photo"}, ""},
+		{"object not read is retried", []entities.AssetUpload{photo}, nil, map[string]bool{"photo": true}, nil, nil, nil, ""},
+		{"renditions not stored are retried", []entities.AssetUpload{photo}, nil, map[string]bool{"renditions": true}, nil, nil, nil, ""},
+		{"failed to confirm", []entities.AssetUpload{{Id: 9, Id_asset: 3, Type: "photo", Object_key: "photo"}, photo}, nil, nil, nil, nil, nil, "error"},
+		{"failed to reject", []entities.AssetUpload{{Id: 9, Id_asset: 3, Type: "photo", Object_key: "text"}, photo}, nil, nil, nil, nil, nil, "error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deleted := mockStorage(t, objects, tc.failing)
+			repository := &mockAttachmentRepository{uploads: tc.uploads, err: tc.repoErr}
+
+			err := ProcessUploads(repository)()
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var confirmed []int
+			for _, upload := range tc.uploads {
+				if attachment, ok := repository.confirmed[upload.Id]; ok {
+					confirmed = append(confirmed, upload.Id)
+					assert.Equal(t, upload.Id_asset, attachment.Id_asset)
+					assert.Equal(t, upload.Name, attachment.Name)
+					assert.Equal(t, "image/png", attachment.Content_type)
+					assert.Equal(t, attachment.Url[:len(attachment.Url)-len(".png")]+"_thumb.png", attachment.Url_thumbnail)
+				}
+			}
+			assert.Equal(t, tc.confirmed, confirmed)
+			assert.Equal(t, tc.rejected, repository.rejected)
+			assert.Equal(t, tc.deleted, *deleted)
+		})
+	}
+}
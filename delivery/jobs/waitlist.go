@@ -0,0 +1,18 @@
+package jobs
+
+import (
+	"log"
+
+	waitlistRepo "sirclo/project/capstone/repository/waitlist"
+)
+
+// ExpireWaitlistHolds releases the units held too long, they go to the next person in the queue
+func ExpireWaitlistHolds(repository waitlistRepo.WaitlistRepo) func() error {
+	return func() error {
+		expired, err := repository.ExpireHolds()
+		if expired > 0 {
+			log.Println("expired waitlist holds: ", expired)
+		}
+		return err
+	}
+}
@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	response "sirclo/project/capstone/delivery/common"
+	"sirclo/project/capstone/entities"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+// OwnershipLoader gets the owner of the resource with the id and the division of the logged in user
+type OwnershipLoader func(idResource, idUser int) (entities.Ownership, error)
+
+// CanAccess is the access of a role to a resource: the admin (1) to every resource, the employee (2)
+// to their own resources and the manager (3) to the resources of their division
+func CanAccess(idRole, idUser int, ownership entities.Ownership) bool {
+	switch idRole {
+	case 1:
+		return true
+	case 2:
+		return ownership.Id_owner == idUser
+	case 3:
+		return ownership.Owner_divisi != "" && ownership.Owner_divisi == ownership.User_divisi
+	}
+	return false
+}
+
+// CanDecide is the access a delegate gets from the manager they approve for, and the fallback approver to the
+// requests escalated to them: only to approve (3) or reject (4) a request waiting for the manager (2)
+func CanDecide(ownership entities.Ownership, idStatus int) bool {
+	if ownership.Id_delegator == 0 && !ownership.Escalated {
+		return false
+	}
+	return ownership.Id_status == 2 && (idStatus == 3 || idStatus == 4)
+}
+
+// ResourceAccess checks the resource of the id param against the logged in user, it runs after JWTMiddleware
+func ResourceAccess(load OwnershipLoader) echo.MiddlewareFunc {
+	return resourceAccess(load, false)
+}
+
+// DecisionAccess checks the resource like ResourceAccess, and also lets through a delegate or the fallback approver
+// of a request waiting for the manager. Their ownership is kept on the context as "delegation", the handler only
+// takes a decision from them (see CanDecide)
+func DecisionAccess(load OwnershipLoader) echo.MiddlewareFunc {
+	return resourceAccess(load, true)
+}
+
+func resourceAccess(load OwnershipLoader, decision bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			idRole, err := GetIdRole(c)
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+			}
+			idUser, _ := GetId(c)
+
+			idResource, err := strconv.Atoi(c.Param("id"))
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to convert id"))
+			}
+
+			ownership, err := load(idResource, idUser)
+			if err == sql.ErrNoRows {
+				return c.JSON(http.StatusNotFound, response.NotFound("not found", "id not found"))
+			}
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+			}
+
+			if CanAccess(idRole, idUser, ownership) {
+				return next(c)
+			}
+			if decision && (ownership.Id_delegator != 0 || ownership.Escalated) && ownership.Id_status == 2 {
+				c.Set("delegation", ownership)
+				return next(c)
+			}
+			return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "unauthorized access"))
+		}
+	}
+}
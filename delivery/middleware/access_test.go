@@ -0,0 +1,186 @@
+package middlewares
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sirclo/project/capstone/entities"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type Responses struct {
+	Code    int    `json:"code"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// 1. test access of each role to a resource
+func TestCanAccess(t *testing.T) {
+	own := entities.Ownership{Id_owner: 1, Owner_divisi: "IT", User_divisi: "IT"}
+	otherDivision := entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT"}
+	noDivision := entities.Ownership{Id_owner: 2}
+
+	testCases := []struct {
+		name      string
+		idRole    int
+		ownership entities.Ownership
+		expected  bool
+	}{
+		{"admin own resource", 1, own, true},
+		{"admin resource of another division", 1, otherDivision, true},
+		{"employee own resource", 2, own, true},
+		{"employee resource of another user", 2, otherDivision, false},
+		{"manager resource of their division", 3, entities.Ownership{Id_owner: 2, Owner_divisi: "IT", User_divisi: "IT"}, true},
+		{"manager resource of another division", 3, otherDivision, false},
+		{"manager without division", 3, noDivision, false},
+		{"employee delegate of the manager", 2, entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 2}, false},
+		{"manager delegate of another division", 3, entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 2}, false},
+		{"fallback approver of an escalated request", 2, entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Escalated: true, Id_status: 2}, false},
+		{"unknown role", 4, own, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CanAccess(tc.idRole, 1, tc.ownership))
+		})
+	}
+}
+
+// 2. test decisions of a delegate and of the fallback approver
+func TestCanDecide(t *testing.T) {
+	delegate := entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 2}
+	escalated := entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Escalated: true, Id_status: 2}
+	decided := entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 3}
+	noDelegation := entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_status: 2}
+
+	testCases := []struct {
+		name      string
+		ownership entities.Ownership
+		idStatus  int
+		expected  bool
+	}{
+		{"delegate approves", delegate, 3, true},
+		{"delegate rejects", delegate, 4, true},
+		{"delegate hands over", delegate, 6, false},
+		{"delegate returns", delegate, 8, false},
+		{"delegate cancels", delegate, 9, false},
+		{"delegate decides a request already decided", decided, 4, false},
+		{"fallback approver approves", escalated, 3, true},
+		{"fallback approver returns", escalated, 8, false},
+		{"user without delegation approves", noDelegation, 3, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CanDecide(tc.ownership, tc.idStatus))
+		})
+	}
+}
+
+// 3. test resource access middleware
+func TestResourceAccess(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"failed to convert id", 2, "a", http.StatusBadRequest, "failed to convert id"},
+		{"not found", 2, "9", http.StatusNotFound, "id not found"},
+		{"failed to fetch data", 2, "3", http.StatusBadRequest, "failed to fetch data"},
+		{"employee resource of another user", 2, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"manager resource of another division", 3, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"delegate resource of another division", 2, "4", http.StatusUnauthorized, "unauthorized access"},
+		{"employee own resource", 2, "1", http.StatusOK, "next"},
+		{"manager resource of their division", 3, "1", http.StatusOK, "next"},
+		{"admin any resource", 1, "2", http.StatusOK, "next"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, message := serveAccess(ResourceAccess(loadResource), tc.idRole, tc.id)
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.message, message)
+		})
+	}
+}
+
+// 4. test decision access middleware, the handler is told when the user decides as a delegate
+func TestDecisionAccess(t *testing.T) {
+	testCases := []struct {
+		name    string
+		idRole  int
+		id      string
+		code    int
+		message string
+	}{
+		{"not found", 2, "9", http.StatusNotFound, "id not found"},
+		{"employee resource of another user", 2, "2", http.StatusUnauthorized, "unauthorized access"},
+		{"delegate request waiting for the manager", 2, "4", http.StatusOK, "delegation"},
+		{"delegate request already decided", 2, "5", http.StatusUnauthorized, "unauthorized access"},
+		{"manager delegate of another division", 3, "4", http.StatusOK, "delegation"},
+		{"employee own resource", 2, "1", http.StatusOK, "next"},
+		{"admin any resource", 1, "4", http.StatusOK, "next"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, message := serveAccess(DecisionAccess(loadResource), tc.idRole, tc.id)
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.message, message)
+		})
+	}
+}
+
+// resource 1 belongs to user 1, resource 2 to user 2 of another division, resource 3 fails, resource 4 waits for
+// the manager user 1 approves for and resource 5 was already decided, any other is not found
+func loadResource(idResource, idUser int) (entities.Ownership, error) {
+	switch idResource {
+	case 1:
+		return entities.Ownership{Id_owner: 1, Owner_divisi: "IT", User_divisi: "IT"}, nil
+	case 2:
+		return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT"}, nil
+	case 3:
+		return entities.Ownership{}, fmt.Errorf("error")
+	case 4:
+		return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 2}, nil
+	case 5:
+		return entities.Ownership{Id_owner: 2, Owner_divisi: "Finance", User_divisi: "IT", Id_delegator: 7, Id_status: 3}, nil
+	}
+	return entities.Ownership{}, sql.ErrNoRows
+}
+
+// serveAccess runs the access middleware for the resource with the id, the next handler answers "delegation" when
+// the user passed as a delegate and "next" otherwise
+func serveAccess(access echo.MiddlewareFunc, idRole int, id string) (int, string) {
+	e := echo.New()
+	token, _ := CreateToken(1, "asd@mail.com", idRole)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, fmt.Sprintf("Bearer %v", token))
+	res := httptest.NewRecorder()
+	context := e.NewContext(req, res)
+	context.SetPath("/resources/:id")
+	context.SetParamNames("id")
+	context.SetParamValues(id)
+
+	next := func(c echo.Context) error {
+		message := "next"
+		if _, ok := c.Get("delegation").(entities.Ownership); ok {
+			message = "delegation"
+		}
+		return c.JSON(http.StatusOK, Responses{Code: http.StatusOK, Status: "success", Message: message})
+	}
+
+	JWTMiddleware()(access(next))(context)
+
+	var response Responses
+	json.Unmarshal(res.Body.Bytes(), &response)
+	return res.Code, response.Message
+}
@@ -2,8 +2,12 @@ package middlewares
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
+	response "sirclo/project/capstone/delivery/common"
+
 	"github.com/golang-jwt/jwt"
 	echo "github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -16,6 +20,39 @@ func JWTMiddleware() echo.MiddlewareFunc {
 	})
 }
 
+// ActiveUser turns away the token of a user deactivated after they logged in. A request without a valid token goes
+// on to its route, which checks the token itself
+func ActiveUser(isActive func(id int) (bool, error)) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			auth := c.Request().Header.Get(echo.HeaderAuthorization)
+			if !strings.HasPrefix(auth, "Bearer ") {
+				return next(c)
+			}
+
+			token, err := jwt.Parse(strings.TrimPrefix(auth, "Bearer "), func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, fmt.Errorf("unexpected signing method")
+				}
+				return []byte("rahasia"), nil
+			})
+			if err != nil || !token.Valid {
+				return next(c)
+			}
+			userid, _ := token.Claims.(jwt.MapClaims)["id"].(float64)
+
+			active, err := isActive(int(userid))
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, response.BadRequest("failed", "failed to fetch data"))
+			}
+			if !active {
+				return c.JSON(http.StatusUnauthorized, response.UnauthorizedRequest("unauthorized", "user is deactivated"))
+			}
+			return next(c)
+		}
+	}
+}
+
 func CreateToken(userid int, email string, idrole int) (string, error) {
 	claims := jwt.MapClaims{}
 	claims["authorized"] = true
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// 1. test the token of a deactivated user is turned away, user 2 was deactivated and the lookup of user 3 fails
+func TestActiveUser(t *testing.T) {
+	isActive := func(id int) (bool, error) {
+		if id == 3 {
+			return false, fmt.Errorf("error")
+		}
+		return id != 2, nil
+	}
+	token := func(id int) string {
+		token, _ := CreateToken(id, "asd@mail.com", 2)
+		return "Bearer " + token
+	}
+
+	testCases := []struct {
+		name          string
+		authorization string
+		code          int
+		message       string
+	}{
+		{"without token", "", http.StatusOK, "next"},
+		{"invalid token", "Bearer invalid", http.StatusOK, "next"},
+		{"active user", token(1), http.StatusOK, "next"},
+		{"deactivated user", token(2), http.StatusUnauthorized, "user is deactivated"},
+		{"failed to fetch data", token(3), http.StatusBadRequest, "failed to fetch data"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			res := httptest.NewRecorder()
+			context := e.NewContext(req, res)
+
+			next := func(c echo.Context) error {
+				return c.JSON(http.StatusOK, Responses{Code: http.StatusOK, Status: "success", Message: "next"})
+			}
+
+			if assert.NoError(t, ActiveUser(isActive)(next)(context)) {
+				var response Responses
+				json.Unmarshal(res.Body.Bytes(), &response)
+
+				assert.Equal(t, tc.code, res.Code)
+				assert.Equal(t, tc.message, response.Message)
+			}
+		})
+	}
+}
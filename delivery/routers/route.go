@@ -2,9 +2,23 @@ package route
 
 import (
 	"sirclo/project/capstone/delivery/controllers/asset"
+	"sirclo/project/capstone/delivery/controllers/attachment"
+	"sirclo/project/capstone/delivery/controllers/audit"
 	"sirclo/project/capstone/delivery/controllers/auth"
+	"sirclo/project/capstone/delivery/controllers/comment"
+	"sirclo/project/capstone/delivery/controllers/delegation"
+	"sirclo/project/capstone/delivery/controllers/kit"
+	"sirclo/project/capstone/delivery/controllers/location"
+	"sirclo/project/capstone/delivery/controllers/maintenance"
+	"sirclo/project/capstone/delivery/controllers/notification"
+	"sirclo/project/capstone/delivery/controllers/offboarding"
+	"sirclo/project/capstone/delivery/controllers/report"
 	"sirclo/project/capstone/delivery/controllers/request"
+	"sirclo/project/capstone/delivery/controllers/reservation"
+	"sirclo/project/capstone/delivery/controllers/sla"
 	"sirclo/project/capstone/delivery/controllers/user"
+	"sirclo/project/capstone/delivery/controllers/vendor"
+	"sirclo/project/capstone/delivery/controllers/waitlist"
 
 	middlewares "sirclo/project/capstone/delivery/middleware"
 
@@ -16,7 +30,21 @@ func RegisterPath(
 	loginController *auth.AuthController,
 	userController *user.UserController,
 	assetController *asset.AssetController,
-	requestController *request.RequestController) {
+	requestController *request.RequestController,
+	attachmentController *attachment.AttachmentController,
+	vendorController *vendor.VendorController,
+	reportController *report.ReportController,
+	locationController *location.LocationController,
+	maintenanceController *maintenance.MaintenanceController,
+	auditController *audit.AuditController,
+	kitController *kit.KitController,
+	reservationController *reservation.ReservationController,
+	waitlistController *waitlist.WaitlistController,
+	notificationController *notification.NotificationController,
+	commentController *comment.CommentController,
+	delegationController *delegation.DelegationController,
+	slaController *sla.SlaController,
+	offboardingController *offboarding.OffboardingController) {
 
 	// login
 	e.POST("/login", loginController.LoginEmailController())
@@ -25,6 +53,14 @@ func RegisterPath(
 	e.POST("/users", userController.CreateUserController())
 	e.GET("/users/:id", userController.GetByIdController())
 	e.GET("/users", userController.GetUsersController())
+	e.GET("/users/:id/damages", requestController.GetDamageHistoryController(), middlewares.JWTMiddleware())
+	e.DELETE("/users/:id", userController.DeactivateUserController(), middlewares.JWTMiddleware())
+
+	// offboarding
+	e.GET("/users/:id/holdings", offboardingController.GetHoldingsController(), middlewares.JWTMiddleware())
+	e.POST("/users/:id/offboarding", offboardingController.StartOffboardingController(), middlewares.JWTMiddleware())
+	e.GET("/users/:id/offboarding", offboardingController.GetOffboardingController(), middlewares.JWTMiddleware())
+	e.PUT("/offboarding-items/:id/write-off", offboardingController.WriteOffController(), middlewares.JWTMiddleware())
 
 	// asset
 	e.GET("/assets", assetController.GetAssetsController())
@@ -33,16 +69,111 @@ func RegisterPath(
 	e.GET("assets/detail/:id", assetController.GetAssetByIdController(), middlewares.JWTMiddleware())
 	e.PUT("assets/update/:id", assetController.UpdateAssetController(), middlewares.JWTMiddleware())
 	e.GET("assets/usage/:id", assetController.GetHistoryUsageController(), middlewares.JWTMiddleware())
+	e.GET("/assets/lookup", assetController.LookupAssetController(), middlewares.JWTMiddleware())
+	e.POST("/assets/labels", assetController.PrintLabelsController(), middlewares.JWTMiddleware())
+	e.GET("/assets/:id/label", assetController.GetAssetLabelController(), middlewares.JWTMiddleware())
 	e.GET("/assets/categories", assetController.GetCategoriesController())
+	e.PUT("/assets/categories/:id/depreciation", assetController.UpdateCategoryDepreciationController(), middlewares.JWTMiddleware())
+	e.PUT("/assets/:id/lifecycle", assetController.UpdateLifecycleController(), middlewares.JWTMiddleware())
+	e.GET("/assets/:id/lifecycle", assetController.GetLifecycleController(), middlewares.JWTMiddleware())
+
+	// asset attachment
+	e.POST("/assets/:id/attachments", attachmentController.UploadAttachmentController(), middlewares.JWTMiddleware())
+	e.GET("/assets/:id/attachments", attachmentController.GetAttachmentsController(), middlewares.JWTMiddleware())
+	e.PUT("/assets/:id/attachments/order", attachmentController.ReorderAttachmentsController(), middlewares.JWTMiddleware())
+	e.PUT("/assets/:id/attachments/:id_attachment/primary", attachmentController.SetPrimaryAttachmentController(), middlewares.JWTMiddleware())
+	e.DELETE("/assets/:id/attachments/:id_attachment", attachmentController.DeleteAttachmentController(), middlewares.JWTMiddleware())
+	e.POST("/assets/:id/uploads", attachmentController.CreateUploadController(), middlewares.JWTMiddleware())
+	e.POST("/assets/:id/uploads/:id_upload/confirm", attachmentController.ConfirmUploadController(), middlewares.JWTMiddleware())
+
+	// vendor
+	e.POST("/vendors", vendorController.CreateVendorController(), middlewares.JWTMiddleware())
+	e.GET("/vendors", vendorController.GetVendorsController(), middlewares.JWTMiddleware())
+	e.GET("/vendors/:id", vendorController.GetVendorByIdController(), middlewares.JWTMiddleware())
+	e.PUT("/vendors/:id", vendorController.UpdateVendorController(), middlewares.JWTMiddleware())
+	e.DELETE("/vendors/:id", vendorController.DeleteVendorController(), middlewares.JWTMiddleware())
+
+	// location
+	e.POST("/locations", locationController.CreateLocationController(), middlewares.JWTMiddleware())
+	e.GET("/locations", locationController.GetLocationsController(), middlewares.JWTMiddleware())
+	e.GET("/locations/:id", locationController.GetLocationByIdController(), middlewares.JWTMiddleware())
+	e.PUT("/locations/:id", locationController.UpdateLocationController(), middlewares.JWTMiddleware())
+	e.DELETE("/locations/:id", locationController.DeleteLocationController(), middlewares.JWTMiddleware())
+	e.PUT("/assets/:id/location", locationController.MoveAssetController(), middlewares.JWTMiddleware())
+	e.GET("/assets/:id/location/history", locationController.GetAssetMovesController(), middlewares.JWTMiddleware())
+
+	// maintenance
+	e.GET("/maintenance", maintenanceController.GetMaintenanceController(), middlewares.JWTMiddleware())
+	e.PUT("/maintenance/:id/close", maintenanceController.CloseMaintenanceController(), middlewares.JWTMiddleware())
+
+	// audit
+	e.POST("/audits", auditController.CreateAuditController(), middlewares.JWTMiddleware())
+	e.GET("/audits", auditController.GetAuditsController(), middlewares.JWTMiddleware())
+	e.GET("/audits/:id", auditController.GetAuditController(), middlewares.JWTMiddleware())
+	e.POST("/audits/:id/scans", auditController.ScanAuditController(), middlewares.JWTMiddleware())
+	e.PUT("/audits/:id/counts", auditController.CountAuditController(), middlewares.JWTMiddleware())
+	e.POST("/audits/:id/sign-off", auditController.SignOffAuditController(), middlewares.JWTMiddleware())
+
+	// kit
+	e.POST("/kits", kitController.CreateKitController(), middlewares.JWTMiddleware())
+	e.GET("/kits", kitController.GetKitsController(), middlewares.JWTMiddleware())
+	e.GET("/kits/:id", kitController.GetKitByIdController(), middlewares.JWTMiddleware())
+	e.PUT("/kits/:id", kitController.UpdateKitController(), middlewares.JWTMiddleware())
+	e.DELETE("/kits/:id", kitController.DeleteKitController(), middlewares.JWTMiddleware())
+	e.POST("/kits/:id/requests", requestController.CreateKitRequestController(), middlewares.JWTMiddleware())
+	e.GET("/kit-requests/:id", requestController.GetKitRequestController(), middlewares.JWTMiddleware(), requestController.KitRequestAccess())
+	e.PUT("/kit-requests/:id", requestController.UpdateKitRequestStatusController(), middlewares.JWTMiddleware(), requestController.KitRequestAccess())
+	e.POST("/kit-requests/:id/return", requestController.ReturnKitRequestController(), middlewares.JWTMiddleware(), requestController.KitRequestAccess())
+
+	// reservation
+	e.POST("/reservations", reservationController.CreateReservationController(), middlewares.JWTMiddleware())
+	e.GET("/reservations", reservationController.GetReservationsController(), middlewares.JWTMiddleware())
+	e.PUT("/reservations/:id", reservationController.UpdateReservationStatusController(), middlewares.JWTMiddleware(), reservationController.ReservationAccess())
+	e.GET("/assets/:id/availability", reservationController.GetAvailabilityController(), middlewares.JWTMiddleware())
+
+	// waitlist
+	e.POST("/waitlist", waitlistController.JoinWaitlistController(), middlewares.JWTMiddleware())
+	e.GET("/waitlist", waitlistController.GetWaitlistController(), middlewares.JWTMiddleware())
+	e.PUT("/waitlist/:id/priority", waitlistController.SetPriorityController(), middlewares.JWTMiddleware())
+	e.DELETE("/waitlist/:id", waitlistController.CancelWaitlistController(), middlewares.JWTMiddleware())
+	e.POST("/waitlist/:id/claim", waitlistController.ClaimWaitlistController(), middlewares.JWTMiddleware())
+
+	// notification
+	e.GET("/notifications", notificationController.GetNotificationsController(), middlewares.JWTMiddleware())
+	e.PUT("/notifications/:id/read", notificationController.MarkReadController(), middlewares.JWTMiddleware())
+
+	// report
+	e.GET("/reports/valuation", reportController.GetValuationController(), middlewares.JWTMiddleware())
 
 	// request
 	e.POST("/requests", requestController.CreateRequestEmployee(), middlewares.JWTMiddleware())
 	e.GET("/requests", requestController.GetRequestsController(), middlewares.JWTMiddleware())
-	e.GET("requests/:id", requestController.GetRequestByIdController(), middlewares.JWTMiddleware())
-	e.PUT("requests/:id", requestController.UpdateRequestStatus(), middlewares.JWTMiddleware())
+	e.POST("/requests/bulk", requestController.BulkUpdateRequestStatus(), middlewares.JWTMiddleware())
+	e.GET("requests/:id", requestController.GetRequestByIdController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
+	e.PUT("requests/:id", requestController.UpdateRequestStatus(), middlewares.JWTMiddleware(), requestController.RequestDecisionAccess())
+	e.POST("/requests/:id/extensions", requestController.CreateExtensionController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
+	e.GET("/requests/:id/extensions", requestController.GetExtensionsController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
+	e.PUT("/extensions/:id", requestController.DecideExtensionController(), middlewares.JWTMiddleware(), requestController.ExtensionAccess())
+	e.POST("/requests/:id/comments", commentController.CreateCommentController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
+	e.GET("/requests/:id/comments", commentController.GetCommentsController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
+	e.GET("/requests/:id/events", requestController.GetRequestEventsController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
+	e.POST("/requests/:id/transfer", requestController.TransferRequestController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
+
+	// delegation
+	e.POST("/delegations", delegationController.CreateDelegationController(), middlewares.JWTMiddleware())
+	e.GET("/delegations", delegationController.GetDelegationsController(), middlewares.JWTMiddleware())
+	e.DELETE("/delegations/:id", delegationController.RevokeDelegationController(), middlewares.JWTMiddleware())
+
+	// sla
+	e.GET("/slas", slaController.GetSlasController(), middlewares.JWTMiddleware())
+	e.PUT("/slas/:id_status", slaController.UpdateSlaController(), middlewares.JWTMiddleware())
+
+	// desk
+	e.POST("/desk/check-out", requestController.CheckOutController(), middlewares.JWTMiddleware())
+	e.POST("/desk/check-in", requestController.CheckInController(), middlewares.JWTMiddleware())
 
 	// employee
 	e.GET("employee/activity", requestController.GetRequestActivityController(), middlewares.JWTMiddleware())
 	e.GET("employee/history", requestController.GetRequestHistoryController(), middlewares.JWTMiddleware())
-	e.GET("employee/request_loan/:id", requestController.GetRequestByIdController(), middlewares.JWTMiddleware())
+	e.GET("employee/request_loan/:id", requestController.GetRequestByIdController(), middlewares.JWTMiddleware(), requestController.RequestAccess())
 }
@@ -1,15 +1,46 @@
 package entities
 
 type Asset struct {
-	Id               int    `json:"id" form:"id"`
-	Id_category      int    `json:"id_category" form:"id_category"`
-	Is_maintenance   bool   `json:"is_maintenance" form:"is_maintenance"`
-	Name             string `json:"name" form:"name"`
-	Description      string `json:"description" form:"description"`
-	Initial_quantity int    `json:"initial_quantity" form:"initial_quantity"`
-	Avail_quantity   int    `json:"avail_quantity" form:"avail_quantity"`
-	Photo            string `json:"photo" form:"photo"`
-	Category         string `json:"category" form:"category"`
+	Id               int     `json:"id" form:"id"`
+	Tag              string  `json:"tag" form:"tag"`
+	Id_category      int     `json:"id_category" form:"id_category"`
+	Is_maintenance   bool    `json:"is_maintenance" form:"is_maintenance"`
+	Name             string  `json:"name" form:"name"`
+	Description      string  `json:"description" form:"description"`
+	Initial_quantity int     `json:"initial_quantity" form:"initial_quantity"`
+	Avail_quantity   int     `json:"avail_quantity" form:"avail_quantity"`
+	Photo            string  `json:"photo" form:"photo"`
+	Photo_medium     string  `json:"photo_medium" form:"photo_medium"`
+	Photo_thumbnail  string  `json:"photo_thumbnail" form:"photo_thumbnail"`
+	Category         string  `json:"category" form:"category"`
+	Lifecycle_status string  `json:"lifecycle_status" form:"lifecycle_status"`
+	Id_vendor        int     `json:"id_vendor" form:"id_vendor"`
+	Vendor           string  `json:"vendor" form:"vendor"`
+	Purchase_date    string  `json:"purchase_date" form:"purchase_date"`
+	Purchase_price   float64 `json:"purchase_price" form:"purchase_price"`
+	Invoice_number   string  `json:"invoice_number" form:"invoice_number"`
+	Warranty_expiry  string  `json:"warranty_expiry" form:"warranty_expiry"`
+	Id_location      int     `json:"id_location" form:"id_location"`
+	Location         string  `json:"location" form:"location"`
+}
+
+// AssetFilter narrows and orders the assets listed, a zero value lists every asset in service. Has_max tells
+// a max quantity of 0 from no max quantity
+type AssetFilter struct {
+	Search        string
+	Category      string
+	Maintenance   string
+	Avail         string
+	Lifecycle     string
+	Location      int
+	Warranty_days int
+	Min_quantity  int
+	Max_quantity  int
+	Has_max       bool
+	Sort          string
+	Order         string
+	Limit         int
+	Offset        int
 }
 
 type SummaryAsset struct {
@@ -39,6 +70,78 @@ type History struct {
 }
 
 type Categories struct {
+	Id                  int     `json:"id" form:"id"`
+	Description         string  `json:"description" form:"description"`
+	Depreciation_method string  `json:"depreciation_method" form:"depreciation_method"`
+	Useful_life_months  int     `json:"useful_life_months" form:"useful_life_months"`
+	Salvage_rate        float64 `json:"salvage_rate" form:"salvage_rate"`
+	Declining_factor    float64 `json:"declining_factor" form:"declining_factor"`
+}
+
+type AssetAttachment struct {
+	Id            int    `json:"id" form:"id"`
+	Id_asset      int    `json:"id_asset" form:"id_asset"`
+	Type          string `json:"type" form:"type"`
+	Document_type string `json:"document_type" form:"document_type"`
+	Name          string `json:"name" form:"name"`
+	Content_type  string `json:"content_type" form:"content_type"`
+	Url           string `json:"url" form:"url"`
+	Url_medium    string `json:"url_medium" form:"url_medium"`
+	Url_thumbnail string `json:"url_thumbnail" form:"url_thumbnail"`
+	Position      int    `json:"position" form:"position"`
+	Is_primary    bool   `json:"is_primary" form:"is_primary"`
+	Created_at    string `json:"created_at" form:"created_at"`
+}
+
+type AssetUpload struct {
+	Id            int    `json:"id" form:"id"`
+	Id_asset      int    `json:"id_asset" form:"id_asset"`
+	Id_user       int    `json:"id_user" form:"id_user"`
+	Object_key    string `json:"object_key" form:"object_key"`
+	Type          string `json:"type" form:"type"`
+	Document_type string `json:"document_type" form:"document_type"`
+	Name          string `json:"name" form:"name"`
+	Content_type  string `json:"content_type" form:"content_type"`
+	Status        string `json:"status" form:"status"`
+	Expires_at    string `json:"expires_at" form:"expires_at"`
+}
+
+type AssetLifecycleEvent struct {
 	Id          int    `json:"id" form:"id"`
-	Description string `json:"description" form:"description"`
+	Id_asset    int    `json:"id_asset" form:"id_asset"`
+	Id_user     int    `json:"id_user" form:"id_user"`
+	User_name   string `json:"user_name" form:"user_name"`
+	From_status string `json:"from_status" form:"from_status"`
+	To_status   string `json:"to_status" form:"to_status"`
+	Reason      string `json:"reason" form:"reason"`
+	Event_date  string `json:"event_date" form:"event_date"`
+}
+
+type AssetDisposal struct {
+	Id              int     `json:"id" form:"id"`
+	Id_asset        int     `json:"id_asset" form:"id_asset"`
+	Id_user         int     `json:"id_user" form:"id_user"`
+	Method          string  `json:"method" form:"method"`
+	Recovered_value float64 `json:"recovered_value" form:"recovered_value"`
+	Disposal_date   string  `json:"disposal_date" form:"disposal_date"`
+	Notes           string  `json:"notes" form:"notes"`
+}
+
+type AssetLifecycle struct {
+	Id_asset         int                   `json:"id_asset" form:"id_asset"`
+	Lifecycle_status string                `json:"lifecycle_status" form:"lifecycle_status"`
+	History          []AssetLifecycleEvent `json:"history" form:"history"`
+	Disposal         *AssetDisposal        `json:"disposal" form:"disposal"`
+}
+
+type AssetMaintenance struct {
+	Id            int    `json:"id" form:"id"`
+	Id_asset      int    `json:"id_asset" form:"id_asset"`
+	Asset_name    string `json:"asset_name" form:"asset_name"`
+	Id_request    int    `json:"id_request" form:"id_request"`
+	Id_assessment int    `json:"id_assessment" form:"id_assessment"`
+	Status        string `json:"status" form:"status"`
+	Notes         string `json:"notes" form:"notes"`
+	Opened_at     string `json:"opened_at" form:"opened_at"`
+	Closed_at     string `json:"closed_at" form:"closed_at"`
 }
@@ -0,0 +1,36 @@
+package entities
+
+type AuditSession struct {
+	Id            int          `json:"id" form:"id"`
+	Id_location   int          `json:"id_location" form:"id_location"`
+	Location      string       `json:"location" form:"location"`
+	Id_user       int          `json:"id_user" form:"id_user"`
+	Status        string       `json:"status" form:"status"`
+	Notes         string       `json:"notes" form:"notes"`
+	Started_at    string       `json:"started_at" form:"started_at"`
+	Signed_off_by int          `json:"signed_off_by" form:"signed_off_by"`
+	Signed_off_at string       `json:"signed_off_at" form:"signed_off_at"`
+	Report        *AuditReport `json:"report,omitempty" form:"report"`
+}
+
+type AuditItem struct {
+	Id_asset   int    `json:"id_asset" form:"id_asset"`
+	Tag        string `json:"tag" form:"tag"`
+	Name       string `json:"name" form:"name"`
+	Expected   int    `json:"expected" form:"expected"`
+	On_loan    int    `json:"on_loan" form:"on_loan"`
+	Found      int    `json:"found" form:"found"`
+	Missing    int    `json:"missing" form:"missing"`
+	Unexpected int    `json:"unexpected" form:"unexpected"`
+	Result     string `json:"result" form:"result"`
+}
+
+type AuditReport struct {
+	Id_session       int         `json:"id_session" form:"id_session"`
+	Generated_at     string      `json:"generated_at" form:"generated_at"`
+	Total_expected   int         `json:"total_expected" form:"total_expected"`
+	Total_found      int         `json:"total_found" form:"total_found"`
+	Total_missing    int         `json:"total_missing" form:"total_missing"`
+	Total_unexpected int         `json:"total_unexpected" form:"total_unexpected"`
+	Items            []AuditItem `json:"items" form:"items"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+type RequestComment struct {
+	Id         int    `json:"id" form:"id"`
+	Id_request int    `json:"id_request" form:"id_request"`
+	Id_user    int    `json:"id_user" form:"id_user"`
+	User_name  string `json:"user_name" form:"user_name"`
+	Body       string `json:"body" form:"body"`
+	Internal   bool   `json:"internal" form:"internal"`
+	Created_at string `json:"created_at" form:"created_at"`
+	Updated_at string `json:"updated_at" form:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package entities
+
+type Delegation struct {
+	Id            int    `json:"id" form:"id"`
+	Id_manager    int    `json:"id_manager" form:"id_manager"`
+	Manager_name  string `json:"manager_name" form:"manager_name"`
+	Id_delegate   int    `json:"id_delegate" form:"id_delegate"`
+	Delegate_name string `json:"delegate_name" form:"delegate_name"`
+	Start_at      string `json:"start_at" form:"start_at"`
+	End_at        string `json:"end_at" form:"end_at"`
+	Revoked_at    string `json:"revoked_at" form:"revoked_at"`
+	Created_at    string `json:"created_at" form:"created_at"`
+}
+
+type RequestEvent struct {
+	Id             int    `json:"id" form:"id"`
+	Id_request     int    `json:"id_request" form:"id_request"`
+	Id_user        int    `json:"id_user" form:"id_user"`
+	User_name      string `json:"user_name" form:"user_name"`
+	Id_on_behalf   int    `json:"id_on_behalf" form:"id_on_behalf"`
+	On_behalf_name string `json:"on_behalf_name" form:"on_behalf_name"`
+	Id_status      int    `json:"id_status" form:"id_status"`
+	Status         string `json:"status" form:"status"`
+	Created_at     string `json:"created_at" form:"created_at"`
+}
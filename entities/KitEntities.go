@@ -0,0 +1,28 @@
+package entities
+
+type Kit struct {
+	Id          int       `json:"id" form:"id"`
+	Name        string    `json:"name" form:"name"`
+	Description string    `json:"description" form:"description"`
+	Items       []KitItem `json:"items" form:"items"`
+}
+
+type KitItem struct {
+	Id_asset       int    `json:"id_asset" form:"id_asset"`
+	Asset_name     string `json:"asset_name" form:"asset_name"`
+	Quantity       int    `json:"quantity" form:"quantity"`
+	Avail_quantity int    `json:"avail_quantity" form:"avail_quantity"`
+}
+
+type KitRequest struct {
+	Id          int               `json:"id" form:"id"`
+	Id_kit      int               `json:"id_kit" form:"id_kit"`
+	Kit_name    string            `json:"kit_name" form:"kit_name"`
+	Id_user     int               `json:"id_user" form:"id_user"`
+	User_name   string            `json:"user_name" form:"user_name"`
+	Return_date string            `json:"return_date" form:"return_date"`
+	Description string            `json:"description" form:"description"`
+	On_loan     int               `json:"on_loan" form:"on_loan"`
+	Returned    int               `json:"returned" form:"returned"`
+	Requests    []RequestResponse `json:"requests" form:"requests"`
+}
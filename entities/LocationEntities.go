@@ -0,0 +1,23 @@
+package entities
+
+type Location struct {
+	Id        int        `json:"id" form:"id"`
+	Id_parent int        `json:"id_parent" form:"id_parent"`
+	Type      string     `json:"type" form:"type"`
+	Name      string     `json:"name" form:"name"`
+	Path      string     `json:"path" form:"path"`
+	Children  []Location `json:"children,omitempty" form:"children"`
+}
+
+type AssetLocationMove struct {
+	Id            int    `json:"id" form:"id"`
+	Id_asset      int    `json:"id_asset" form:"id_asset"`
+	Id_user       int    `json:"id_user" form:"id_user"`
+	User_name     string `json:"user_name" form:"user_name"`
+	From_location int    `json:"from_location" form:"from_location"`
+	From_name     string `json:"from_name" form:"from_name"`
+	To_location   int    `json:"to_location" form:"to_location"`
+	To_name       string `json:"to_name" form:"to_name"`
+	Notes         string `json:"notes" form:"notes"`
+	Moved_at      string `json:"moved_at" form:"moved_at"`
+}
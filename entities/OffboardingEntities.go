@@ -0,0 +1,38 @@
+package entities
+
+type Holding struct {
+	Id_request     int    `json:"id_request" form:"id_request"`
+	Id_kit_request int    `json:"id_kit_request" form:"id_kit_request"`
+	Id_asset       int    `json:"id_asset" form:"id_asset"`
+	Tag            string `json:"tag" form:"tag"`
+	Asset_name     string `json:"asset_name" form:"asset_name"`
+	Category       string `json:"category" form:"category"`
+	Quantity       int    `json:"quantity" form:"quantity"`
+	Id_status      int    `json:"id_status" form:"id_status"`
+	Status         string `json:"status" form:"status"`
+	Request_date   string `json:"request_date" form:"request_date"`
+	Return_date    string `json:"return_date" form:"return_date"`
+}
+
+type Offboarding struct {
+	Id           int               `json:"id" form:"id"`
+	Id_user      int               `json:"id_user" form:"id_user"`
+	User_name    string            `json:"user_name" form:"user_name"`
+	Id_admin     int               `json:"id_admin" form:"id_admin"`
+	Pending      int               `json:"pending" form:"pending"`
+	Completed    bool              `json:"completed" form:"completed"`
+	Created_at   string            `json:"created_at" form:"created_at"`
+	Completed_at string            `json:"completed_at" form:"completed_at"`
+	Items        []OffboardingItem `json:"items" form:"items"`
+}
+
+type OffboardingItem struct {
+	Id               int    `json:"id" form:"id"`
+	Id_request       int    `json:"id_request" form:"id_request"`
+	Id_asset         int    `json:"id_asset" form:"id_asset"`
+	Asset_name       string `json:"asset_name" form:"asset_name"`
+	Quantity         int    `json:"quantity" form:"quantity"`
+	Status           string `json:"status" form:"status"`
+	Write_off_reason string `json:"write_off_reason" form:"write_off_reason"`
+	Written_off_at   string `json:"written_off_at" form:"written_off_at"`
+}
@@ -0,0 +1,49 @@
+package entities
+
+type AssetValuation struct {
+	Id                       int     `json:"id" form:"id"`
+	Name                     string  `json:"name" form:"name"`
+	Quantity                 int     `json:"quantity" form:"quantity"`
+	Purchase_date            string  `json:"purchase_date" form:"purchase_date"`
+	Unit_cost                float64 `json:"unit_cost" form:"unit_cost"`
+	Cost                     float64 `json:"cost" form:"cost"`
+	Accumulated_depreciation float64 `json:"accumulated_depreciation" form:"accumulated_depreciation"`
+	Book_value               float64 `json:"book_value" form:"book_value"`
+}
+
+type CategoryValuation struct {
+	Id_category              int              `json:"id_category" form:"id_category"`
+	Category                 string           `json:"category" form:"category"`
+	Depreciation_method      string           `json:"depreciation_method" form:"depreciation_method"`
+	Useful_life_months       int              `json:"useful_life_months" form:"useful_life_months"`
+	Salvage_rate             float64          `json:"salvage_rate" form:"salvage_rate"`
+	Total_assets             int              `json:"total_assets" form:"total_assets"`
+	Unvalued_assets          int              `json:"unvalued_assets" form:"unvalued_assets"`
+	Cost                     float64          `json:"cost" form:"cost"`
+	Accumulated_depreciation float64          `json:"accumulated_depreciation" form:"accumulated_depreciation"`
+	Book_value               float64          `json:"book_value" form:"book_value"`
+	Assets                   []AssetValuation `json:"assets" form:"assets"`
+}
+
+type ValuationReport struct {
+	As_of                    string              `json:"as_of" form:"as_of"`
+	Cost                     float64             `json:"cost" form:"cost"`
+	Accumulated_depreciation float64             `json:"accumulated_depreciation" form:"accumulated_depreciation"`
+	Book_value               float64             `json:"book_value" form:"book_value"`
+	Categories               []CategoryValuation `json:"categories" form:"categories"`
+}
+
+// ValuationAsset is an asset with the depreciation policy of its category, as read for the valuation report
+type ValuationAsset struct {
+	Id                  int
+	Name                string
+	Quantity            int
+	Purchase_date       string
+	Purchase_price      float64
+	Id_category         int
+	Category            string
+	Depreciation_method string
+	Useful_life_months  int
+	Salvage_rate        float64
+	Declining_factor    float64
+}
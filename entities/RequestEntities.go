@@ -1,34 +1,80 @@
 package entities
 
 type Request struct {
-	Id               int    `json:"id" form:"id"`
-	Id_user          int    `json:"id_user" form:"id_user"`
-	Id_asset         int    `json:"id_asset" form:"id_asset"`
-	Id_status        int    `json:"id_status" form:"id_status"`
-	Request_date     string `json:"request_date" form:"request_date"`
-	Return_date      string `json:"return_date" form:"return_date"`
-	Description      string `json:"description" form:"description"`
-	User_name        string `json:"user_name" form:"user_name"`
-	Asset_name       string `json:"asset_name" form:"asset_name"`
-	Category         string `json:"category" form:"category"`
-	Avail_quantity   int    `json:"avail_quantity" form:"avail_quantity"`
-	Initial_quantity int    `json:"initial_quantity" form:"initial_quantity"`
-	Status           string `json:"status" form:"status"`
+	Id                int    `json:"id" form:"id"`
+	Id_user           int    `json:"id_user" form:"id_user"`
+	Id_asset          int    `json:"id_asset" form:"id_asset"`
+	Id_status         int    `json:"id_status" form:"id_status"`
+	Quantity          int    `json:"quantity" form:"quantity"`
+	Returned_quantity int    `json:"returned_quantity" form:"returned_quantity"`
+	Request_date      string `json:"request_date" form:"request_date"`
+	Return_date       string `json:"return_date" form:"return_date"`
+	Description       string `json:"description" form:"description"`
+	User_name         string `json:"user_name" form:"user_name"`
+	Asset_name        string `json:"asset_name" form:"asset_name"`
+	Category          string `json:"category" form:"category"`
+	Avail_quantity    int    `json:"avail_quantity" form:"avail_quantity"`
+	Initial_quantity  int    `json:"initial_quantity" form:"initial_quantity"`
+	Status            string `json:"status" form:"status"`
+	Condition_grade   string `json:"condition_grade" form:"condition_grade"`
+	Damage_notes      string `json:"damage_notes" form:"damage_notes"`
+	Reason            string `json:"reason" form:"reason"`
 }
 
 type RequestResponse struct {
-	Id             int    `json:"id" form:"id"`
-	Id_user        int    `json:"id_user" form:"id_user"`
-	Id_asset       int    `json:"id_asset" form:"id_asset"`
-	Id_status      int    `json:"id_status" form:"id_status"`
-	Id_category    int    `json:"id_category" form:"id_category"`
-	Request_date   string `json:"request_date" form:"request_date"`
-	Return_date    string `json:"return_date" form:"return_date"`
-	Description    string `json:"description" form:"description"`
-	User_name      string `json:"user_name" form:"user_name"`
-	Asset_name     string `json:"asset_name" form:"asset_name"`
-	Category       string `json:"category" form:"category"`
-	Avail_quantity int    `json:"avail_quantity" form:"avail_quantity"`
-	Status         string `json:"status" form:"status"`
-	Photo          string `json:"photo" form:"photo"`
+	Id                int    `json:"id" form:"id"`
+	Id_user           int    `json:"id_user" form:"id_user"`
+	Id_asset          int    `json:"id_asset" form:"id_asset"`
+	Id_status         int    `json:"id_status" form:"id_status"`
+	Quantity          int    `json:"quantity" form:"quantity"`
+	Returned_quantity int    `json:"returned_quantity" form:"returned_quantity"`
+	Id_category       int    `json:"id_category" form:"id_category"`
+	Request_date      string `json:"request_date" form:"request_date"`
+	Return_date       string `json:"return_date" form:"return_date"`
+	Description       string `json:"description" form:"description"`
+	User_name         string `json:"user_name" form:"user_name"`
+	Asset_name        string `json:"asset_name" form:"asset_name"`
+	Category          string `json:"category" form:"category"`
+	Avail_quantity    int    `json:"avail_quantity" form:"avail_quantity"`
+	Status            string `json:"status" form:"status"`
+	Photo             string `json:"photo" form:"photo"`
+	Photo_thumbnail   string `json:"photo_thumbnail" form:"photo_thumbnail"`
+	Id_location       int    `json:"id_location" form:"id_location"`
+	Pickup_location   string `json:"pickup_location" form:"pickup_location"`
+	Extension_status  string `json:"extension_status" form:"extension_status"`
+	Extension_date    string `json:"extension_date" form:"extension_date"`
+	Sla_breached      bool   `json:"sla_breached" form:"sla_breached"`
+}
+
+type RequestExtension struct {
+	Id                  int    `json:"id" form:"id"`
+	Id_request          int    `json:"id_request" form:"id_request"`
+	Id_user             int    `json:"id_user" form:"id_user"`
+	Current_return_date string `json:"current_return_date" form:"current_return_date"`
+	Return_date         string `json:"return_date" form:"return_date"`
+	Reason              string `json:"reason" form:"reason"`
+	Status              string `json:"status" form:"status"`
+	Id_approver         int    `json:"id_approver" form:"id_approver"`
+	Decided_at          string `json:"decided_at" form:"decided_at"`
+	Created_at          string `json:"created_at" form:"created_at"`
+}
+
+type ReturnAssessment struct {
+	Id             int           `json:"id" form:"id"`
+	Id_request     int           `json:"id_request" form:"id_request"`
+	Id_asset       int           `json:"id_asset" form:"id_asset"`
+	Asset_name     string        `json:"asset_name" form:"asset_name"`
+	Id_user        int           `json:"id_user" form:"id_user"`
+	Id_assessor    int           `json:"id_assessor" form:"id_assessor"`
+	Grade          string        `json:"grade" form:"grade"`
+	Quantity       int           `json:"quantity" form:"quantity"`
+	Damage_notes   string        `json:"damage_notes" form:"damage_notes"`
+	Photos         []ReturnPhoto `json:"photos" form:"photos"`
+	Id_maintenance int           `json:"id_maintenance" form:"id_maintenance"`
+	Created_at     string        `json:"created_at" form:"created_at"`
+}
+
+type ReturnPhoto struct {
+	Url           string `json:"url" form:"url"`
+	Url_thumbnail string `json:"url_thumbnail" form:"url_thumbnail"`
 }
@@ -0,0 +1,21 @@
+package entities
+
+type Reservation struct {
+	Id          int    `json:"id" form:"id"`
+	Id_asset    int    `json:"id_asset" form:"id_asset"`
+	Asset_name  string `json:"asset_name" form:"asset_name"`
+	Id_user     int    `json:"id_user" form:"id_user"`
+	User_name   string `json:"user_name" form:"user_name"`
+	Quantity    int    `json:"quantity" form:"quantity"`
+	Start_at    string `json:"start_at" form:"start_at"`
+	End_at      string `json:"end_at" form:"end_at"`
+	Status      string `json:"status" form:"status"`
+	Description string `json:"description" form:"description"`
+	Id_request  int    `json:"id_request" form:"id_request"`
+	Created_at  string `json:"created_at" form:"created_at"`
+}
+
+type DayAvailability struct {
+	Date          string `json:"date" form:"date"`
+	Free_quantity int    `json:"free_quantity" form:"free_quantity"`
+}
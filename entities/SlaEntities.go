@@ -0,0 +1,11 @@
+package entities
+
+type RequestSla struct {
+	Id_status        int    `json:"id_status" form:"id_status"`
+	Status           string `json:"status" form:"status"`
+	Business_days    int    `json:"business_days" form:"business_days"`
+	Max_age_days     int    `json:"max_age_days" form:"max_age_days"`
+	Id_fallback_user int    `json:"id_fallback_user" form:"id_fallback_user"`
+	Fallback_name    string `json:"fallback_name" form:"fallback_name"`
+	Updated_at       string `json:"updated_at" form:"updated_at"`
+}
@@ -8,4 +8,14 @@ type User struct {
 	Divisi   string `json:"divisi" form:"divisi"`
 	Id_role  int    `json:"id_role" form:"id_role"`
 	Role     string `json:"role" form:"role"`
+	Badge    string `json:"badge" form:"badge"`
+}
+
+type Ownership struct {
+	Id_owner     int    `json:"id_owner" form:"id_owner"`
+	Owner_divisi string `json:"owner_divisi" form:"owner_divisi"`
+	User_divisi  string `json:"user_divisi" form:"user_divisi"`
+	Id_delegator int    `json:"id_delegator" form:"id_delegator"`
+	Escalated    bool   `json:"escalated" form:"escalated"`
+	Id_status    int    `json:"id_status" form:"id_status"`
 }
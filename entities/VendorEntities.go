@@ -0,0 +1,10 @@
+package entities
+
+type Vendor struct {
+	Id           int    `json:"id" form:"id"`
+	Name         string `json:"name" form:"name"`
+	Contact_name string `json:"contact_name" form:"contact_name"`
+	Email        string `json:"email" form:"email"`
+	Phone        string `json:"phone" form:"phone"`
+	Address      string `json:"address" form:"address"`
+}
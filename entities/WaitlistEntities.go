@@ -0,0 +1,25 @@
+package entities
+
+type WaitlistEntry struct {
+	Id         int    `json:"id" form:"id"`
+	Id_asset   int    `json:"id_asset" form:"id_asset"`
+	Asset_name string `json:"asset_name" form:"asset_name"`
+	Id_user    int    `json:"id_user" form:"id_user"`
+	User_name  string `json:"user_name" form:"user_name"`
+	Priority   int    `json:"priority" form:"priority"`
+	Position   int    `json:"position" form:"position"`
+	Status     string `json:"status" form:"status"`
+	Held_until string `json:"held_until" form:"held_until"`
+	Id_request int    `json:"id_request" form:"id_request"`
+	Created_at string `json:"created_at" form:"created_at"`
+}
+
+type Notification struct {
+	Id           int    `json:"id" form:"id"`
+	Id_user      int    `json:"id_user" form:"id_user"`
+	Type         string `json:"type" form:"type"`
+	Message      string `json:"message" form:"message"`
+	Id_reference int    `json:"id_reference" form:"id_reference"`
+	Read_at      string `json:"read_at" form:"read_at"`
+	Created_at   string `json:"created_at" form:"created_at"`
+}
@@ -28,6 +28,10 @@ CREATE TABLE IF NOT EXISTS `users` (
 CREATE TABLE IF NOT EXISTS `categories` (
   `id` int NOT NULL AUTO_INCREMENT,
   `description` varchar(255) NOT NULL,
+  `depreciation_method` varchar(20) NOT NULL DEFAULT 'straight_line',
+  `useful_life_months` int NOT NULL DEFAULT 0,
+  `salvage_rate` decimal(5,4) NOT NULL DEFAULT 0,
+  `declining_factor` decimal(4,2) NOT NULL DEFAULT 2,
   `created_at` datetime DEFAULT NULL,
   `updated_at` datetime DEFAULT NULL,
   `deleted_at` datetime DEFAULT NULL,
@@ -43,8 +47,34 @@ CREATE TABLE IF NOT EXISTS `status_check` (
   PRIMARY KEY (`id`)
 );
 
+CREATE TABLE IF NOT EXISTS `vendors` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `contact_name` varchar(255) DEFAULT NULL,
+  `email` varchar(255) DEFAULT NULL,
+  `phone` varchar(50) DEFAULT NULL,
+  `address` text DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  `deleted_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `locations` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_parent` int DEFAULT NULL,
+  `type` varchar(20) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  `deleted_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `locations_parent_FK` FOREIGN KEY (`id_parent`) REFERENCES `locations` (`id`)
+);
+
 CREATE TABLE IF NOT EXISTS `assets` (
   `id` int NOT NULL AUTO_INCREMENT,
+  `tag` varchar(50) DEFAULT NULL,
   `id_category` int NOT NULL,
   `is_maintenance` BOOL DEFAULT false,
   `name` varchar(255) NOT NULL,
@@ -52,11 +82,58 @@ CREATE TABLE IF NOT EXISTS `assets` (
   `initial_quantity` int NOT NULL,
   `avail_quantity` int NOT NULL,
   `photo` varchar(1000) DEFAULT NULL,
+  `photo_medium` varchar(1000) DEFAULT NULL,
+  `photo_thumbnail` varchar(1000) DEFAULT NULL,
+  `lifecycle_status` varchar(20) NOT NULL DEFAULT 'in_service',
+  `id_vendor` int DEFAULT NULL,
+  `purchase_date` date DEFAULT NULL,
+  `purchase_price` decimal(15,2) DEFAULT NULL,
+  `invoice_number` varchar(100) DEFAULT NULL,
+  `warranty_expiry` date DEFAULT NULL,
+  `id_location` int DEFAULT NULL,
   `created_at` datetime DEFAULT NULL,
   `updated_at` datetime DEFAULT NULL,
   `deleted_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
-  CONSTRAINT `assets_FK` FOREIGN KEY (`id_category`) REFERENCES `categories` (`id`)
+  UNIQUE KEY `assets_tag` (`tag`),
+  KEY `assets_warranty_expiry` (`warranty_expiry`),
+  FULLTEXT KEY `assets_search` (`name`, `description`),
+  CONSTRAINT `assets_FK` FOREIGN KEY (`id_category`) REFERENCES `categories` (`id`),
+  CONSTRAINT `assets_vendors_FK` FOREIGN KEY (`id_vendor`) REFERENCES `vendors` (`id`),
+  CONSTRAINT `assets_locations_FK` FOREIGN KEY (`id_location`) REFERENCES `locations` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `kits` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `description` text DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  `deleted_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `kit_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_kit` int NOT NULL,
+  `id_asset` int NOT NULL,
+  `quantity` int NOT NULL DEFAULT 1,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `kit_items_kit_asset` (`id_kit`, `id_asset`),
+  CONSTRAINT `kit_items_kits_FK` FOREIGN KEY (`id_kit`) REFERENCES `kits` (`id`),
+  CONSTRAINT `kit_items_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `kit_requests` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_kit` int NOT NULL,
+  `id_user` int NOT NULL,
+  `return_date` datetime DEFAULT NULL,
+  `description` text DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `kit_requests_kits_FK` FOREIGN KEY (`id_kit`) REFERENCES `kits` (`id`),
+  CONSTRAINT `kit_requests_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
 );
 
 CREATE TABLE IF NOT EXISTS `requests` (
@@ -64,15 +141,346 @@ CREATE TABLE IF NOT EXISTS `requests` (
   `id_user` int NOT NULL,
   `id_asset` int NOT NULL,
   `id_status` int NOT NULL,
+  `quantity` int NOT NULL DEFAULT 1,
+  `returned_quantity` int NOT NULL DEFAULT 0,
   `request_date` datetime DEFAULT NULL,
   `return_date` datetime DEFAULT NULL,
   `description` text DEFAULT NULL,
+  `id_kit_request` int DEFAULT NULL,
+  `sla_breached_status` int DEFAULT NULL,
+  `id_escalated_to` int DEFAULT NULL,
+  `escalated_at` datetime DEFAULT NULL,
   `created_at` datetime DEFAULT NULL,
   `updated_at` datetime DEFAULT NULL,
   `deleted_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
+  CONSTRAINT `requests_escalated_to_FK` FOREIGN KEY (`id_escalated_to`) REFERENCES `users` (`id`),
+  CONSTRAINT `requests_kit_requests_FK` FOREIGN KEY (`id_kit_request`) REFERENCES `kit_requests` (`id`),
   CONSTRAINT `requests_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
   CONSTRAINT `requests_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
   CONSTRAINT `requests_status_FK` FOREIGN KEY (`id_status`) REFERENCES `status_check` (`id`)
 );
 
+CREATE TABLE IF NOT EXISTS `asset_attachments` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `document_type` varchar(50) DEFAULT NULL,
+  `name` varchar(255) NOT NULL,
+  `content_type` varchar(100) NOT NULL,
+  `url` varchar(1000) NOT NULL,
+  `url_medium` varchar(1000) DEFAULT NULL,
+  `url_thumbnail` varchar(1000) DEFAULT NULL,
+  `position` int NOT NULL DEFAULT 0,
+  `is_primary` BOOL DEFAULT false,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  `deleted_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `asset_attachments_asset` (`id_asset`, `position`),
+  CONSTRAINT `asset_attachments_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `asset_uploads` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `object_key` varchar(1000) NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `document_type` varchar(50) DEFAULT NULL,
+  `name` varchar(255) NOT NULL,
+  `content_type` varchar(100) NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'pending',
+  `expires_at` datetime NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `asset_uploads_status` (`status`, `expires_at`),
+  CONSTRAINT `asset_uploads_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `asset_uploads_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `asset_lifecycle_events` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `from_status` varchar(20) DEFAULT NULL,
+  `to_status` varchar(20) NOT NULL,
+  `reason` text DEFAULT NULL,
+  `event_date` date NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `asset_lifecycle_events_asset` (`id_asset`),
+  CONSTRAINT `asset_lifecycle_events_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `asset_lifecycle_events_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `asset_disposals` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `method` varchar(20) NOT NULL,
+  `recovered_value` decimal(15,2) NOT NULL DEFAULT 0,
+  `disposal_date` date NOT NULL,
+  `notes` text DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `asset_disposals_asset` (`id_asset`),
+  CONSTRAINT `asset_disposals_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `asset_disposals_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `asset_location_moves` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `from_location` int DEFAULT NULL,
+  `to_location` int NOT NULL,
+  `notes` text DEFAULT NULL,
+  `moved_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `asset_location_moves_asset` (`id_asset`),
+  CONSTRAINT `asset_location_moves_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `asset_location_moves_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `asset_location_moves_from_FK` FOREIGN KEY (`from_location`) REFERENCES `locations` (`id`),
+  CONSTRAINT `asset_location_moves_to_FK` FOREIGN KEY (`to_location`) REFERENCES `locations` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `request_handovers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_request` int NOT NULL,
+  `id_user` int NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `condition_notes` text DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `request_handovers_request` (`id_request`),
+  CONSTRAINT `request_handovers_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`),
+  CONSTRAINT `request_handovers_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `return_assessments` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_request` int NOT NULL,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `id_assessor` int DEFAULT NULL,
+  `grade` varchar(20) NOT NULL,
+  `quantity` int NOT NULL DEFAULT 1,
+  `damage_notes` text DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `return_assessments_user` (`id_user`, `grade`),
+  CONSTRAINT `return_assessments_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`),
+  CONSTRAINT `return_assessments_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `return_assessments_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `return_assessments_assessors_FK` FOREIGN KEY (`id_assessor`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `return_assessment_photos` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_assessment` int NOT NULL,
+  `url` varchar(1000) NOT NULL,
+  `url_thumbnail` varchar(1000) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `return_assessment_photos_assessments_FK` FOREIGN KEY (`id_assessment`) REFERENCES `return_assessments` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `asset_maintenance` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `id_request` int DEFAULT NULL,
+  `id_assessment` int DEFAULT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'open',
+  `notes` text DEFAULT NULL,
+  `opened_at` datetime NOT NULL,
+  `closed_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `asset_maintenance_status` (`status`),
+  CONSTRAINT `asset_maintenance_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `asset_maintenance_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`),
+  CONSTRAINT `asset_maintenance_assessments_FK` FOREIGN KEY (`id_assessment`) REFERENCES `return_assessments` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `audit_sessions` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_location` int DEFAULT NULL,
+  `id_user` int NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'open',
+  `notes` text DEFAULT NULL,
+  `report` json DEFAULT NULL,
+  `started_at` datetime NOT NULL,
+  `signed_off_by` int DEFAULT NULL,
+  `signed_off_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `audit_sessions_locations_FK` FOREIGN KEY (`id_location`) REFERENCES `locations` (`id`),
+  CONSTRAINT `audit_sessions_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `audit_sessions_signed_off_FK` FOREIGN KEY (`signed_off_by`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `audit_counts` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_session` int NOT NULL,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `found_quantity` int NOT NULL DEFAULT 0,
+  `counted_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `audit_counts_session_asset` (`id_session`, `id_asset`),
+  CONSTRAINT `audit_counts_sessions_FK` FOREIGN KEY (`id_session`) REFERENCES `audit_sessions` (`id`),
+  CONSTRAINT `audit_counts_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `audit_counts_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `reservations` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `quantity` int NOT NULL DEFAULT 1,
+  `start_at` datetime NOT NULL,
+  `end_at` datetime NOT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'pending',
+  `description` text DEFAULT NULL,
+  `id_request` int DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `reservations_asset_period` (`id_asset`, `start_at`, `end_at`),
+  CONSTRAINT `reservations_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `reservations_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `reservations_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `waitlist_entries` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_asset` int NOT NULL,
+  `id_user` int NOT NULL,
+  `priority` int NOT NULL DEFAULT 0,
+  `status` varchar(20) NOT NULL DEFAULT 'waiting',
+  `held_until` datetime DEFAULT NULL,
+  `id_request` int DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `waitlist_entries_queue` (`id_asset`, `status`, `priority`),
+  CONSTRAINT `waitlist_entries_assets_FK` FOREIGN KEY (`id_asset`) REFERENCES `assets` (`id`),
+  CONSTRAINT `waitlist_entries_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `waitlist_entries_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `notifications` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_user` int NOT NULL,
+  `type` varchar(50) NOT NULL,
+  `message` text NOT NULL,
+  `id_reference` int DEFAULT NULL,
+  `read_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `notifications_user` (`id_user`, `read_at`),
+  CONSTRAINT `notifications_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
+);
+
+-- status 9: dibatalkan, a request withdrawn by the employee before hand over
+INSERT IGNORE INTO `status_check` (`id`, `description`, `created_at`) VALUES (9, 'dibatalkan', now());
+
+CREATE TABLE IF NOT EXISTS `request_extensions` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_request` int NOT NULL,
+  `id_user` int NOT NULL,
+  `current_return_date` datetime DEFAULT NULL,
+  `return_date` datetime NOT NULL,
+  `reason` text DEFAULT NULL,
+  `status` varchar(20) NOT NULL DEFAULT 'pending',
+  `id_approver` int DEFAULT NULL,
+  `decided_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `request_extensions_request` (`id_request`, `status`),
+  CONSTRAINT `request_extensions_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`),
+  CONSTRAINT `request_extensions_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `request_extensions_approver_FK` FOREIGN KEY (`id_approver`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `request_comments` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_request` int NOT NULL,
+  `id_user` int NOT NULL,
+  `body` text NOT NULL,
+  `internal` tinyint(1) NOT NULL DEFAULT 0,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `request_comments_request` (`id_request`, `created_at`),
+  CONSTRAINT `request_comments_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`),
+  CONSTRAINT `request_comments_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `approval_delegations` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_manager` int NOT NULL,
+  `id_delegate` int NOT NULL,
+  `start_at` datetime NOT NULL,
+  `end_at` datetime NOT NULL,
+  `revoked_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `approval_delegations_delegate` (`id_delegate`, `start_at`, `end_at`),
+  CONSTRAINT `approval_delegations_manager_FK` FOREIGN KEY (`id_manager`) REFERENCES `users` (`id`),
+  CONSTRAINT `approval_delegations_delegate_FK` FOREIGN KEY (`id_delegate`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `request_events` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_request` int NOT NULL,
+  `id_user` int DEFAULT NULL,
+  `id_on_behalf` int DEFAULT NULL,
+  `id_status` int NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `request_events_request` (`id_request`),
+  CONSTRAINT `request_events_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`),
+  CONSTRAINT `request_events_users_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `request_events_on_behalf_FK` FOREIGN KEY (`id_on_behalf`) REFERENCES `users` (`id`),
+  CONSTRAINT `request_events_status_FK` FOREIGN KEY (`id_status`) REFERENCES `status_check` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `request_slas` (
+  `id_status` int NOT NULL,
+  `business_days` int NOT NULL DEFAULT 0,
+  `max_age_days` int NOT NULL DEFAULT 0,
+  `id_fallback_user` int DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id_status`),
+  CONSTRAINT `request_slas_status_FK` FOREIGN KEY (`id_status`) REFERENCES `status_check` (`id`),
+  CONSTRAINT `request_slas_fallback_FK` FOREIGN KEY (`id_fallback_user`) REFERENCES `users` (`id`)
+);
+
+-- the admin acts on a new request within 2 business days, the manager within 3, any request still pending after 14 days is rejected
+INSERT IGNORE INTO `request_slas` (`id_status`, `business_days`, `max_age_days`, `updated_at`) VALUES (1, 2, 14, now()), (2, 3, 14, now());
+
+CREATE TABLE IF NOT EXISTS `offboardings` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_user` int NOT NULL,
+  `id_admin` int NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `completed_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `offboardings_user` (`id_user`, `completed_at`),
+  CONSTRAINT `offboardings_user_FK` FOREIGN KEY (`id_user`) REFERENCES `users` (`id`),
+  CONSTRAINT `offboardings_admin_FK` FOREIGN KEY (`id_admin`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `offboarding_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `id_offboarding` int NOT NULL,
+  `id_request` int NOT NULL,
+  `quantity` int NOT NULL DEFAULT 1,
+  `write_off_reason` text DEFAULT NULL,
+  `id_written_off_by` int DEFAULT NULL,
+  `written_off_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `offboarding_items_offboardings_FK` FOREIGN KEY (`id_offboarding`) REFERENCES `offboardings` (`id`),
+  CONSTRAINT `offboarding_items_requests_FK` FOREIGN KEY (`id_request`) REFERENCES `requests` (`id`),
+  CONSTRAINT `offboarding_items_written_off_by_FK` FOREIGN KEY (`id_written_off_by`) REFERENCES `users` (`id`)
+);
@@ -4,8 +4,12 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 
 	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+	"sirclo/project/capstone/util/pagination"
 )
 
 type assetRepo struct {
@@ -16,64 +20,162 @@ func NewAssetRepo(db *sql.DB) *assetRepo {
 	return &assetRepo{db: db}
 }
 
-// create asset
+// create asset, the asset tag printed on its label is derived from the new id
 func (ar *assetRepo) Create(asset entities.Asset) error {
-	query := (`INSERT INTO assets (id_category, is_maintenance, name, description, initial_quantity, avail_quantity, photo, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, now(), now())`)
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
 
-	statement, err := ar.db.Prepare(query)
+	if asset.Lifecycle_status == "" {
+		asset.Lifecycle_status = "in_service"
+	}
+
+	res, err := tx.Exec(`INSERT INTO assets (id_category, is_maintenance, name, description, initial_quantity, avail_quantity, photo, photo_medium, photo_thumbnail, lifecycle_status, id_vendor, purchase_date, purchase_price, invoice_number, warranty_expiry, id_location, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, now(), now())`,
+		asset.Id_category, asset.Is_maintenance, asset.Name, asset.Description, asset.Initial_quantity, asset.Avail_quantity, asset.Photo, asset.Photo_medium, asset.Photo_thumbnail, asset.Lifecycle_status,
+		nullInt(asset.Id_vendor), nullString(asset.Purchase_date), asset.Purchase_price, nullString(asset.Invoice_number), nullString(asset.Warranty_expiry), nullInt(asset.Id_location))
 	if err != nil {
 		log.Println(err)
 		return err
 	}
 
-	defer statement.Close()
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
 
-	_, err = statement.Exec(asset.Id_category, asset.Is_maintenance, asset.Name, asset.Description, asset.Initial_quantity, asset.Avail_quantity, asset.Photo)
+	_, err = tx.Exec(`UPDATE assets SET tag = ? WHERE id = ?`, util.AssetTag(int(id)), id)
 	if err != nil {
 		log.Println(err)
 		return err
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-// get all asset with filter
-func (ar *assetRepo) Get(category, maintenance, avail string, limit, offset int) ([]entities.Asset, error) {
-	var condition string
-	var condLimit string
+// assetSorts are the columns the assets are sorted on
+var assetSorts = map[string]string{
+	"name":       "a.name",
+	"avail":      "a.avail_quantity",
+	"created_at": "a.created_at",
+}
+
+// searchTerms turns a search into a boolean full-text query where every word is required, as a prefix
+func searchTerms(search string) string {
+	var terms []string
+	for _, word := range strings.Fields(search) {
+		word = strings.Trim(word, `+-<>()~*"@`)
+		if word != "" {
+			terms = append(terms, "+"+word+"*")
+		}
+	}
+	return strings.Join(terms, " ")
+}
 
+// assetCondition builds the where clause of a filter, shared by the list and its count
+func assetCondition(filter entities.AssetFilter) (string, []interface{}) {
+	var condition string
 	var bind []interface{}
 
-	if category != "" {
-		bind = append(bind, category)
-		condition += " and c.id=? "
+	switch filter.Lifecycle {
+	case "":
+		condition += " and a.lifecycle_status = 'in_service' "
+	case "all":
+		condition += ""
+	default:
+		bind = append(bind, filter.Lifecycle)
+		condition += " and a.lifecycle_status = ? "
 	}
 
-	if avail != "" {
-		switch avail {
-		case "no":
-			condition += " and a.avail_quantity = 0"
-		case "yes":
-			condition += " and a.avail_quantity > 0"
+	if terms := searchTerms(filter.Search); terms != "" {
+		bind = append(bind, terms)
+		condition += " and match(a.name, a.description) against (? in boolean mode) "
+	}
+
+	// a category is given by id or by name
+	if filter.Category != "" {
+		bind = append(bind, filter.Category)
+		if _, err := strconv.Atoi(filter.Category); err == nil {
+			condition += " and c.id=? "
+		} else {
+			condition += " and c.description=? "
 		}
 	}
 
-	if limit != 0 && offset == 0 {
-		bind = append(bind, limit)
-		condLimit += "limit ?"
+	// the location itself and every location under it
+	if filter.Location != 0 {
+		bind = append(bind, filter.Location, filter.Location, filter.Location)
+		condition += ` and a.id_location in (select l1.id from locations l1 left join locations l2 on l2.id = l1.id_parent
+						where l1.deleted_at is null and (l1.id = ? or l1.id_parent = ? or l2.id_parent = ?)) `
 	}
 
-	if limit != 0 && offset != 0 {
-		bind = append(bind, offset)
-		bind = append(bind, limit)
-		condLimit += "limit ?, ?"
+	// warranty expiring in the next warrantyDays days
+	if filter.Warranty_days > 0 {
+		bind = append(bind, filter.Warranty_days)
+		condition += " and a.warranty_expiry between curdate() and date_add(curdate(), interval ? day) "
+	}
+
+	switch filter.Maintenance {
+	case "no":
+		condition += " and a.is_maintenance = false"
+	case "yes":
+		condition += " and a.is_maintenance = true"
+	}
+
+	switch filter.Avail {
+	case "no":
+		condition += " and a.avail_quantity = 0"
+	case "yes":
+		condition += " and a.avail_quantity > 0"
 	}
 
+	// range of the available quantity
+	if filter.Min_quantity > 0 {
+		bind = append(bind, filter.Min_quantity)
+		condition += " and a.avail_quantity >= ? "
+	}
+	if filter.Has_max {
+		bind = append(bind, filter.Max_quantity)
+		condition += " and a.avail_quantity <= ? "
+	}
+
+	return condition, bind
+}
+
+// get all asset with filter, only assets in service unless another lifecycle status is asked. A search without
+// a sort lists the best matches first
+func (ar *assetRepo) Get(filter entities.AssetFilter) ([]entities.Asset, error) {
+	condition, bind := assetCondition(filter)
+
+	order := "a.id asc"
+	if column, ok := assetSorts[filter.Sort]; ok {
+		direction := "asc"
+		if filter.Order == "desc" {
+			direction = "desc"
+		}
+		order = column + " " + direction + ", a.id asc"
+	} else if terms := searchTerms(filter.Search); terms != "" {
+		bind = append(bind, terms)
+		order = "match(a.name, a.description) against (? in boolean mode) desc, a.id asc"
+	}
+
+	condLimit, bindLimit := pagination.Page{Limit: filter.Limit, Offset: filter.Offset}.Clause()
+	bind = append(bind, bindLimit...)
+
 	var assets []entities.Asset
-	results, err := ar.db.Query(`select a.id, a.id_category, a.is_maintenance, a.name, a.description, a.initial_quantity, a.avail_quantity, a.photo, c.description as category
+	results, err := ar.db.Query(`select a.id, COALESCE(a.tag, ''), a.id_category, a.is_maintenance, a.name, a.description, a.initial_quantity, a.avail_quantity, a.photo, COALESCE(a.photo_medium, ''), COALESCE(a.photo_thumbnail, ''), c.description as category, a.lifecycle_status,
+								COALESCE(a.id_vendor, 0), COALESCE(v.name, ''), COALESCE(a.purchase_date, ''), COALESCE(a.purchase_price, 0), COALESCE(a.invoice_number, ''), COALESCE(a.warranty_expiry, ''),
+								COALESCE(a.id_location, 0), concat_ws(' / ', ls.name, lb.name, l.name) as location
 								from assets a
 								join categories c on c.id = a.id_category
-								where a.deleted_at is null`+condition+` order by a.id asc `+condLimit, bind...)
+								left join vendors v on v.id = a.id_vendor
+								left join locations l on l.id = a.id_location
+								left join locations lb on lb.id = l.id_parent
+								left join locations ls on ls.id = lb.id_parent
+								where a.deleted_at is null`+condition+` order by `+order+` `+condLimit, bind...)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -84,7 +186,8 @@ func (ar *assetRepo) Get(category, maintenance, avail string, limit, offset int)
 	for results.Next() {
 		var asset entities.Asset
 
-		err = results.Scan(&asset.Id, &asset.Id_category, &asset.Is_maintenance, &asset.Name, &asset.Description, &asset.Initial_quantity, &asset.Avail_quantity, &asset.Photo, &asset.Category)
+		err = results.Scan(&asset.Id, &asset.Tag, &asset.Id_category, &asset.Is_maintenance, &asset.Name, &asset.Description, &asset.Initial_quantity, &asset.Avail_quantity, &asset.Photo, &asset.Photo_medium, &asset.Photo_thumbnail, &asset.Category, &asset.Lifecycle_status,
+			&asset.Id_vendor, &asset.Vendor, &asset.Purchase_date, &asset.Purchase_price, &asset.Invoice_number, &asset.Warranty_expiry, &asset.Id_location, &asset.Location)
 		if err != nil {
 			log.Println(err)
 			return nil, err
@@ -95,16 +198,48 @@ func (ar *assetRepo) Get(category, maintenance, avail string, limit, offset int)
 	return assets, nil
 }
 
+// count the assets of a filter, for the total pages of the list
+func (ar *assetRepo) Count(filter entities.AssetFilter) (int, error) {
+	condition, bind := assetCondition(filter)
+
+	var total int
+	row := ar.db.QueryRow(`select count(*)
+						from assets a
+						join categories c on c.id = a.id_category
+						where a.deleted_at is null`+condition, bind...)
+	if err := row.Scan(&total); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return total, nil
+}
+
 // get asset by id
 func (ar *assetRepo) GetById(id int) (entities.Asset, error) {
+	return ar.getAsset("a.id = ?", id)
+}
+
+// get asset by the tag printed on its label
+func (ar *assetRepo) GetByTag(tag string) (entities.Asset, error) {
+	return ar.getAsset("a.tag = ?", tag)
+}
+
+func (ar *assetRepo) getAsset(condition string, value interface{}) (entities.Asset, error) {
 	var asset entities.Asset
 
-	row := ar.db.QueryRow(`select a.id, a.id_category, a.is_maintenance, a.name, a.description, a.initial_quantity, a.avail_quantity, a.photo, c.description as category
+	row := ar.db.QueryRow(`select a.id, COALESCE(a.tag, ''), a.id_category, a.is_maintenance, a.name, a.description, a.initial_quantity, a.avail_quantity, a.photo, COALESCE(a.photo_medium, ''), COALESCE(a.photo_thumbnail, ''), c.description as category, a.lifecycle_status,
+								COALESCE(a.id_vendor, 0), COALESCE(v.name, ''), COALESCE(a.purchase_date, ''), COALESCE(a.purchase_price, 0), COALESCE(a.invoice_number, ''), COALESCE(a.warranty_expiry, ''),
+								COALESCE(a.id_location, 0), concat_ws(' / ', ls.name, lb.name, l.name) as location
 							from assets a
 							join categories c on c.id = a.id_category
-							where a.deleted_at is null and a.id = ? order by a.id asc`, id)
-
-	err := row.Scan(&asset.Id, &asset.Id_category, &asset.Is_maintenance, &asset.Name, &asset.Description, &asset.Initial_quantity, &asset.Avail_quantity, &asset.Photo, &asset.Category)
+							left join vendors v on v.id = a.id_vendor
+							left join locations l on l.id = a.id_location
+							left join locations lb on lb.id = l.id_parent
+							left join locations ls on ls.id = lb.id_parent
+							where a.deleted_at is null and `+condition, value)
+
+	err := row.Scan(&asset.Id, &asset.Tag, &asset.Id_category, &asset.Is_maintenance, &asset.Name, &asset.Description, &asset.Initial_quantity, &asset.Avail_quantity, &asset.Photo, &asset.Photo_medium, &asset.Photo_thumbnail, &asset.Category, &asset.Lifecycle_status,
+		&asset.Id_vendor, &asset.Vendor, &asset.Purchase_date, &asset.Purchase_price, &asset.Invoice_number, &asset.Warranty_expiry, &asset.Id_location, &asset.Location)
 	if err != nil {
 		return asset, err
 	}
@@ -149,9 +284,34 @@ func (ar *assetRepo) Update(assetExisted, asset entities.Asset, id int) error {
 		}
 	}
 
+	if asset.Id_vendor != 0 {
+		bind = append(bind, asset.Id_vendor)
+		query += " id_vendor = ?,"
+	}
+
+	if asset.Purchase_date != "" {
+		bind = append(bind, asset.Purchase_date)
+		query += " purchase_date = ?,"
+	}
+
+	if asset.Purchase_price != 0 {
+		bind = append(bind, asset.Purchase_price)
+		query += " purchase_price = ?,"
+	}
+
+	if asset.Invoice_number != "" {
+		bind = append(bind, asset.Invoice_number)
+		query += " invoice_number = ?,"
+	}
+
+	if asset.Warranty_expiry != "" {
+		bind = append(bind, asset.Warranty_expiry)
+		query += " warranty_expiry = ?,"
+	}
+
 	if asset.Photo != "" {
-		bind = append(bind, asset.Photo)
-		query += " photo = ?,"
+		bind = append(bind, asset.Photo, asset.Photo_medium, asset.Photo_thumbnail)
+		query += " photo = ?, photo_medium = ?, photo_thumbnail = ?,"
 	}
 
 	if asset.Is_maintenance == true {
@@ -215,21 +375,24 @@ func (ar *assetRepo) Delete(id int) error {
 
 func (ar *assetRepo) GetSummaryAsset() (entities.SummaryAsset, error) {
 	var summary entities.SummaryAsset
-	row := ar.db.QueryRow(`select all_asset.total_asset, all_asset.total_avail_asset, COALESCE(maintenance.total_asset_maintenance, 0)
+	// units under repair after a damaged return count as maintenance too
+	row := ar.db.QueryRow(`select all_asset.total_asset, all_asset.total_avail_asset, COALESCE(maintenance.total_asset_maintenance, 0) +
+								(select count(*) from asset_maintenance m join assets a on a.id = m.id_asset
+								where m.status = 'open' and a.is_maintenance = false and a.deleted_at is null and a.lifecycle_status not in ('disposed', 'sold'))
 							from (
 								SELECT 
 									sum(a.initial_quantity) as total_asset, sum(a.avail_quantity) as total_avail_asset
 								FROM
 									assets a
 									join categories c on c.id = a.id_category
-									where a.deleted_at is null order by a.id asc) AS all_asset
+									where a.deleted_at is null and a.lifecycle_status not in ('disposed', 'sold') order by a.id asc) AS all_asset
 							JOIN (
 								SELECT 
 									sum(a.initial_quantity) as total_asset_maintenance
 								FROM
 									assets a
 									join categories c on c.id = a.id_category
-									where a.deleted_at is null and a.is_maintenance = true order by a.id asc
+									where a.deleted_at is null and a.lifecycle_status not in ('disposed', 'sold') and a.is_maintenance = true order by a.id asc
 							) AS maintenance
 							`)
 
@@ -293,7 +456,7 @@ func (ar *assetRepo) GetHistoryUsage(id_asset, limit, offset int) (entities.Hist
 
 func (ar *assetRepo) GetCategory() ([]entities.Categories, error) {
 	var categories []entities.Categories
-	row, err := ar.db.Query(`select id, description
+	row, err := ar.db.Query(`select id, description, depreciation_method, useful_life_months, salvage_rate, declining_factor
 						from categories
 						where deleted_at is null`)
 
@@ -306,7 +469,7 @@ func (ar *assetRepo) GetCategory() ([]entities.Categories, error) {
 	for row.Next() {
 		var category entities.Categories
 
-		err = row.Scan(&category.Id, &category.Description)
+		err = row.Scan(&category.Id, &category.Description, &category.Depreciation_method, &category.Useful_life_months, &category.Salvage_rate, &category.Declining_factor)
 		if err != nil {
 			log.Println(err)
 			return nil, err
@@ -317,3 +480,133 @@ func (ar *assetRepo) GetCategory() ([]entities.Categories, error) {
 
 	return categories, nil
 }
+
+// update lifecycle status of an asset, a disposal record is stored when the asset leaves the company
+func (ar *assetRepo) UpdateLifecycle(event entities.AssetLifecycleEvent, disposal *entities.AssetDisposal) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if event.To_status == "retired" {
+		var totalLoan int
+		row := tx.QueryRow(`select count(*) from requests where id_asset = ? and id_status in (6, 7) and deleted_at is null`, event.Id_asset)
+		if err := row.Scan(&totalLoan); err != nil {
+			log.Println(err)
+			return err
+		}
+		if totalLoan > 0 {
+			return fmt.Errorf("asset is still on loan")
+		}
+	}
+
+	res, err := tx.Exec(`UPDATE assets SET lifecycle_status = ?, updated_at = now() WHERE id = ? AND lifecycle_status = ? AND deleted_at is null`,
+		event.To_status, event.Id_asset, event.From_status)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("asset status has changed, please retry")
+	}
+
+	_, err = tx.Exec(`INSERT INTO asset_lifecycle_events (id_asset, id_user, from_status, to_status, reason, event_date, created_at) VALUES (?, ?, ?, ?, ?, ?, now())`,
+		event.Id_asset, event.Id_user, event.From_status, event.To_status, event.Reason, event.Event_date)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	if disposal != nil {
+		_, err = tx.Exec(`INSERT INTO asset_disposals (id_asset, id_user, method, recovered_value, disposal_date, notes, created_at) VALUES (?, ?, ?, ?, ?, ?, now())`,
+			disposal.Id_asset, disposal.Id_user, disposal.Method, disposal.Recovered_value, disposal.Disposal_date, disposal.Notes)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// get lifecycle history and disposal record of an asset
+func (ar *assetRepo) GetLifecycle(id int) (entities.AssetLifecycle, error) {
+	var lifecycle entities.AssetLifecycle
+
+	row := ar.db.QueryRow(`select id, lifecycle_status from assets where id = ? and deleted_at is null`, id)
+	if err := row.Scan(&lifecycle.Id_asset, &lifecycle.Lifecycle_status); err != nil {
+		log.Println(err)
+		return lifecycle, err
+	}
+
+	results, err := ar.db.Query(`select e.id, e.id_asset, e.id_user, u.name as user_name, COALESCE(e.from_status, ''), e.to_status, COALESCE(e.reason, ''), e.event_date
+								from asset_lifecycle_events e
+								join users u on u.id = e.id_user
+								where e.id_asset = ? order by e.event_date asc, e.id asc`, id)
+	if err != nil {
+		log.Println(err)
+		return lifecycle, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var event entities.AssetLifecycleEvent
+
+		err = results.Scan(&event.Id, &event.Id_asset, &event.Id_user, &event.User_name, &event.From_status, &event.To_status, &event.Reason, &event.Event_date)
+		if err != nil {
+			log.Println(err)
+			return lifecycle, err
+		}
+
+		lifecycle.History = append(lifecycle.History, event)
+	}
+
+	var disposal entities.AssetDisposal
+	row = ar.db.QueryRow(`select id, id_asset, id_user, method, recovered_value, disposal_date, COALESCE(notes, '')
+						from asset_disposals
+						where id_asset = ?`, id)
+	err = row.Scan(&disposal.Id, &disposal.Id_asset, &disposal.Id_user, &disposal.Method, &disposal.Recovered_value, &disposal.Disposal_date, &disposal.Notes)
+	if err == nil {
+		lifecycle.Disposal = &disposal
+	} else if err != sql.ErrNoRows {
+		log.Println(err)
+		return lifecycle, err
+	}
+
+	return lifecycle, nil
+}
+
+// nullString stores empty optional column as NULL
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// nullInt stores empty optional reference as NULL
+func nullInt(value int) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+// update depreciation policy of a category
+func (ar *assetRepo) UpdateCategoryDepreciation(category entities.Categories) error {
+	res, err := ar.db.Exec(`UPDATE categories SET depreciation_method = ?, useful_life_months = ?, salvage_rate = ?, declining_factor = ?, updated_at = now() WHERE id = ? AND deleted_at is null`,
+		category.Depreciation_method, category.Useful_life_months, category.Salvage_rate, category.Declining_factor, category.Id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
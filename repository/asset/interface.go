@@ -4,11 +4,16 @@ import "sirclo/project/capstone/entities"
 
 type AssetRepo interface {
 	Create(entities.Asset) error
-	Get(string, string, string, int, int) ([]entities.Asset, error)
+	Get(entities.AssetFilter) ([]entities.Asset, error)
+	Count(entities.AssetFilter) (int, error)
 	GetById(int) (entities.Asset, error)
+	GetByTag(string) (entities.Asset, error)
 	Update(entities.Asset, entities.Asset, int) error
 	Delete(int) error
 	GetSummaryAsset() (entities.SummaryAsset, error)
 	GetHistoryUsage(int, int, int) (entities.HistoryUsage, error)
 	GetCategory() ([]entities.Categories, error)
+	UpdateLifecycle(entities.AssetLifecycleEvent, *entities.AssetDisposal) error
+	GetLifecycle(int) (entities.AssetLifecycle, error)
+	UpdateCategoryDepreciation(entities.Categories) error
 }
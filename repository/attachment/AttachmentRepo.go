@@ -0,0 +1,389 @@
+package attachment
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type attachmentRepo struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepo(db *sql.DB) *attachmentRepo {
+	return &attachmentRepo{db: db}
+}
+
+// check asset existed
+func (ar *attachmentRepo) CheckAsset(idAsset int) error {
+	var id int
+	row := ar.db.QueryRow(`select id from assets where id = ? and deleted_at is null`, idAsset)
+
+	err := row.Scan(&id)
+	if err != nil {
+		log.Println(err)
+		return fmt.Errorf("asset not found")
+	}
+	return nil
+}
+
+// create the attachments of an upload, every one of them or none. The first photo of an asset becomes its primary photo
+func (ar *attachmentRepo) Create(attachments []entities.AssetAttachment) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, attachment := range attachments {
+		if err := createAttachment(tx, attachment); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func createAttachment(tx *sql.Tx, attachment entities.AssetAttachment) error {
+	// the asset is locked so two uploads at the same time do not get the same position
+	var idAsset int
+	row := tx.QueryRow(`select id from assets where id = ? for update`, attachment.Id_asset)
+	if err := row.Scan(&idAsset); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	var position, totalPrimary int
+	row = tx.QueryRow(`select COALESCE(max(position), 0) + 1, COALESCE(sum(is_primary), 0)
+						from asset_attachments
+						where id_asset = ? and deleted_at is null`, attachment.Id_asset)
+	if err := row.Scan(&position, &totalPrimary); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	isPrimary := attachment.Type == "photo" && totalPrimary == 0
+
+	_, err := tx.Exec(`INSERT INTO asset_attachments (id_asset, type, document_type, name, content_type, url, url_medium, url_thumbnail, position, is_primary, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, now(), now())`,
+		attachment.Id_asset, attachment.Type, attachment.Document_type, attachment.Name, attachment.Content_type, attachment.Url, attachment.Url_medium, attachment.Url_thumbnail, position, isPrimary)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	if isPrimary {
+		return syncAssetPhoto(tx, attachment.Id_asset)
+	}
+	return nil
+}
+
+// get attachments of an asset
+func (ar *attachmentRepo) Get(idAsset int) ([]entities.AssetAttachment, error) {
+	var attachments []entities.AssetAttachment
+	results, err := ar.db.Query(`select id, id_asset, type, COALESCE(document_type, ''), name, content_type, url, COALESCE(url_medium, ''), COALESCE(url_thumbnail, ''), position, is_primary, created_at
+								from asset_attachments
+								where id_asset = ? and deleted_at is null order by position asc, id asc`, idAsset)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var attachment entities.AssetAttachment
+
+		err = results.Scan(&attachment.Id, &attachment.Id_asset, &attachment.Type, &attachment.Document_type, &attachment.Name, &attachment.Content_type,
+			&attachment.Url, &attachment.Url_medium, &attachment.Url_thumbnail, &attachment.Position, &attachment.Is_primary, &attachment.Created_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// reorder attachments, order contains every attachment id of the asset in the new order
+func (ar *attachmentRepo) Reorder(idAsset int, order []int) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`select id from asset_attachments where id_asset = ? and deleted_at is null for update`, idAsset)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	attachments := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			log.Println(err)
+			return err
+		}
+		attachments[id] = true
+	}
+	rows.Close()
+
+	// every attachment of the asset once, and no attachment of another asset
+	listed := map[int]bool{}
+	for _, id := range order {
+		if !attachments[id] {
+			return fmt.Errorf("attachment %d not found", id)
+		}
+		if listed[id] {
+			return fmt.Errorf("attachment %d is listed twice", id)
+		}
+		listed[id] = true
+	}
+	if len(listed) != len(attachments) {
+		return fmt.Errorf("order must contain every attachment of the asset")
+	}
+
+	for position, id := range order {
+		_, err := tx.Exec(`UPDATE asset_attachments SET position = ?, updated_at = now() WHERE id = ?`, position+1, id)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// set primary photo of an asset
+func (ar *attachmentRepo) SetPrimary(idAsset, id int) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var attachmentType string
+	row := tx.QueryRow(`select type from asset_attachments where id = ? and id_asset = ? and deleted_at is null`, id, idAsset)
+	if err := row.Scan(&attachmentType); err != nil {
+		log.Println(err)
+		return fmt.Errorf("id not found")
+	}
+	if attachmentType != "photo" {
+		return fmt.Errorf("only photo can be primary")
+	}
+
+	_, err = tx.Exec(`UPDATE asset_attachments SET is_primary = (id = ?), updated_at = now() WHERE id_asset = ? AND deleted_at is null`, id, idAsset)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	if err := syncAssetPhoto(tx, idAsset); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// delete attachment, the next photo takes over when the primary photo is deleted
+func (ar *attachmentRepo) Delete(idAsset, id int) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var isPrimary bool
+	row := tx.QueryRow(`select is_primary from asset_attachments where id = ? and id_asset = ? and deleted_at is null`, id, idAsset)
+	if err := row.Scan(&isPrimary); err != nil {
+		log.Println(err)
+		return fmt.Errorf("id not found")
+	}
+
+	_, err = tx.Exec(`UPDATE asset_attachments SET deleted_at = now(), is_primary = false WHERE id = ?`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	if isPrimary {
+		_, err = tx.Exec(`UPDATE asset_attachments SET is_primary = true, updated_at = now()
+							WHERE id_asset = ? AND type = 'photo' AND deleted_at is null
+							ORDER BY position asc, id asc LIMIT 1`, idAsset)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+
+		if err := syncAssetPhoto(tx, idAsset); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// create pending direct upload, it expires after expiry seconds when not confirmed
+func (ar *attachmentRepo) CreateUpload(upload entities.AssetUpload, expiry int) (int, error) {
+	res, err := ar.db.Exec(`INSERT INTO asset_uploads (id_asset, id_user, object_key, type, document_type, name, content_type, status, expires_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, 'pending', DATE_ADD(now(), INTERVAL ? SECOND), now(), now())`,
+		upload.Id_asset, upload.Id_user, upload.Object_key, upload.Type, upload.Document_type, upload.Name, upload.Content_type, expiry)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// get direct upload by id, an expired pending upload is reported with status expired
+func (ar *attachmentRepo) GetUpload(id int) (entities.AssetUpload, error) {
+	var upload entities.AssetUpload
+
+	row := ar.db.QueryRow(`select id, id_asset, id_user, object_key, type, COALESCE(document_type, ''), name, content_type,
+								if(status = 'pending' and expires_at <= now(), 'expired', status), expires_at
+							from asset_uploads
+							where id = ?`, id)
+
+	err := row.Scan(&upload.Id, &upload.Id_asset, &upload.Id_user, &upload.Object_key, &upload.Type, &upload.Document_type, &upload.Name, &upload.Content_type, &upload.Status, &upload.Expires_at)
+	if err != nil {
+		log.Println(err)
+		return upload, err
+	}
+	return upload, nil
+}
+
+// confirm direct upload and attach the uploaded file to the asset, a pending document or a processed photo
+func (ar *attachmentRepo) ConfirmUpload(id int, attachment entities.AssetAttachment) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE asset_uploads SET status = 'confirmed', updated_at = now()
+							WHERE id = ? AND ((status = 'pending' AND expires_at > now()) OR status = 'processing')`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("upload expired or already confirmed")
+	}
+
+	if err := createAttachment(tx, attachment); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// confirm a direct photo upload, its renditions are made in the background before it is attached
+func (ar *attachmentRepo) ProcessUpload(id int) error {
+	res, err := ar.db.Exec(`UPDATE asset_uploads SET status = 'processing', updated_at = now() WHERE id = ? AND status = 'pending' AND expires_at > now()`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("upload expired or already confirmed")
+	}
+	return nil
+}
+
+// get pending uploads that were never confirmed
+func (ar *attachmentRepo) GetExpiredUploads() ([]entities.AssetUpload, error) {
+	return ar.uploads(`status = 'pending' and expires_at <= now()`)
+}
+
+// get confirmed photos waiting for their renditions
+func (ar *attachmentRepo) GetProcessingUploads() ([]entities.AssetUpload, error) {
+	return ar.uploads(`status = 'processing'`)
+}
+
+func (ar *attachmentRepo) uploads(condition string) ([]entities.AssetUpload, error) {
+	var uploads []entities.AssetUpload
+	results, err := ar.db.Query(`select id, id_asset, id_user, object_key, type, COALESCE(document_type, ''), name, content_type, status, expires_at
+								from asset_uploads
+								where ` + condition + ` order by id asc`)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var upload entities.AssetUpload
+
+		err = results.Scan(&upload.Id, &upload.Id_asset, &upload.Id_user, &upload.Object_key, &upload.Type, &upload.Document_type, &upload.Name, &upload.Content_type, &upload.Status, &upload.Expires_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		uploads = append(uploads, upload)
+	}
+	return uploads, nil
+}
+
+// reject a confirmed photo that turned out not to be a valid photo
+func (ar *attachmentRepo) RejectUpload(id int) error {
+	_, err := ar.db.Exec(`UPDATE asset_uploads SET status = 'rejected', updated_at = now() WHERE id = ? AND status = 'processing'`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// mark abandoned upload as expired
+func (ar *attachmentRepo) ExpireUpload(id int) error {
+	_, err := ar.db.Exec(`UPDATE asset_uploads SET status = 'expired', updated_at = now() WHERE id = ? AND status = 'pending'`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// syncAssetPhoto copies the primary photo to the asset so list views keep using assets.photo. A photo the asset
+// was given on create or update is kept, only an empty photo or one that came from an attachment follows the
+// primary photo
+func syncAssetPhoto(tx *sql.Tx, idAsset int) error {
+	var fromAttachment bool
+	row := tx.QueryRow(`select COALESCE(a.photo, '') = '' or exists (select 1 from asset_attachments at where at.id_asset = a.id and at.url = a.photo)
+						from assets a where a.id = ?`, idAsset)
+	if err := row.Scan(&fromAttachment); err != nil {
+		log.Println(err)
+		return err
+	}
+	if !fromAttachment {
+		return nil
+	}
+
+	_, err := tx.Exec(`UPDATE assets a
+						LEFT JOIN asset_attachments at on at.id_asset = a.id and at.is_primary = true and at.deleted_at is null
+						SET a.photo = COALESCE(at.url, ''), a.photo_medium = COALESCE(at.url_medium, ''), a.photo_thumbnail = COALESCE(at.url_thumbnail, ''), a.updated_at = now()
+						WHERE a.id = ?`, idAsset)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package attachment
+
+import "sirclo/project/capstone/entities"
+
+type AttachmentRepo interface {
+	CheckAsset(idAsset int) error
+	Create(attachments []entities.AssetAttachment) error
+	Get(idAsset int) ([]entities.AssetAttachment, error)
+	Reorder(idAsset int, order []int) error
+	SetPrimary(idAsset, id int) error
+	Delete(idAsset, id int) error
+	CreateUpload(upload entities.AssetUpload, expiry int) (int, error)
+	GetUpload(id int) (entities.AssetUpload, error)
+	ConfirmUpload(id int, attachment entities.AssetAttachment) error
+	ProcessUpload(id int) error
+	GetExpiredUploads() ([]entities.AssetUpload, error)
+	GetProcessingUploads() ([]entities.AssetUpload, error)
+	RejectUpload(id int) error
+	ExpireUpload(id int) error
+}
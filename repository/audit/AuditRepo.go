@@ -0,0 +1,243 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type auditRepo struct {
+	db *sql.DB
+}
+
+func NewAuditRepo(db *sql.DB) *auditRepo {
+	return &auditRepo{db: db}
+}
+
+// nullInt stores empty optional reference as NULL
+func nullInt(value int) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+// create audit session, on a location (and the locations under it) or on every asset
+func (ar *auditRepo) Create(session entities.AuditSession) (int, error) {
+	res, err := ar.db.Exec(`INSERT INTO audit_sessions (id_location, id_user, status, notes, started_at) VALUES (?, ?, 'open', ?, now())`,
+		nullInt(session.Id_location), session.Id_user, session.Notes)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// get all audit session, without their report
+func (ar *auditRepo) Get() ([]entities.AuditSession, error) {
+	var sessions []entities.AuditSession
+	results, err := ar.db.Query(`select s.id, COALESCE(s.id_location, 0), COALESCE(l.name, ''), s.id_user, s.status, COALESCE(s.notes, ''), s.started_at, COALESCE(s.signed_off_by, 0), COALESCE(s.signed_off_at, '')
+								from audit_sessions s
+								left join locations l on l.id = s.id_location
+								order by s.started_at desc`)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var session entities.AuditSession
+
+		err = results.Scan(&session.Id, &session.Id_location, &session.Location, &session.Id_user, &session.Status, &session.Notes, &session.Started_at, &session.Signed_off_by, &session.Signed_off_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// get audit session by id, with the stored report once signed off
+func (ar *auditRepo) GetById(id int) (entities.AuditSession, error) {
+	var session entities.AuditSession
+	var report sql.NullString
+
+	row := ar.db.QueryRow(`select s.id, COALESCE(s.id_location, 0), COALESCE(l.name, ''), s.id_user, s.status, COALESCE(s.notes, ''), s.started_at, COALESCE(s.signed_off_by, 0), COALESCE(s.signed_off_at, ''), s.report
+							from audit_sessions s
+							left join locations l on l.id = s.id_location
+							where s.id = ?`, id)
+
+	err := row.Scan(&session.Id, &session.Id_location, &session.Location, &session.Id_user, &session.Status, &session.Notes, &session.Started_at, &session.Signed_off_by, &session.Signed_off_at, &report)
+	if err != nil {
+		return session, err
+	}
+
+	if report.Valid {
+		session.Report = &entities.AuditReport{}
+		if err := json.Unmarshal([]byte(report.String), session.Report); err != nil {
+			log.Println(err)
+			return session, err
+		}
+	}
+
+	return session, nil
+}
+
+// checkOpen locks the session, counts can only change while it is open
+func checkOpen(tx *sql.Tx, idSession int) error {
+	var status string
+	row := tx.QueryRow(`select status from audit_sessions where id = ? for update`, idSession)
+	if err := row.Scan(&status); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("audit session not found")
+		}
+		log.Println(err)
+		return err
+	}
+	if status != "open" {
+		return fmt.Errorf("audit session is already signed off")
+	}
+	return nil
+}
+
+// scan an asset tag, one more unit of the asset found
+func (ar *auditRepo) Scan(idSession int, tag string, idUser int) (entities.Asset, error) {
+	var asset entities.Asset
+
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return asset, err
+	}
+	defer tx.Rollback()
+
+	if err := checkOpen(tx, idSession); err != nil {
+		return asset, err
+	}
+
+	row := tx.QueryRow(`select id, tag, name from assets where tag = ? and deleted_at is null`, tag)
+	if err := row.Scan(&asset.Id, &asset.Tag, &asset.Name); err != nil {
+		if err == sql.ErrNoRows {
+			return asset, fmt.Errorf("asset not found")
+		}
+		log.Println(err)
+		return asset, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO audit_counts (id_session, id_asset, id_user, found_quantity, counted_at) VALUES (?, ?, ?, 1, now())
+					ON DUPLICATE KEY UPDATE found_quantity = found_quantity + 1, id_user = VALUES(id_user), counted_at = now()`, idSession, asset.Id, idUser)
+	if err != nil {
+		log.Println(err)
+		return asset, err
+	}
+
+	return asset, tx.Commit()
+}
+
+// tick off an asset with the quantity counted on the shelf
+func (ar *auditRepo) SetCount(idSession, idAsset, found, idUser int) error {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkOpen(tx, idSession); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO audit_counts (id_session, id_asset, id_user, found_quantity, counted_at) VALUES (?, ?, ?, ?, now())
+					ON DUPLICATE KEY UPDATE found_quantity = VALUES(found_quantity), id_user = VALUES(id_user), counted_at = now()`, idSession, idAsset, idUser, found)
+	if err != nil {
+		log.Println(err)
+		return fmt.Errorf("asset not found")
+	}
+
+	return tx.Commit()
+}
+
+// get expected and found quantity of every asset in the audited location and every asset counted,
+// an asset counted outside of the location is expected 0 times
+func (ar *auditRepo) GetItems(idSession int) ([]entities.AuditItem, error) {
+	var idLocation int
+	row := ar.db.QueryRow(`select COALESCE(id_location, 0) from audit_sessions where id = ?`, idSession)
+	if err := row.Scan(&idLocation); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	scope := "true"
+	var bind []interface{}
+	if idLocation != 0 {
+		scope = `a.id_location in (select l1.id from locations l1 left join locations l2 on l2.id = l1.id_parent
+					where l1.id = ? or l1.id_parent = ? or l2.id_parent = ?)`
+		bind = append(bind, idLocation, idLocation, idLocation)
+	}
+	bind = append(bind, idSession)
+
+	var items []entities.AuditItem
+	results, err := ar.db.Query(`select a.id, COALESCE(a.tag, ''), a.name, a.in_scope * a.avail_quantity as expected, a.on_loan, COALESCE(ac.found_quantity, 0)
+								from (
+									select a.id, a.tag, a.name, a.avail_quantity, `+scope+` as in_scope,
+										(select COALESCE(sum(r.quantity - r.returned_quantity), 0) from requests r where r.id_asset = a.id and r.id_status = 6 and r.deleted_at is null) as on_loan
+									from assets a
+									where a.deleted_at is null and a.lifecycle_status = 'in_service'
+								) a
+								left join audit_counts ac on ac.id_asset = a.id and ac.id_session = ?
+								where a.in_scope or ac.id is not null
+								order by a.id asc`, bind...)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var item entities.AuditItem
+
+		err = results.Scan(&item.Id_asset, &item.Tag, &item.Name, &item.Expected, &item.On_loan, &item.Found)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// sign off the audit session and store its report
+func (ar *auditRepo) SignOff(idSession, idUser int, report entities.AuditReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	res, err := ar.db.Exec(`UPDATE audit_sessions SET status = 'signed_off', report = ?, signed_off_by = ?, signed_off_at = now() WHERE id = ? AND status = 'open'`,
+		string(data), idUser, idSession)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("audit session not found or already signed off")
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package audit
+
+import "sirclo/project/capstone/entities"
+
+type AuditRepo interface {
+	Create(entities.AuditSession) (int, error)
+	Get() ([]entities.AuditSession, error)
+	GetById(int) (entities.AuditSession, error)
+	Scan(idSession int, tag string, idUser int) (entities.Asset, error)
+	SetCount(idSession, idAsset, found, idUser int) error
+	GetItems(int) ([]entities.AuditItem, error)
+	SignOff(idSession, idUser int, report entities.AuditReport) error
+}
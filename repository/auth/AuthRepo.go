@@ -18,7 +18,7 @@ func NewAuthRepo(db *sql.DB) *authRepo {
 }
 
 func (ar *authRepo) LoginEmail(email, password string) (string, error) {
-	result, err := ar.db.Query("select id, name, email, divisi, id_role FROM users WHERE email=? AND password=?", email, password)
+	result, err := ar.db.Query("select id, name, email, divisi, id_role FROM users WHERE email=? AND password=? AND deleted_at is null", email, password)
 	if err != nil {
 		return "", err
 	}
@@ -39,7 +39,7 @@ func (ar *authRepo) LoginEmail(email, password string) (string, error) {
 }
 
 func (ar *authRepo) GetPasswordByEmail(email string) (string, error) {
-	result, err := ar.db.Query("select password FROM users WHERE email=?", email)
+	result, err := ar.db.Query("select password FROM users WHERE email=? AND deleted_at is null", email)
 	if err != nil {
 		return "", err
 	}
@@ -57,7 +57,7 @@ func (ar *authRepo) GetPasswordByEmail(email string) (string, error) {
 }
 
 func (ar *authRepo) GetIdByEmail(email string) (int, error) {
-	result, err := ar.db.Query("SELECT id FROM users WHERE email=?", email)
+	result, err := ar.db.Query("SELECT id FROM users WHERE email=? AND deleted_at is null", email)
 	if err != nil {
 		return 0, err
 	}
@@ -74,7 +74,7 @@ func (ar *authRepo) GetIdByEmail(email string) (int, error) {
 	return userId, nil
 }
 func (ar *authRepo) GetIdRole(email string) (int, error) {
-	result, err := ar.db.Query("SELECT id_role FROM users WHERE email=?", email)
+	result, err := ar.db.Query("SELECT id_role FROM users WHERE email=? AND deleted_at is null", email)
 	if err != nil {
 		return 0, err
 	}
@@ -92,7 +92,7 @@ func (ar *authRepo) GetIdRole(email string) (int, error) {
 }
 
 func (ar *authRepo) GetNameByEmail(email string) (string, error) {
-	result, err := ar.db.Query("SELECT name FROM users WHERE email=?", email)
+	result, err := ar.db.Query("SELECT name FROM users WHERE email=? AND deleted_at is null", email)
 	if err != nil {
 		return "", err
 	}
@@ -108,3 +108,13 @@ func (ar *authRepo) GetNameByEmail(email string) (string, error) {
 	name := user.Name
 	return name, nil
 }
+
+// IsActive tells whether the user of a token is still active, a deactivated user keeps no access
+func (ar *authRepo) IsActive(id int) (bool, error) {
+	var active bool
+	row := ar.db.QueryRow("SELECT count(*) > 0 FROM users WHERE id=? AND deleted_at is null", id)
+	if err := row.Scan(&active); err != nil {
+		return false, err
+	}
+	return active, nil
+}
@@ -6,4 +6,5 @@ type Auth interface {
 	GetIdByEmail(email string) (int, error)
 	GetIdRole(email string) (int, error)
 	GetNameByEmail(email string) (string, error)
+	IsActive(id int) (bool, error)
 }
@@ -0,0 +1,82 @@
+package comment
+
+import (
+	"database/sql"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type commentRepo struct {
+	db *sql.DB
+}
+
+func NewCommentRepo(db *sql.DB) *commentRepo {
+	return &commentRepo{db: db}
+}
+
+// Add stores a comment on a request, inside the transaction of the change it explains
+func Add(tx *sql.Tx, comment entities.RequestComment) (int, error) {
+	res, err := tx.Exec(`INSERT INTO request_comments (id_request, id_user, body, internal, created_at, updated_at) VALUES (?, ?, ?, ?, now(), now())`,
+		comment.Id_request, comment.Id_user, comment.Body, comment.Internal)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// add a comment on a request
+func (cr *commentRepo) Create(comment entities.RequestComment) (int, error) {
+	tx, err := cr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	id, err := Add(tx, comment)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// get the comments of a request, oldest first, the internal notes only when asked
+func (cr *commentRepo) Get(idRequest int, internal bool) ([]entities.RequestComment, error) {
+	condition := " and c.internal = 0"
+	if internal {
+		condition = ""
+	}
+
+	var comments []entities.RequestComment
+	results, err := cr.db.Query(`select c.id, c.id_request, c.id_user, u.name, c.body, c.internal, c.created_at, c.updated_at
+								from request_comments c
+								join users u on u.id = c.id_user
+								where c.id_request = ?`+condition+` order by c.created_at asc, c.id asc`, idRequest)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var comment entities.RequestComment
+
+		err = results.Scan(&comment.Id, &comment.Id_request, &comment.Id_user, &comment.User_name, &comment.Body, &comment.Internal, &comment.Created_at, &comment.Updated_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
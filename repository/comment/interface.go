@@ -0,0 +1,8 @@
+package comment
+
+import "sirclo/project/capstone/entities"
+
+type CommentRepo interface {
+	Create(entities.RequestComment) (int, error)
+	Get(idRequest int, internal bool) ([]entities.RequestComment, error)
+}
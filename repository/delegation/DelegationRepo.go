@@ -0,0 +1,121 @@
+package delegation
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type delegationRepo struct {
+	db *sql.DB
+}
+
+func NewDelegationRepo(db *sql.DB) *delegationRepo {
+	return &delegationRepo{db: db}
+}
+
+// create a delegation window, the windows of a manager can not overlap
+func (dr *delegationRepo) Create(delegation entities.Delegation) (int, error) {
+	tx, err := dr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// lock the manager so two windows can not be created at once
+	var idManager int
+	row := tx.QueryRow(`select id from users where id = ? and deleted_at is null for update`, delegation.Id_manager)
+	if err := row.Scan(&idManager); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	var idDelegate int
+	row = tx.QueryRow(`select id from users where id = ? and deleted_at is null`, delegation.Id_delegate)
+	if err := row.Scan(&idDelegate); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("delegate not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+
+	var overlaps int
+	row = tx.QueryRow(`select count(*) from approval_delegations
+						where id_manager = ? and revoked_at is null and start_at < ? and end_at > ?`,
+		delegation.Id_manager, delegation.End_at, delegation.Start_at)
+	if err := row.Scan(&overlaps); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if overlaps > 0 {
+		return 0, fmt.Errorf("delegation overlaps an existing one")
+	}
+
+	res, err := tx.Exec(`INSERT INTO approval_delegations (id_manager, id_delegate, start_at, end_at, created_at) VALUES (?, ?, ?, ?, now())`,
+		delegation.Id_manager, delegation.Id_delegate, delegation.Start_at, delegation.End_at)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
+
+// get the delegations given or received by a user, every delegation when the user is not given
+func (dr *delegationRepo) Get(idUser int) ([]entities.Delegation, error) {
+	var condition string
+	var bind []interface{}
+
+	if idUser != 0 {
+		bind = append(bind, idUser, idUser)
+		condition += " where d.id_manager = ? or d.id_delegate = ?"
+	}
+
+	var delegations []entities.Delegation
+	results, err := dr.db.Query(`select d.id, d.id_manager, m.name, d.id_delegate, u.name, d.start_at, d.end_at, COALESCE(d.revoked_at, ''), d.created_at
+								from approval_delegations d
+								join users m on m.id = d.id_manager
+								join users u on u.id = d.id_delegate`+condition+` order by d.start_at desc`, bind...)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var delegation entities.Delegation
+
+		err = results.Scan(&delegation.Id, &delegation.Id_manager, &delegation.Manager_name, &delegation.Id_delegate, &delegation.Delegate_name, &delegation.Start_at, &delegation.End_at, &delegation.Revoked_at, &delegation.Created_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		delegations = append(delegations, delegation)
+	}
+	return delegations, nil
+}
+
+// revoke a delegation of the manager, a window already over can not be revoked
+func (dr *delegationRepo) Revoke(id, idManager int) error {
+	res, err := dr.db.Exec(`UPDATE approval_delegations SET revoked_at = now() WHERE id = ? AND id_manager = ? AND revoked_at is null AND end_at > now()`, id, idManager)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("delegation not found or no longer active")
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package delegation
+
+import "sirclo/project/capstone/entities"
+
+type DelegationRepo interface {
+	Create(entities.Delegation) (int, error)
+	Get(idUser int) ([]entities.Delegation, error)
+	Revoke(id, idManager int) error
+}
@@ -0,0 +1,201 @@
+package kit
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type kitRepo struct {
+	db *sql.DB
+}
+
+func NewKitRepo(db *sql.DB) *kitRepo {
+	return &kitRepo{db: db}
+}
+
+// insertItems stores the components of a kit, every asset must exist
+func insertItems(tx *sql.Tx, idKit int, items []entities.KitItem) error {
+	for _, item := range items {
+		res, err := tx.Exec(`INSERT INTO kit_items (id_kit, id_asset, quantity)
+							SELECT ?, a.id, ? FROM assets a WHERE a.id = ? AND a.deleted_at is null`, idKit, item.Quantity, item.Id_asset)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+		row, _ := res.RowsAffected()
+		if row == 0 {
+			return fmt.Errorf("asset %d not found", item.Id_asset)
+		}
+	}
+	return nil
+}
+
+// getItems gets the components of the kits, by id of the kit
+func (kr *kitRepo) getItems(condition string, bind ...interface{}) (map[int][]entities.KitItem, error) {
+	items := map[int][]entities.KitItem{}
+	results, err := kr.db.Query(`select ki.id_kit, ki.id_asset, a.name, ki.quantity, a.avail_quantity
+								from kit_items ki
+								join assets a on a.id = ki.id_asset
+								join kits k on k.id = ki.id_kit
+								where k.deleted_at is null `+condition+` order by ki.id asc`, bind...)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var idKit int
+		var item entities.KitItem
+
+		if err := results.Scan(&idKit, &item.Id_asset, &item.Asset_name, &item.Quantity, &item.Avail_quantity); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		items[idKit] = append(items[idKit], item)
+	}
+	return items, nil
+}
+
+// create kit with its components
+func (kr *kitRepo) Create(kit entities.Kit) (int, error) {
+	tx, err := kr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT INTO kits (name, description, created_at, updated_at) VALUES (?, ?, now(), now())`, kit.Name, kit.Description)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	if err := insertItems(tx, int(id), kit.Items); err != nil {
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
+
+// get all kit with their components
+func (kr *kitRepo) Get() ([]entities.Kit, error) {
+	var kits []entities.Kit
+	results, err := kr.db.Query(`select id, name, COALESCE(description, '') from kits where deleted_at is null order by name asc`)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var kit entities.Kit
+
+		err = results.Scan(&kit.Id, &kit.Name, &kit.Description)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		kits = append(kits, kit)
+	}
+
+	items, err := kr.getItems("")
+	if err != nil {
+		return nil, err
+	}
+	for i := range kits {
+		kits[i].Items = items[kits[i].Id]
+	}
+	return kits, nil
+}
+
+// get kit by id with its components
+func (kr *kitRepo) GetById(id int) (entities.Kit, error) {
+	var kit entities.Kit
+
+	row := kr.db.QueryRow(`select id, name, COALESCE(description, '') from kits where id = ? and deleted_at is null`, id)
+	err := row.Scan(&kit.Id, &kit.Name, &kit.Description)
+	if err != nil {
+		return kit, err
+	}
+
+	items, err := kr.getItems("and ki.id_kit = ?", id)
+	if err != nil {
+		return kit, err
+	}
+	kit.Items = items[kit.Id]
+	return kit, nil
+}
+
+// update kit, the components are replaced when given
+func (kr *kitRepo) Update(kit entities.Kit, id int) error {
+	tx, err := kr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE kits SET`
+	var bind []interface{}
+
+	if kit.Name != "" {
+		bind = append(bind, kit.Name)
+		query += " name = ?,"
+	}
+	if kit.Description != "" {
+		bind = append(bind, kit.Description)
+		query += " description = ?,"
+	}
+
+	bind = append(bind, id)
+	query += " updated_at = now() WHERE id = ? AND deleted_at is null"
+
+	res, err := tx.Exec(query, bind...)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("id not found")
+	}
+
+	if kit.Items != nil {
+		if _, err := tx.Exec(`DELETE FROM kit_items WHERE id_kit = ?`, id); err != nil {
+			log.Println(err)
+			return err
+		}
+		if err := insertItems(tx, id, kit.Items); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// delete kit, the requests already made for it are kept
+func (kr *kitRepo) Delete(id int) error {
+	res, err := kr.db.Exec(`UPDATE kits SET deleted_at = now() WHERE id = ? AND deleted_at is null`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package kit
+
+import "sirclo/project/capstone/entities"
+
+type KitRepo interface {
+	Create(entities.Kit) (int, error)
+	Get() ([]entities.Kit, error)
+	GetById(int) (entities.Kit, error)
+	Update(entities.Kit, int) error
+	Delete(int) error
+}
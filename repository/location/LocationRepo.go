@@ -0,0 +1,204 @@
+package location
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type locationRepo struct {
+	db *sql.DB
+}
+
+func NewLocationRepo(db *sql.DB) *locationRepo {
+	return &locationRepo{db: db}
+}
+
+// create location
+func (lr *locationRepo) Create(location entities.Location) error {
+	query := (`INSERT INTO locations (id_parent, type, name, created_at, updated_at) VALUES (?, ?, ?, now(), now())`)
+
+	statement, err := lr.db.Prepare(query)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	defer statement.Close()
+
+	var idParent interface{}
+	if location.Id_parent != 0 {
+		idParent = location.Id_parent
+	}
+
+	_, err = statement.Exec(idParent, location.Type, location.Name)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return nil
+}
+
+// get all location, path is the full name from the site down to the location
+func (lr *locationRepo) Get() ([]entities.Location, error) {
+	var locations []entities.Location
+	results, err := lr.db.Query(`select l.id, COALESCE(l.id_parent, 0), l.type, l.name, concat_ws(' / ', s.name, b.name, l.name) as path
+								from locations l
+								left join locations b on b.id = l.id_parent
+								left join locations s on s.id = b.id_parent
+								where l.deleted_at is null order by l.name asc`)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var location entities.Location
+
+		err = results.Scan(&location.Id, &location.Id_parent, &location.Type, &location.Name, &location.Path)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		locations = append(locations, location)
+	}
+	return locations, nil
+}
+
+// get location by id
+func (lr *locationRepo) GetById(id int) (entities.Location, error) {
+	var location entities.Location
+
+	row := lr.db.QueryRow(`select l.id, COALESCE(l.id_parent, 0), l.type, l.name, concat_ws(' / ', s.name, b.name, l.name) as path
+							from locations l
+							left join locations b on b.id = l.id_parent
+							left join locations s on s.id = b.id_parent
+							where l.id = ? and l.deleted_at is null`, id)
+
+	err := row.Scan(&location.Id, &location.Id_parent, &location.Type, &location.Name, &location.Path)
+	if err != nil {
+		return location, err
+	}
+
+	return location, nil
+}
+
+// update location name, the place in the tree stays the same
+func (lr *locationRepo) Update(location entities.Location, id int) error {
+	res, err := lr.db.Exec(`UPDATE locations SET name = ?, updated_at = now() WHERE id = ? AND deleted_at is null`, location.Name, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
+
+// delete location, only when there is no sub location or asset left in it
+func (lr *locationRepo) Delete(id int) error {
+	var totalUsed int
+	used := lr.db.QueryRow(`select (select count(*) from locations where id_parent = ? and deleted_at is null) +
+							(select count(*) from assets where id_location = ? and deleted_at is null)`, id, id)
+	if err := used.Scan(&totalUsed); err != nil {
+		log.Println(err)
+		return err
+	}
+	if totalUsed > 0 {
+		return fmt.Errorf("location is still in use")
+	}
+
+	res, err := lr.db.Exec("UPDATE locations SET deleted_at = now() WHERE id = ? AND deleted_at is null", id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("id not found")
+	}
+	return nil
+}
+
+// move asset to another location and record the move in the history
+func (lr *locationRepo) MoveAsset(move entities.AssetLocationMove) error {
+	tx, err := lr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var fromLocation sql.NullInt64
+	row := tx.QueryRow(`select id_location from assets where id = ? and deleted_at is null for update`, move.Id_asset)
+	if err := row.Scan(&fromLocation); err != nil {
+		log.Println(err)
+		return fmt.Errorf("asset not found")
+	}
+	if fromLocation.Valid && int(fromLocation.Int64) == move.To_location {
+		return fmt.Errorf("asset is already in this location")
+	}
+
+	var totalLocation int
+	row = tx.QueryRow(`select count(*) from locations where id = ? and deleted_at is null`, move.To_location)
+	if err := row.Scan(&totalLocation); err != nil {
+		log.Println(err)
+		return err
+	}
+	if totalLocation == 0 {
+		return fmt.Errorf("location not found")
+	}
+
+	_, err = tx.Exec(`UPDATE assets SET id_location = ?, updated_at = now() WHERE id = ?`, move.To_location, move.Id_asset)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO asset_location_moves (id_asset, id_user, from_location, to_location, notes, moved_at) VALUES (?, ?, ?, ?, ?, now())`,
+		move.Id_asset, move.Id_user, fromLocation, move.To_location, move.Notes)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// get location move history of an asset
+func (lr *locationRepo) GetAssetMoves(idAsset int) ([]entities.AssetLocationMove, error) {
+	var moves []entities.AssetLocationMove
+	results, err := lr.db.Query(`select m.id, m.id_asset, m.id_user, u.name as user_name, COALESCE(m.from_location, 0), COALESCE(f.name, ''), m.to_location, t.name, COALESCE(m.notes, ''), m.moved_at
+								from asset_location_moves m
+								join users u on u.id = m.id_user
+								left join locations f on f.id = m.from_location
+								join locations t on t.id = m.to_location
+								where m.id_asset = ? order by m.moved_at asc, m.id asc`, idAsset)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var move entities.AssetLocationMove
+
+		err = results.Scan(&move.Id, &move.Id_asset, &move.Id_user, &move.User_name, &move.From_location, &move.From_name, &move.To_location, &move.To_name, &move.Notes, &move.Moved_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
@@ -0,0 +1,13 @@
+package location
+
+import "sirclo/project/capstone/entities"
+
+type LocationRepo interface {
+	Create(entities.Location) error
+	Get() ([]entities.Location, error)
+	GetById(int) (entities.Location, error)
+	Update(entities.Location, int) error
+	Delete(int) error
+	MoveAsset(entities.AssetLocationMove) error
+	GetAssetMoves(int) ([]entities.AssetLocationMove, error)
+}
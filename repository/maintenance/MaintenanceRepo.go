@@ -0,0 +1,90 @@
+package maintenance
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+	waitlistRepo "sirclo/project/capstone/repository/waitlist"
+)
+
+type maintenanceRepo struct {
+	db *sql.DB
+}
+
+func NewMaintenanceRepo(db *sql.DB) *maintenanceRepo {
+	return &maintenanceRepo{db: db}
+}
+
+// get maintenance records, filtered by status when given
+func (mr *maintenanceRepo) Get(status string) ([]entities.AssetMaintenance, error) {
+	var condition string
+	var bind []interface{}
+
+	if status != "" {
+		bind = append(bind, status)
+		condition += " where m.status = ?"
+	}
+
+	var records []entities.AssetMaintenance
+	results, err := mr.db.Query(`select m.id, m.id_asset, a.name, COALESCE(m.id_request, 0), COALESCE(m.id_assessment, 0), m.status, COALESCE(m.notes, ''), m.opened_at, COALESCE(m.closed_at, '')
+								from asset_maintenance m
+								join assets a on a.id = m.id_asset`+condition+` order by m.opened_at asc`, bind...)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var record entities.AssetMaintenance
+
+		err = results.Scan(&record.Id, &record.Id_asset, &record.Asset_name, &record.Id_request, &record.Id_assessment, &record.Status, &record.Notes, &record.Opened_at, &record.Closed_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// close a maintenance record, the repaired unit is available again or held for the waitlist
+func (mr *maintenanceRepo) Close(id int, notes string) error {
+	tx, err := mr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idAsset int
+	row := tx.QueryRow(`select id_asset from asset_maintenance where id = ? and status = 'open' for update`, id)
+	if err := row.Scan(&idAsset); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("maintenance not found or already closed")
+		}
+		log.Println(err)
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE asset_maintenance SET status = 'closed', notes = concat_ws('\n', notes, nullif(?, '')), closed_at = now() WHERE id = ?`, notes, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE assets SET avail_quantity = least(avail_quantity + 1, initial_quantity), updated_at = now() WHERE id = ?`, idAsset)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if err := waitlistRepo.HoldUnits(tx, idAsset); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,8 @@
+package maintenance
+
+import "sirclo/project/capstone/entities"
+
+type MaintenanceRepo interface {
+	Get(status string) ([]entities.AssetMaintenance, error)
+	Close(id int, notes string) error
+}
@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type notificationRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) *notificationRepo {
+	return &notificationRepo{db: db}
+}
+
+// Notify stores a notification for a user, inside the transaction of the change it is about
+func Notify(tx *sql.Tx, idUser int, notificationType, message string, idReference int) error {
+	_, err := tx.Exec(`INSERT INTO notifications (id_user, type, message, id_reference, created_at) VALUES (?, ?, ?, ?, now())`,
+		idUser, notificationType, message, idReference)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+// get notifications of a user, newest first
+func (nr *notificationRepo) Get(idUser int, unread bool) ([]entities.Notification, error) {
+	condition := ""
+	if unread {
+		condition = "and read_at is null "
+	}
+
+	var notifications []entities.Notification
+	results, err := nr.db.Query(`select id, id_user, type, message, COALESCE(id_reference, 0), COALESCE(read_at, ''), created_at
+								from notifications
+								where id_user = ? `+condition+`order by created_at desc, id desc`, idUser)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var notification entities.Notification
+
+		err = results.Scan(&notification.Id, &notification.Id_user, &notification.Type, &notification.Message, &notification.Id_reference, &notification.Read_at, &notification.Created_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
+
+// mark a notification of the user as read
+func (nr *notificationRepo) MarkRead(id, idUser int) error {
+	res, err := nr.db.Exec(`UPDATE notifications SET read_at = now() WHERE id = ? AND id_user = ? AND read_at is null`, id, idUser)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("notification not found or already read")
+	}
+	return nil
+}
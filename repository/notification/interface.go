@@ -0,0 +1,8 @@
+package notification
+
+import "sirclo/project/capstone/entities"
+
+type NotificationRepo interface {
+	Get(idUser int, unread bool) ([]entities.Notification, error)
+	MarkRead(id, idUser int) error
+}
@@ -0,0 +1,249 @@
+package offboarding
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+
+	notificationRepo "sirclo/project/capstone/repository/notification"
+)
+
+type offboardingRepo struct {
+	db *sql.DB
+}
+
+func NewOffboardingRepo(db *sql.DB) *offboardingRepo {
+	return &offboardingRepo{db: db}
+}
+
+// get the assets a user holds, every loan handed over (6) or with a return requested (7) and the units left on it
+func (or *offboardingRepo) GetHoldings(idUser int) ([]entities.Holding, error) {
+	var holdings []entities.Holding
+	results, err := or.db.Query(`select r.id, COALESCE(r.id_kit_request, 0), a.id, COALESCE(a.tag, ''), a.name, c.description, r.quantity - r.returned_quantity,
+									r.id_status, s.description, r.request_date, COALESCE(r.return_date, '')
+								from requests r
+								join assets a on a.id = r.id_asset
+								join categories c on c.id = a.id_category
+								join status_check s on s.id = r.id_status
+								where r.id_user = ? and r.id_status in (6, 7) and r.deleted_at is null
+								order by r.request_date asc, r.id asc`, idUser)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var holding entities.Holding
+
+		err = results.Scan(&holding.Id_request, &holding.Id_kit_request, &holding.Id_asset, &holding.Tag, &holding.Asset_name, &holding.Category,
+			&holding.Quantity, &holding.Id_status, &holding.Status, &holding.Request_date, &holding.Return_date)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		holdings = append(holdings, holding)
+	}
+	return holdings, nil
+}
+
+// start the offboarding of a user, a return is requested for every asset they hold and each loan becomes an item
+// of the checklist. A user has one offboarding in progress at a time, until every item of it is done
+func (or *offboardingRepo) Start(idUser, idAdmin int) (int, error) {
+	tx, err := or.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var user int
+	row := tx.QueryRow(`select id from users where id = ? and deleted_at is null for update`, idUser)
+	if err := row.Scan(&user); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("user not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+
+	var inProgress int
+	row = tx.QueryRow(`select count(*) from offboardings o
+						join offboarding_items i on i.id_offboarding = o.id
+						join requests r on r.id = i.id_request
+						where o.id_user = ? and o.completed_at is null and i.written_off_at is null and r.id_status in (6, 7)`, idUser)
+	if err := row.Scan(&inProgress); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if inProgress > 0 {
+		return 0, fmt.Errorf("offboarding already in progress")
+	}
+
+	rows, err := tx.Query(`select id, id_status, quantity - returned_quantity from requests
+						where id_user = ? and id_status in (6, 7) and deleted_at is null for update`, idUser)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	var loans []entities.Holding
+	for rows.Next() {
+		var loan entities.Holding
+		if err := rows.Scan(&loan.Id_request, &loan.Id_status, &loan.Quantity); err != nil {
+			rows.Close()
+			log.Println(err)
+			return 0, err
+		}
+		loans = append(loans, loan)
+	}
+	rows.Close()
+
+	if len(loans) == 0 {
+		return 0, fmt.Errorf("user holds no assets")
+	}
+
+	res, err := tx.Exec(`INSERT INTO offboardings (id_user, id_admin, created_at) VALUES (?, ?, now())`, idUser, idAdmin)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	idOffboarding, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	for _, loan := range loans {
+		if loan.Id_status == 6 {
+			_, err := tx.Exec(`UPDATE requests SET id_status = 7, request_date = now(), updated_at = now() WHERE id = ?`, loan.Id_request)
+			if err != nil {
+				log.Println(err)
+				return 0, err
+			}
+			_, err = tx.Exec(`INSERT INTO request_events (id_request, id_user, id_status, created_at) VALUES (?, ?, 7, now())`, loan.Id_request, idAdmin)
+			if err != nil {
+				log.Println(err)
+				return 0, err
+			}
+		}
+
+		_, err := tx.Exec(`INSERT INTO offboarding_items (id_offboarding, id_request, quantity) VALUES (?, ?, ?)`, idOffboarding, loan.Id_request, loan.Quantity)
+		if err != nil {
+			log.Println(err)
+			return 0, err
+		}
+	}
+
+	message := fmt.Sprintf("please return the %d assets you hold before you leave", len(loans))
+	if err := notificationRepo.Notify(tx, idUser, "offboarding_started", message, int(idOffboarding)); err != nil {
+		return 0, err
+	}
+
+	return int(idOffboarding), tx.Commit()
+}
+
+// get the latest offboarding of a user with its checklist, an item is done once its loan is closed (returned or
+// transferred) or written off
+func (or *offboardingRepo) Get(idUser int) (entities.Offboarding, error) {
+	var offboarding entities.Offboarding
+	row := or.db.QueryRow(`select o.id, o.id_user, u.name, o.id_admin, o.created_at, COALESCE(o.completed_at, '')
+						from offboardings o
+						join users u on u.id = o.id_user
+						where o.id_user = ?
+						order by o.id desc limit 1`, idUser)
+	err := row.Scan(&offboarding.Id, &offboarding.Id_user, &offboarding.User_name, &offboarding.Id_admin, &offboarding.Created_at, &offboarding.Completed_at)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println(err)
+		}
+		return offboarding, err
+	}
+
+	results, err := or.db.Query(`select i.id, i.id_request, a.id, a.name, i.quantity,
+									case when i.written_off_at is not null then 'written_off' when r.id_status in (6, 7) then 'pending' else 'returned' end,
+									COALESCE(i.write_off_reason, ''), COALESCE(i.written_off_at, '')
+								from offboarding_items i
+								join requests r on r.id = i.id_request
+								join assets a on a.id = r.id_asset
+								where i.id_offboarding = ?
+								order by i.id asc`, offboarding.Id)
+	if err != nil {
+		log.Println(err)
+		return offboarding, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var item entities.OffboardingItem
+
+		err = results.Scan(&item.Id, &item.Id_request, &item.Id_asset, &item.Asset_name, &item.Quantity, &item.Status, &item.Write_off_reason, &item.Written_off_at)
+		if err != nil {
+			log.Println(err)
+			return offboarding, err
+		}
+
+		if item.Status == "pending" {
+			offboarding.Pending++
+		}
+		offboarding.Items = append(offboarding.Items, item)
+	}
+	offboarding.Completed = offboarding.Pending == 0
+	return offboarding, nil
+}
+
+// write off an item that will not come back, the loan is closed and the units left on it are taken out of the
+// quantity of the asset. They never go back to stock
+func (or *offboardingRepo) WriteOff(idItem, idAdmin int, reason string) error {
+	tx, err := or.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idRequest, idAsset, idStatus, remaining int
+	var writtenOff bool
+	row := tx.QueryRow(`select r.id, r.id_asset, r.id_status, r.quantity - r.returned_quantity, i.written_off_at is not null
+						from offboarding_items i
+						join requests r on r.id = i.id_request
+						where i.id = ? for update`, idItem)
+	if err := row.Scan(&idRequest, &idAsset, &idStatus, &remaining, &writtenOff); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("id not found")
+		}
+		log.Println(err)
+		return err
+	}
+	if writtenOff || (idStatus != 6 && idStatus != 7) {
+		return fmt.Errorf("item is already returned or written off")
+	}
+
+	_, err = tx.Exec(`UPDATE offboarding_items SET write_off_reason = ?, id_written_off_by = ?, written_off_at = now() WHERE id = ?`, reason, idAdmin, idItem)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	_, err = tx.Exec(`UPDATE requests SET id_status = 8, return_date = '0000-00-00', updated_at = now() WHERE id = ?`, idRequest)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO request_events (id_request, id_user, id_status, created_at) VALUES (?, ?, 8, now())`, idRequest, idAdmin)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	_, err = tx.Exec(`UPDATE assets SET initial_quantity = greatest(initial_quantity - ?, 0), updated_at = now() WHERE id = ?`, remaining, idAsset)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,10 @@
+package offboarding
+
+import "sirclo/project/capstone/entities"
+
+type OffboardingRepo interface {
+	GetHoldings(idUser int) ([]entities.Holding, error)
+	Start(idUser, idAdmin int) (int, error)
+	Get(idUser int) (entities.Offboarding, error)
+	WriteOff(idItem, idAdmin int, reason string) error
+}
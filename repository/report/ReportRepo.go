@@ -0,0 +1,51 @@
+package report
+
+import (
+	"database/sql"
+	"log"
+
+	"sirclo/project/capstone/entities"
+)
+
+type reportRepo struct {
+	db *sql.DB
+}
+
+func NewReportRepo(db *sql.DB) *reportRepo {
+	return &reportRepo{db: db}
+}
+
+// get assets owned on the given date with the depreciation policy of their category,
+// assets not purchased yet or already disposed/sold on that date are left out
+func (rr *reportRepo) GetValuationAssets(asOf string) ([]entities.ValuationAsset, error) {
+	var assets []entities.ValuationAsset
+	results, err := rr.db.Query(`select a.id, a.name, a.initial_quantity, COALESCE(a.purchase_date, ''), COALESCE(a.purchase_price, 0),
+									c.id, c.description, c.depreciation_method, c.useful_life_months, c.salvage_rate, c.declining_factor
+								from assets a
+								join categories c on c.id = a.id_category
+								left join asset_disposals d on d.id_asset = a.id
+								where a.deleted_at is null and a.lifecycle_status != 'ordered'
+									and (a.purchase_date is null or a.purchase_date <= ?)
+									and (d.id is null or d.disposal_date > ?)
+								order by c.id asc, a.id asc`, asOf, asOf)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var asset entities.ValuationAsset
+
+		err = results.Scan(&asset.Id, &asset.Name, &asset.Quantity, &asset.Purchase_date, &asset.Purchase_price,
+			&asset.Id_category, &asset.Category, &asset.Depreciation_method, &asset.Useful_life_months, &asset.Salvage_rate, &asset.Declining_factor)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		assets = append(assets, asset)
+	}
+	return assets, nil
+}
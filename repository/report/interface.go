@@ -0,0 +1,7 @@
+package report
+
+import "sirclo/project/capstone/entities"
+
+type ReportRepo interface {
+	GetValuationAssets(asOf string) ([]entities.ValuationAsset, error)
+}
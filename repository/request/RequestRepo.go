@@ -5,6 +5,12 @@ import (
 	"fmt"
 	"log"
 	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util/pagination"
+	"strings"
+
+	commentRepo "sirclo/project/capstone/repository/comment"
+	notificationRepo "sirclo/project/capstone/repository/notification"
+	waitlistRepo "sirclo/project/capstone/repository/waitlist"
 )
 
 type requestRepo struct {
@@ -15,9 +21,32 @@ func NewRequestRepo(db *sql.DB) *requestRepo {
 	return &requestRepo{db: db}
 }
 
-// create request
+// reservedUnits is the units of the asset a kept for approved reservations that start before a loan is back on its
+// return date (an SQL expression), every approved reservation when the loan has no return date
+func reservedUnits(returnDate string) string {
+	return `(select COALESCE(sum(v.quantity), 0) from reservations v where v.id_asset = a.id and v.status = 'approved'
+		and (COALESCE(` + returnDate + `, '') = '' or v.start_at < ` + returnDate + `))`
+}
+
+// takeUnits takes units of an asset in service out of the stock for a loan back on its return date, none are
+// taken when it would leave fewer units than the ones kept for reservations (see reservedUnits)
+func takeUnits(tx *sql.Tx, idAsset, quantity int, returnDate string) (bool, error) {
+	res, err := tx.Exec(`UPDATE assets a SET a.avail_quantity = a.avail_quantity - ?, a.updated_at = now()
+						WHERE a.id = ? AND a.lifecycle_status = 'in_service' AND a.deleted_at is null
+						AND a.avail_quantity - `+reservedUnits("?")+` >= ?`, quantity, idAsset, returnDate, returnDate, quantity)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	row, _ := res.RowsAffected()
+	return row > 0, nil
+}
+
+// create request, only assets in service with enough units available can be requested, units kept for upcoming
+// reservations are not available
 func (rr *requestRepo) Create(request entities.Request) error {
-	query := (`INSERT INTO requests (id_user, id_asset, id_status, request_date, return_date, description, created_at, updated_at) VALUES (?, ?, ?, now(), ?, ?, now(), now())`)
+	query := (`INSERT INTO requests (id_user, id_asset, id_status, quantity, request_date, return_date, description, created_at, updated_at)
+	SELECT ?, a.id, ?, ?, now(), ?, ?, now(), now() FROM assets a WHERE a.id = ? AND a.lifecycle_status = 'in_service' AND a.avail_quantity - ` + reservedUnits("?") + ` >= ? AND a.deleted_at is null`)
 
 	statement, err := rr.db.Prepare(query)
 	if err != nil {
@@ -26,39 +55,63 @@ func (rr *requestRepo) Create(request entities.Request) error {
 	}
 	defer statement.Close()
 
-	_, err = statement.Exec(request.Id_user, request.Id_asset, request.Id_status, request.Return_date, request.Description)
+	res, err := statement.Exec(request.Id_user, request.Id_status, request.Quantity, request.Return_date, request.Description, request.Id_asset, request.Return_date, request.Return_date, request.Quantity)
 	if err != nil {
 		log.Println(err)
 		return err
 	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("asset not found, not in service or not enough units available")
+	}
 	return nil
 }
 
+// get available quantity of an asset in service for a loan until the return date, without the units kept for upcoming
+// reservations. 0 when the asset cannot be requested
+func (rr *requestRepo) GetAssetAvailQty(idAsset int, returnDate string) (int, error) {
+	var availQuantity int
+
+	row := rr.db.QueryRow(`select if(a.lifecycle_status = 'in_service', greatest(a.avail_quantity - `+reservedUnits("?")+`, 0), 0)
+							from assets a where a.id = ? and a.deleted_at is null`, returnDate, returnDate, idAsset)
+	err := row.Scan(&availQuantity)
+	if err != nil {
+		return 0, err
+	}
+	return availQuantity, nil
+}
+
 // get request by id
 func (rr *requestRepo) GetById(id int) (entities.RequestResponse, error) {
 	var request entities.RequestResponse
 	if request.Return_date != "" {
-		row := rr.db.QueryRow(`select r.id, r.id_user, r.id_asset, r.id_status, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status 
+		row := rr.db.QueryRow(`select r.id, r.id_user, r.id_asset, r.id_status, r.quantity, r.returned_quantity, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status, COALESCE(a.id_location, 0), concat_ws(' / ', ls.name, lb.name, l.name) as pickup_location
 		from requests r
 		join users u on u.id = r.id_user
 		join status_check s on s.id = r.id_status
 		join assets a on a.id = r.id_asset
 			join categories c on c.id = a.id_category
+			left join locations l on l.id = a.id_location
+			left join locations lb on lb.id = l.id_parent
+			left join locations ls on ls.id = lb.id_parent
 		where r.id = ? and r.deleted_at is nul`, id)
-		err := row.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status)
+		err := row.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Quantity, &request.Returned_quantity, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status, &request.Id_location, &request.Pickup_location)
 		if err != nil {
 			log.Println(err)
 			return request, err
 		}
 	} else {
-		row := rr.db.QueryRow(`select r.id, r.id_user, r.id_asset, r.id_status, r.request_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status 
+		row := rr.db.QueryRow(`select r.id, r.id_user, r.id_asset, r.id_status, r.quantity, r.returned_quantity, r.request_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status, COALESCE(a.id_location, 0), concat_ws(' / ', ls.name, lb.name, l.name) as pickup_location
 		from requests r
 		join users u on u.id = r.id_user
 		join status_check s on s.id = r.id_status
 		join assets a on a.id = r.id_asset
 			join categories c on c.id = a.id_category
+			left join locations l on l.id = a.id_location
+			left join locations lb on lb.id = l.id_parent
+			left join locations ls on ls.id = lb.id_parent
 		where r.id = ?`, id)
-		err := row.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Request_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status)
+		err := row.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Quantity, &request.Returned_quantity, &request.Request_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status, &request.Id_location, &request.Pickup_location)
 		if err != nil {
 			log.Println(err)
 			return request, err
@@ -102,58 +155,46 @@ func (rr *requestRepo) Update(request entities.Request, id int) error {
 	return err
 }
 
-// update available quantity asset
-func (rr *requestRepo) UpdateAvailQty(quantity, id int) error {
-	query := (`update requests r 
-	join assets a on a.id = r.id_asset
-	set avail_quantity = ?
-	where r.id = ?`)
-
-	res, err := rr.db.Exec(query, quantity, id)
-	row, _ := res.RowsAffected()
-	if row == 0 {
-		return fmt.Errorf("id not found")
-	}
-	return err
-}
-
-// get asset available qty based on id_asset
+// get asset available qty based on id_asset, without the units kept for reservations starting before the loan is back
 func (rr *requestRepo) GetAvailQty(id int) (entities.Request, error) {
 	var request entities.Request
 
-	row := rr.db.QueryRow(`select r.id, r.id_asset, a.initial_quantity, a.avail_quantity from requests r
+	row := rr.db.QueryRow(`select r.id, r.id_asset, r.quantity, r.returned_quantity, a.initial_quantity, a.avail_quantity - `+reservedUnits("r.return_date")+` from requests r
 	join assets a on a.id = r.id_asset
 	where r.id = ? and r.deleted_at is null`, id)
 
-	err := row.Scan(&request.Id, &request.Id_asset, &request.Initial_quantity, &request.Avail_quantity)
+	err := row.Scan(&request.Id, &request.Id_asset, &request.Quantity, &request.Returned_quantity, &request.Initial_quantity, &request.Avail_quantity)
 	if err != nil {
 		return request, err
 	}
 	return request, nil
 }
 
-// get requests (admin)
-func (rr *requestRepo) GetAdmin(returnDate, requestDate, status, filterDate, category string, limit, offset int) ([]entities.RequestResponse, error) {
-	var condition string
-	var condLimit string
-	var requests []entities.RequestResponse
+// requestOrder is the order by clause of the request lists, by request date then by return date
+func requestOrder(returnDate, requestDate string) string {
+	var orders []string
+	if requestDate == "latest" {
+		orders = append(orders, "r.request_date desc")
+	} else if requestDate == "oldest" {
+		orders = append(orders, "r.request_date asc")
+	}
 
-	var bind []interface{}
+	if returnDate == "longest" {
+		orders = append(orders, "r.return_date desc")
+	} else if returnDate == "shortest" {
+		orders = append(orders, "r.return_date asc")
+	}
 
-	if status != "" {
-		switch status {
-		case "new":
-			condition += "and (r.id_status = 1 or r.id_status = 3) "
-		case "using":
-			condition += "and r.id_status = 6 "
-		case "reject":
-			condition += "and (r.id_status = 4 or r.id_status = 5) "
-		case "returned":
-			condition += "and r.id_status = 8 "
-		default:
-			condition += ""
-		}
+	if len(orders) == 0 {
+		return ""
 	}
+	return "order by " + strings.Join(orders, ", ") + " "
+}
+
+// requestFilter is the condition of the request date and category filters shared by the admin and the manager
+func requestFilter(filterDate, category string) (string, []interface{}) {
+	var condition string
+	var bind []interface{}
 
 	if filterDate != "" {
 		bind = append(bind, "%"+filterDate+"%")
@@ -164,37 +205,46 @@ func (rr *requestRepo) GetAdmin(returnDate, requestDate, status, filterDate, cat
 		bind = append(bind, "%"+category+"%")
 		condition += "and c.description LIKE ? "
 	}
+	return condition, bind
+}
 
-	if requestDate == "latest" {
-		condition += "order by r.request_date desc "
-	} else if requestDate == "oldest" {
-		condition += "order by r.request_date asc "
-	}
+// adminCondition is the condition of the requests of the admin, shared by the list and its count
+func adminCondition(status, filterDate, category string) (string, []interface{}) {
+	var condition string
 
-	if returnDate == "longest" {
-		condition += "order by r.return_date desc "
-	} else if returnDate == "shortest" {
-		condition += "order by r.return_date asc "
+	switch status {
+	case "new":
+		condition += "and (r.id_status = 1 or r.id_status = 3) "
+	case "using":
+		condition += "and r.id_status = 6 "
+	case "reject":
+		condition += "and (r.id_status = 4 or r.id_status = 5) "
+	case "returned":
+		condition += "and r.id_status = 8 "
 	}
 
-	if limit != 0 && offset == 0 {
-		bind = append(bind, limit)
-		condLimit += "limit ?"
-	}
+	filter, bind := requestFilter(filterDate, category)
+	return condition + filter, bind
+}
 
-	if limit != 0 && offset != 0 {
-		bind = append(bind, offset)
-		bind = append(bind, limit)
-		condLimit += "limit ?, ?"
-	}
+// get requests (admin)
+func (rr *requestRepo) GetAdmin(returnDate, requestDate, status, filterDate, category string, page pagination.Page) ([]entities.RequestResponse, error) {
+	var requests []entities.RequestResponse
+
+	condition, bind := adminCondition(status, filterDate, category)
+	condLimit, bindLimit := page.Clause()
+	bind = append(bind, bindLimit...)
 
-	res, err := rr.db.Query(`select r.id, r.id_user, r.id_asset, r.id_status, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status 
+	res, err := rr.db.Query(`select r.id, r.id_user, r.id_asset, r.id_status, r.quantity, r.returned_quantity, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status, COALESCE(a.id_location, 0), concat_ws(' / ', ls.name, lb.name, l.name) as pickup_location, COALESCE(r.sla_breached_status = r.id_status, false)
 	from requests r
 	join users u on u.id = r.id_user
 	join status_check s on s.id = r.id_status
 	join assets a on a.id = r.id_asset
 		join categories c on c.id = a.id_category
-	where id_status != 0	`+condition+condLimit, bind...)
+		left join locations l on l.id = a.id_location
+		left join locations lb on lb.id = l.id_parent
+		left join locations ls on ls.id = lb.id_parent
+	where id_status != 0	`+condition+requestOrder(returnDate, requestDate)+condLimit, bind...)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -204,7 +254,7 @@ func (rr *requestRepo) GetAdmin(returnDate, requestDate, status, filterDate, cat
 	for res.Next() {
 		var request entities.RequestResponse
 
-		err = res.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status)
+		err = res.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Quantity, &request.Returned_quantity, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status, &request.Id_location, &request.Pickup_location, &request.Sla_breached)
 		if err != nil {
 			fmt.Println(err)
 			return nil, err
@@ -215,69 +265,64 @@ func (rr *requestRepo) GetAdmin(returnDate, requestDate, status, filterDate, cat
 	return requests, nil
 }
 
-// get requests (manager)
-func (rr *requestRepo) GetManager(returnDate, requestDate, status, filterDate, category string, limit, offset int) ([]entities.RequestResponse, error) {
-	var condition string
-	var requests []entities.RequestResponse
-	var condLimit string
-
-	var bind []interface{}
-
-	if status != "" {
-		switch status {
-		case "new":
-			condition += "and r.id_status = 2 "
-		case "using":
-			condition += "and r.id_status = 6 "
-		case "reject":
-			condition += "and r.id_status = 4 "
-		case "returned":
-			condition += "and r.id_status = 8 "
-		default:
-			condition += ""
-		}
-	}
+// count requests (admin)
+func (rr *requestRepo) CountAdmin(status, filterDate, category string) (int, error) {
+	condition, bind := adminCondition(status, filterDate, category)
 
-	if filterDate != "" {
-		bind = append(bind, "%"+filterDate+"%")
-		condition += "and r.request_date LIKE ? "
+	var total int
+	row := rr.db.QueryRow(`select count(*)
+	from requests r
+	join assets a on a.id = r.id_asset
+		join categories c on c.id = a.id_category
+	where id_status != 0	`+condition, bind...)
+	if err := row.Scan(&total); err != nil {
+		log.Println(err)
+		return 0, err
 	}
+	return total, nil
+}
 
-	if category != "" {
-		bind = append(bind, "%"+category+"%")
-		condition += "and c.description LIKE ? "
-	}
+// managerCondition is the condition of the requests of the manager, the requests of employees of the division of
+// the manager and of the divisions the user approves for as a delegate. Shared by the list and its count
+func managerCondition(idManager int, status, filterDate, category string) (string, []interface{}) {
+	condition := `and (u.divisi = (select divisi from users where id = ? and id_role = 3) or u.divisi in (select m.divisi from approval_delegations d
+		join users m on m.id = d.id_manager
+		where d.id_delegate = ? and d.revoked_at is null and now() between d.start_at and d.end_at)) and id_status != 1 and id_status != 5 and id_status != 7 `
+	bind := []interface{}{idManager, idManager}
 
-	if requestDate == "latest" {
-		condition += "order by r.request_date desc "
-	} else if requestDate == "oldest" {
-		condition += "order by r.request_date asc "
+	switch status {
+	case "new":
+		condition += "and r.id_status = 2 "
+	case "using":
+		condition += "and r.id_status = 6 "
+	case "reject":
+		condition += "and r.id_status = 4 "
+	case "returned":
+		condition += "and r.id_status = 8 "
 	}
 
-	if returnDate == "longest" {
-		condition += "order by r.return_date desc "
-	} else if returnDate == "shortest" {
-		condition += "order by r.return_date asc "
-	}
+	filter, bindFilter := requestFilter(filterDate, category)
+	return condition + filter, append(bind, bindFilter...)
+}
 
-	if limit != 0 && offset == 0 {
-		bind = append(bind, limit)
-		condLimit += "limit ?"
-	}
+// get requests (manager)
+func (rr *requestRepo) GetManager(idManager int, returnDate, requestDate, status, filterDate, category string, page pagination.Page) ([]entities.RequestResponse, error) {
+	var requests []entities.RequestResponse
 
-	if limit != 0 && offset != 0 {
-		bind = append(bind, offset)
-		bind = append(bind, limit)
-		condLimit += "limit ?, ?"
-	}
+	condition, bind := managerCondition(idManager, status, filterDate, category)
+	condLimit, bindLimit := page.Clause()
+	bind = append(bind, bindLimit...)
 
-	res, err := rr.db.Query(`select r.id, r.id_user, r.id_asset, r.id_status, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status 
+	res, err := rr.db.Query(`select r.id, r.id_user, r.id_asset, r.id_status, r.quantity, r.returned_quantity, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status, COALESCE(a.id_location, 0), concat_ws(' / ', ls.name, lb.name, l.name) as pickup_location, COALESCE(r.sla_breached_status = r.id_status, false)
 	from requests r
 	join users u on u.id = r.id_user
 	join status_check s on s.id = r.id_status
 	join assets a on a.id = r.id_asset
 		join categories c on c.id = a.id_category
-	where id_status != 0 and id_status != 1 and id_status != 5 and id_status != 7	`+condition+condLimit, bind...)
+		left join locations l on l.id = a.id_location
+		left join locations lb on lb.id = l.id_parent
+		left join locations ls on ls.id = lb.id_parent
+	where id_status != 0	`+condition+requestOrder(returnDate, requestDate)+condLimit, bind...)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -287,7 +332,7 @@ func (rr *requestRepo) GetManager(returnDate, requestDate, status, filterDate, c
 	for res.Next() {
 		var request entities.RequestResponse
 
-		err = res.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status)
+		err = res.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Quantity, &request.Returned_quantity, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status, &request.Id_location, &request.Pickup_location, &request.Sla_breached)
 		if err != nil {
 			fmt.Println(err)
 			return nil, err
@@ -298,37 +343,63 @@ func (rr *requestRepo) GetManager(returnDate, requestDate, status, filterDate, c
 	return requests, nil
 }
 
-func (rr *requestRepo) GetEmployee(idEmployee int, isHistory bool, limit, offset int) ([]entities.RequestResponse, error) {
-	var condition string
-	var condLimit string
-	var requests []entities.RequestResponse
+// count requests (manager)
+func (rr *requestRepo) CountManager(idManager int, status, filterDate, category string) (int, error) {
+	condition, bind := managerCondition(idManager, status, filterDate, category)
 
-	var bind []interface{}
-	bind = append(bind, idEmployee)
-	if isHistory == true {
+	var total int
+	row := rr.db.QueryRow(`select count(*)
+	from requests r
+	join users u on u.id = r.id_user
+	join assets a on a.id = r.id_asset
+		join categories c on c.id = a.id_category
+	where id_status != 0	`+condition, bind...)
+	if err := row.Scan(&total); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// employeeCondition is the condition of the requests of an employee, the loans only for their history
+func employeeCondition(idEmployee int, isHistory bool) (string, []interface{}) {
+	condition := "r.id_user = ? "
+	if isHistory {
 		condition += "and r.id_status in (6,7,8) "
 	}
+	return condition, []interface{}{idEmployee}
+}
 
-	condition += "order by r.updated_at desc "
+// get requests of an employee, last updated first. A page by cursor goes newest request first, on the id that
+// never changes, and starts after the last request of the previous page
+func (rr *requestRepo) GetEmployee(idEmployee int, isHistory bool, page pagination.Page) ([]entities.RequestResponse, error) {
+	var requests []entities.RequestResponse
 
-	if limit != 0 && offset == 0 {
-		bind = append(bind, limit)
-		condLimit += "limit ?"
+	condition, bind := employeeCondition(idEmployee, isHistory)
+	if page.Keyset {
+		if page.After.Id != 0 {
+			bind = append(bind, page.After.Id)
+			condition += "and r.id < ? "
+		}
+		condition += "order by r.id desc "
+	} else {
+		condition += "order by r.updated_at desc, r.id desc "
 	}
 
-	if limit != 0 && offset != 0 {
-		bind = append(bind, offset)
-		bind = append(bind, limit)
-		condLimit += "limit ?, ?"
-	}
+	condLimit, bindLimit := page.Clause()
+	bind = append(bind, bindLimit...)
 
-	res, err := rr.db.Query(`select r.id, r.id_user, r.id_asset, r.id_status, a.id_category, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status , a.photo
+	res, err := rr.db.Query(`select r.id, r.id_user, r.id_asset, r.id_status, r.quantity, r.returned_quantity, a.id_category, r.request_date, r.return_date, r.description, u.name as user_name, a.name as asset_name, c.description as category, a.avail_quantity, s.description as status , a.photo, COALESCE(a.photo_thumbnail, ''), COALESCE(a.id_location, 0), concat_ws(' / ', ls.name, lb.name, l.name) as pickup_location, COALESCE(e.status, ''), COALESCE(e.return_date, '')
 	from requests r
 	join users u on u.id = r.id_user
 	join status_check s on s.id = r.id_status
 	join assets a on a.id = r.id_asset
 		join categories c on c.id = a.id_category
-	where r.id_user = ? `+condition+condLimit, bind...)
+		left join locations l on l.id = a.id_location
+		left join locations lb on lb.id = l.id_parent
+		left join locations ls on ls.id = lb.id_parent
+	left join request_extensions e on e.id = (select max(id) from request_extensions where id_request = r.id)
+	where `+condition+condLimit, bind...)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -338,7 +409,7 @@ func (rr *requestRepo) GetEmployee(idEmployee int, isHistory bool, limit, offset
 	for res.Next() {
 		var request entities.RequestResponse
 
-		err = res.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Id_category, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status, &request.Photo)
+		err = res.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Quantity, &request.Returned_quantity, &request.Id_category, &request.Request_date, &request.Return_date, &request.Description, &request.User_name, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status, &request.Photo, &request.Photo_thumbnail, &request.Id_location, &request.Pickup_location, &request.Extension_status, &request.Extension_date)
 		if err != nil {
 			fmt.Println(err)
 			return nil, err
@@ -348,3 +419,1013 @@ func (rr *requestRepo) GetEmployee(idEmployee int, isHistory bool, limit, offset
 	}
 	return requests, nil
 }
+
+// count requests of an employee
+func (rr *requestRepo) CountEmployee(idEmployee int, isHistory bool) (int, error) {
+	condition, bind := employeeCondition(idEmployee, isHistory)
+
+	var total int
+	row := rr.db.QueryRow(`select count(*) from requests r where `+condition, bind...)
+	if err := row.Scan(&total); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// check out an asset at the desk against the oldest request approved by the manager for this user, units kept
+// for reservations starting before the loan is back can not be checked out. Kit components are not checked out
+// here, they are handed over through their kit
+func (rr *requestRepo) CheckOut(tag string, idUser, idAdmin int, notes string) (int, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var idAsset int
+	var lifecycleStatus string
+	row := tx.QueryRow(`select id, lifecycle_status from assets where tag = ? and deleted_at is null for update`, tag)
+	if err := row.Scan(&idAsset, &lifecycleStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("asset not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+	if lifecycleStatus != "in_service" {
+		return 0, fmt.Errorf("asset is not in service")
+	}
+
+	var idRequest, quantity int
+	var returnDate string
+	row = tx.QueryRow(`select id, quantity, COALESCE(return_date, '') from requests where id_asset = ? and id_user = ? and id_status = 3 and id_kit_request is null and deleted_at is null
+						order by request_date asc, id asc limit 1 for update`, idAsset, idUser)
+	if err := row.Scan(&idRequest, &quantity, &returnDate); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("no approved request for this asset and user")
+		}
+		log.Println(err)
+		return 0, err
+	}
+
+	taken, err := takeUnits(tx, idAsset, quantity, returnDate)
+	if err != nil {
+		return 0, err
+	}
+	if !taken {
+		return 0, fmt.Errorf("asset is not available")
+	}
+
+	if _, err := tx.Exec(`UPDATE requests SET id_status = 6, request_date = now(), updated_at = now() WHERE id = ?`, idRequest); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if err := createHandover(tx, idRequest, idAdmin, "check_out", notes); err != nil {
+		return 0, err
+	}
+
+	return idRequest, tx.Commit()
+}
+
+// hand over a request approved by the manager (status 3), every unit of the request leaves the stock in the same
+// transaction as the status so two hand overs can not take the same units. Units kept for reservations starting
+// before the loan is back can not be handed over. The return date is kept unless given
+func (rr *requestRepo) HandOver(idRequest int, returnDate string) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idAsset, quantity, idStatus int
+	var currentReturnDate string
+	row := tx.QueryRow(`select id_asset, quantity, id_status, COALESCE(return_date, '') from requests where id = ? and deleted_at is null for update`, idRequest)
+	if err := row.Scan(&idAsset, &quantity, &idStatus, &currentReturnDate); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("id not found")
+		}
+		log.Println(err)
+		return err
+	}
+	if idStatus != 3 {
+		return fmt.Errorf("request is not approved by the manager")
+	}
+
+	if returnDate == "" {
+		returnDate = currentReturnDate
+	}
+
+	taken, err := takeUnits(tx, idAsset, quantity, returnDate)
+	if err != nil {
+		return err
+	}
+	if !taken {
+		return fmt.Errorf("not enough units available")
+	}
+
+	_, err = tx.Exec(`UPDATE requests SET id_status = 6, return_date = NULLIF(?, ''), request_date = now(), updated_at = now() WHERE id = ?`, returnDate, idRequest)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// check in an asset at the desk, idUser is only needed when several users hold the same asset
+func (rr *requestRepo) CheckIn(tag string, idUser int, notes string, assessment entities.ReturnAssessment) (int, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var idAsset int
+	row := tx.QueryRow(`select id from assets where tag = ? and deleted_at is null for update`, tag)
+	if err := row.Scan(&idAsset); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("asset not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+
+	condition := ""
+	bind := []interface{}{idAsset}
+	if idUser != 0 {
+		condition = " and id_user = ?"
+		bind = append(bind, idUser)
+	}
+
+	// loans with a return requested first
+	results, err := tx.Query(`select id, id_user from requests where id_asset = ? and id_status in (6, 7) and deleted_at is null`+condition+`
+							order by id_status desc, request_date asc for update`, bind...)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	var loans []entities.Request
+	for results.Next() {
+		var loan entities.Request
+		if err := results.Scan(&loan.Id, &loan.Id_user); err != nil {
+			results.Close()
+			log.Println(err)
+			return 0, err
+		}
+		loans = append(loans, loan)
+	}
+	results.Close()
+
+	if len(loans) == 0 {
+		return 0, fmt.Errorf("no active loan for this asset")
+	}
+	if len(loans) > 1 && idUser == 0 {
+		return 0, fmt.Errorf("several loans found for this asset, scan the user badge")
+	}
+
+	assessment.Id_request = loans[0].Id
+	assessment.Id_asset = idAsset
+	assessment.Id_user = loans[0].Id_user
+	if err := returnLoan(tx, assessment); err != nil {
+		return 0, err
+	}
+	if err := createHandover(tx, assessment.Id_request, assessment.Id_assessor, "check_in", notes); err != nil {
+		return 0, err
+	}
+
+	return assessment.Id_request, tx.Commit()
+}
+
+// return an asset on loan (status 6 or 7) with the assessment of its condition
+func (rr *requestRepo) Return(idRequest int, assessment entities.ReturnAssessment) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idStatus int
+	row := tx.QueryRow(`select id_asset, id_user, id_status from requests where id = ? and deleted_at is null for update`, idRequest)
+	if err := row.Scan(&assessment.Id_asset, &assessment.Id_user, &idStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("id not found")
+		}
+		log.Println(err)
+		return err
+	}
+	if idStatus != 6 && idStatus != 7 {
+		return fmt.Errorf("request is not on loan")
+	}
+
+	assessment.Id_request = idRequest
+	if err := returnLoan(tx, assessment); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// transfer an active loan to another employee, the loan is closed and a new one handed over to the recipient for the
+// units left with the same return date. The units never go back to stock so the available quantity is untouched
+func (rr *requestRepo) Transfer(idRequest, idRecipient, idAdmin int, notes string) (int, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var idUser, idAsset, idStatus, idKitRequest, remaining int
+	var returnDate, assetName string
+	row := tx.QueryRow(`select r.id_user, r.id_asset, r.id_status, COALESCE(r.id_kit_request, 0), r.quantity - r.returned_quantity, COALESCE(r.return_date, ''), a.name
+						from requests r
+						join assets a on a.id = r.id_asset
+						where r.id = ? and r.deleted_at is null for update`, idRequest)
+	if err := row.Scan(&idUser, &idAsset, &idStatus, &idKitRequest, &remaining, &returnDate, &assetName); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("id not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+	if idStatus != 6 && idStatus != 7 {
+		return 0, fmt.Errorf("request is not on loan")
+	}
+	if idKitRequest != 0 {
+		return 0, fmt.Errorf("request is part of a kit request")
+	}
+	if idUser == idRecipient {
+		return 0, fmt.Errorf("asset is already lent to the recipient")
+	}
+
+	// like a request, a loan only goes to an employee
+	var recipientRole int
+	row = tx.QueryRow(`select COALESCE(id_role, 0) from users where id = ? and deleted_at is null`, idRecipient)
+	if err := row.Scan(&recipientRole); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("recipient not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+	if recipientRole != 2 {
+		return 0, fmt.Errorf("recipient must be an employee")
+	}
+
+	_, err = tx.Exec(`UPDATE requests SET returned_quantity = quantity, id_status = 8, return_date = '0000-00-00', updated_at = now() WHERE id = ?`, idRequest)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if err := createHandover(tx, idRequest, idAdmin, "transfer_out", notes); err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Exec(`INSERT INTO requests (id_user, id_asset, id_status, quantity, request_date, return_date, description, created_at, updated_at)
+						VALUES (?, ?, 6, ?, now(), nullif(?, ''), ?, now(), now())`,
+		idRecipient, idAsset, remaining, returnDate, fmt.Sprintf("transferred from request %d", idRequest))
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	idTransfer, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if err := createHandover(tx, int(idTransfer), idAdmin, "transfer_in", notes); err != nil {
+		return 0, err
+	}
+
+	for _, event := range []entities.RequestEvent{{Id_request: idRequest, Id_status: 8}, {Id_request: int(idTransfer), Id_status: 6}} {
+		_, err := tx.Exec(`INSERT INTO request_events (id_request, id_user, id_status, created_at) VALUES (?, ?, ?, now())`, event.Id_request, idAdmin, event.Id_status)
+		if err != nil {
+			log.Println(err)
+			return 0, err
+		}
+	}
+
+	if err := notificationRepo.Notify(tx, idUser, "request_transferred", fmt.Sprintf("your loan of %s was transferred to another employee", assetName), idRequest); err != nil {
+		return 0, err
+	}
+	if err := notificationRepo.Notify(tx, idRecipient, "request_transferred", fmt.Sprintf("%s was transferred to you", assetName), int(idTransfer)); err != nil {
+		return 0, err
+	}
+
+	return int(idTransfer), tx.Commit()
+}
+
+// returnLoan returns units of a loan with the assessment of their condition, every unit left when the quantity
+// is not given. The assessor is the admin who took the units back, none when the employee returns them. The loan
+// is closed once every unit is back, damaged units go to maintenance instead of back to the available quantity
+// and units back in stock are held for the waitlist first
+func returnLoan(tx *sql.Tx, assessment entities.ReturnAssessment) error {
+	var quantity, returnedQuantity int
+	row := tx.QueryRow(`select quantity, returned_quantity from requests where id = ? for update`, assessment.Id_request)
+	if err := row.Scan(&quantity, &returnedQuantity); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	remaining := quantity - returnedQuantity
+	if assessment.Quantity == 0 {
+		assessment.Quantity = remaining
+	}
+	if assessment.Quantity > remaining {
+		return fmt.Errorf("only %d units left to return", remaining)
+	}
+
+	query := `UPDATE requests SET returned_quantity = returned_quantity + ?, updated_at = now() WHERE id = ?`
+	if assessment.Quantity == remaining {
+		query = `UPDATE requests SET returned_quantity = returned_quantity + ?, id_status = 8, return_date = '0000-00-00', updated_at = now() WHERE id = ?`
+	}
+	if _, err := tx.Exec(query, assessment.Quantity, assessment.Id_request); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	res, err := tx.Exec(`INSERT INTO return_assessments (id_request, id_asset, id_user, id_assessor, grade, quantity, damage_notes, created_at) VALUES (?, ?, ?, NULLIF(?, 0), ?, ?, ?, now())`,
+		assessment.Id_request, assessment.Id_asset, assessment.Id_user, assessment.Id_assessor, assessment.Grade, assessment.Quantity, assessment.Damage_notes)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	idAssessment, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	for _, photo := range assessment.Photos {
+		_, err := tx.Exec(`INSERT INTO return_assessment_photos (id_assessment, url, url_thumbnail) VALUES (?, ?, ?)`, idAssessment, photo.Url, photo.Url_thumbnail)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+	}
+
+	// one maintenance record per damaged unit, each unit is back in stock when its record is closed
+	if assessment.Grade == "damaged" {
+		for i := 0; i < assessment.Quantity; i++ {
+			_, err = tx.Exec(`INSERT INTO asset_maintenance (id_asset, id_request, id_assessment, status, notes, opened_at) VALUES (?, ?, ?, 'open', ?, now())`,
+				assessment.Id_asset, assessment.Id_request, idAssessment, assessment.Damage_notes)
+			if err != nil {
+				log.Println(err)
+				return err
+			}
+		}
+		return nil
+	}
+
+	_, err = tx.Exec(`UPDATE assets SET avail_quantity = least(avail_quantity + ?, initial_quantity), updated_at = now() WHERE id = ?`, assessment.Quantity, assessment.Id_asset)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return waitlistRepo.HoldUnits(tx, assessment.Id_asset)
+}
+
+// get assets returned in poor condition or damaged by a user
+func (rr *requestRepo) GetDamageHistory(idUser int) ([]entities.ReturnAssessment, error) {
+	results, err := rr.db.Query(`select ra.id, ra.id_request, ra.id_asset, a.name, ra.id_user, COALESCE(ra.id_assessor, 0), ra.grade, ra.quantity, COALESCE(ra.damage_notes, ''), COALESCE(m.id, 0), ra.created_at
+								from return_assessments ra
+								join assets a on a.id = ra.id_asset
+								left join (select id_assessment, min(id) as id from asset_maintenance group by id_assessment) m on m.id_assessment = ra.id
+								where ra.id_user = ? and ra.grade in ('poor', 'damaged') order by ra.created_at desc`, idUser)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	var damages []entities.ReturnAssessment
+	index := map[int]int{}
+	for results.Next() {
+		var damage entities.ReturnAssessment
+
+		err = results.Scan(&damage.Id, &damage.Id_request, &damage.Id_asset, &damage.Asset_name, &damage.Id_user, &damage.Id_assessor, &damage.Grade, &damage.Quantity, &damage.Damage_notes, &damage.Id_maintenance, &damage.Created_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		index[damage.Id] = len(damages)
+		damages = append(damages, damage)
+	}
+
+	photos, err := rr.db.Query(`select p.id_assessment, p.url, COALESCE(p.url_thumbnail, '')
+								from return_assessment_photos p
+								join return_assessments ra on ra.id = p.id_assessment
+								where ra.id_user = ? and ra.grade in ('poor', 'damaged') order by p.id asc`, idUser)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer photos.Close()
+
+	for photos.Next() {
+		var idAssessment int
+		var photo entities.ReturnPhoto
+
+		if err := photos.Scan(&idAssessment, &photo.Url, &photo.Url_thumbnail); err != nil {
+			log.Println(err)
+			return nil, err
+		}
+		if i, ok := index[idAssessment]; ok {
+			damages[i].Photos = append(damages[i].Photos, photo)
+		}
+	}
+
+	return damages, nil
+}
+
+// createHandover records who handled the asset at the desk and its condition
+func createHandover(tx *sql.Tx, idRequest, idAdmin int, handoverType, notes string) error {
+	_, err := tx.Exec(`INSERT INTO request_handovers (id_request, id_user, type, condition_notes, created_at) VALUES (?, ?, ?, ?, now())`,
+		idRequest, idAdmin, handoverType, notes)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+// create a request for a kit, one request per unit of every component so each unit is handed over and
+// returned like a single request
+func (rr *requestRepo) CreateKitRequest(kitRequest entities.KitRequest, idStatus int) (int, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	items, err := kitItems(tx, kitRequest.Id_kit)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Exec(`INSERT INTO kit_requests (id_kit, id_user, return_date, description, created_at) VALUES (?, ?, ?, ?, now())`,
+		kitRequest.Id_kit, kitRequest.Id_user, kitRequest.Return_date, kitRequest.Description)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	for _, item := range items {
+		for i := 0; i < item.Quantity; i++ {
+			_, err := tx.Exec(`INSERT INTO requests (id_user, id_asset, id_status, request_date, return_date, description, id_kit_request, created_at, updated_at)
+							VALUES (?, ?, ?, now(), ?, ?, ?, now(), now())`,
+				kitRequest.Id_user, item.Id_asset, idStatus, kitRequest.Return_date, kitRequest.Description, id)
+			if err != nil {
+				log.Println(err)
+				return 0, err
+			}
+		}
+	}
+
+	return int(id), tx.Commit()
+}
+
+// kitItems locks the components of a kit, the kit can only be requested when every component is
+// in service with enough units available
+func kitItems(tx *sql.Tx, idKit int) ([]entities.KitItem, error) {
+	results, err := tx.Query(`select ki.id_asset, a.name, ki.quantity, a.avail_quantity, a.lifecycle_status
+							from kit_items ki
+							join kits k on k.id = ki.id_kit
+							join assets a on a.id = ki.id_asset
+							where ki.id_kit = ? and k.deleted_at is null and a.deleted_at is null
+							order by ki.id_asset asc for update`, idKit)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	var items []entities.KitItem
+	var unavailable []string
+	for results.Next() {
+		var item entities.KitItem
+		var lifecycleStatus string
+		if err := results.Scan(&item.Id_asset, &item.Asset_name, &item.Quantity, &item.Avail_quantity, &lifecycleStatus); err != nil {
+			results.Close()
+			log.Println(err)
+			return nil, err
+		}
+		if lifecycleStatus != "in_service" || item.Avail_quantity < item.Quantity {
+			unavailable = append(unavailable, item.Asset_name)
+		}
+		items = append(items, item)
+	}
+	results.Close()
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("kit not found")
+	}
+	if len(unavailable) > 0 {
+		return nil, fmt.Errorf("not enough units available for %s", strings.Join(unavailable, ", "))
+	}
+	return items, nil
+}
+
+// get kit request with the request of every component unit
+func (rr *requestRepo) GetKitRequest(id int) (entities.KitRequest, error) {
+	var kitRequest entities.KitRequest
+
+	row := rr.db.QueryRow(`select kr.id, kr.id_kit, k.name, kr.id_user, u.name, COALESCE(kr.return_date, ''), COALESCE(kr.description, '')
+							from kit_requests kr
+							join kits k on k.id = kr.id_kit
+							join users u on u.id = kr.id_user
+							where kr.id = ?`, id)
+	err := row.Scan(&kitRequest.Id, &kitRequest.Id_kit, &kitRequest.Kit_name, &kitRequest.Id_user, &kitRequest.User_name, &kitRequest.Return_date, &kitRequest.Description)
+	if err != nil {
+		return kitRequest, err
+	}
+
+	results, err := rr.db.Query(`select r.id, r.id_user, r.id_asset, r.id_status, r.quantity, r.returned_quantity, a.id_category, r.request_date, COALESCE(r.return_date, ''), COALESCE(r.description, ''), a.name as asset_name, c.description as category, a.avail_quantity, s.description as status
+								from requests r
+								join status_check s on s.id = r.id_status
+								join assets a on a.id = r.id_asset
+								join categories c on c.id = a.id_category
+								where r.id_kit_request = ? and r.deleted_at is null order by r.id asc`, id)
+	if err != nil {
+		log.Println(err)
+		return kitRequest, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var request entities.RequestResponse
+
+		err = results.Scan(&request.Id, &request.Id_user, &request.Id_asset, &request.Id_status, &request.Quantity, &request.Returned_quantity, &request.Id_category, &request.Request_date, &request.Return_date, &request.Description, &request.Asset_name, &request.Category, &request.Avail_quantity, &request.Status)
+		if err != nil {
+			log.Println(err)
+			return kitRequest, err
+		}
+		request.User_name = kitRequest.User_name
+
+		switch request.Id_status {
+		case 6, 7:
+			kitRequest.On_loan++
+		case 8:
+			kitRequest.Returned++
+		}
+		kitRequest.Requests = append(kitRequest.Requests, request)
+	}
+	return kitRequest, nil
+}
+
+// kitTransition is the status every open component of a kit has to be in before the kit is set to a status
+type kitTransition struct {
+	from int
+	err  error
+}
+
+var kitTransitions = map[int]kitTransition{
+	2: {1, fmt.Errorf("kit request is not waiting for admin")},
+	5: {1, fmt.Errorf("kit request is not waiting for admin")},
+	3: {2, fmt.Errorf("kit request is not waiting for manager")},
+	4: {2, fmt.Errorf("kit request is not waiting for manager")},
+	6: {3, fmt.Errorf("kit request is not approved by manager")},
+	7: {6, fmt.Errorf("kit request is not on loan")},
+}
+
+// update the status of every open component of a kit request, from the status the components have to be in (see
+// kitTransitions). The kit is handed over (status 6) only when every component is available, units kept for
+// reservations starting before the kit is back are not
+func (rr *requestRepo) UpdateKitRequestStatus(id, idStatus, idUser int) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	transition, ok := kitTransitions[idStatus]
+	if !ok {
+		return fmt.Errorf("invalid status")
+	}
+
+	results, err := tx.Query(`select id, id_asset, id_status, COALESCE(return_date, '') from requests
+							where id_kit_request = ? and id_status not in (4, 5, 8) and deleted_at is null for update`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	var requests []entities.Request
+	for results.Next() {
+		var request entities.Request
+		if err := results.Scan(&request.Id, &request.Id_asset, &request.Id_status, &request.Return_date); err != nil {
+			results.Close()
+			log.Println(err)
+			return err
+		}
+		requests = append(requests, request)
+	}
+	results.Close()
+
+	if len(requests) == 0 {
+		return fmt.Errorf("kit request not found or already closed")
+	}
+
+	for _, request := range requests {
+		if request.Id_status != transition.from {
+			return transition.err
+		}
+	}
+
+	if idStatus == 6 {
+		type loan struct {
+			idAsset    int
+			returnDate string
+		}
+		units := map[loan]int{}
+		for _, request := range requests {
+			units[loan{request.Id_asset, request.Return_date}]++
+		}
+
+		for loan, quantity := range units {
+			taken, err := takeUnits(tx, loan.idAsset, quantity, loan.returnDate)
+			if err != nil {
+				return err
+			}
+			if !taken {
+				return fmt.Errorf("asset %d is not available", loan.idAsset)
+			}
+		}
+	}
+
+	for _, request := range requests {
+		query := `UPDATE requests SET id_status = ?, request_date = now(), updated_at = now() WHERE id = ?`
+		if idStatus == 4 || idStatus == 5 {
+			query = `UPDATE requests SET id_status = ?, return_date = '0000-00-00', request_date = now(), updated_at = now() WHERE id = ?`
+		}
+		if _, err := tx.Exec(query, idStatus, request.Id); err != nil {
+			log.Println(err)
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO request_events (id_request, id_user, id_status, created_at) VALUES (?, ?, ?, now())`, request.Id, idUser, idStatus); err != nil {
+			log.Println(err)
+			return err
+		}
+		if idStatus == 6 {
+			if err := createHandover(tx, request.Id, idUser, "check_out", ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	// a rejected kit gives the units kept for its components to the waitlist
+	if idStatus == 4 || idStatus == 5 {
+		held := map[int]bool{}
+		for _, request := range requests {
+			if held[request.Id_asset] {
+				continue
+			}
+			held[request.Id_asset] = true
+			if err := waitlistRepo.HoldUnits(tx, request.Id_asset); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// return some or all components of a kit on loan, returns how many units are still on loan
+func (rr *requestRepo) ReturnKitItems(id int, assessments []entities.ReturnAssessment) (int, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, assessment := range assessments {
+		var idStatus int
+		row := tx.QueryRow(`select id_asset, id_user, id_status from requests where id = ? and id_kit_request = ? and deleted_at is null for update`,
+			assessment.Id_request, id)
+		if err := row.Scan(&assessment.Id_asset, &assessment.Id_user, &idStatus); err != nil {
+			if err == sql.ErrNoRows {
+				return 0, fmt.Errorf("request %d is not part of this kit request", assessment.Id_request)
+			}
+			log.Println(err)
+			return 0, err
+		}
+		if idStatus != 6 && idStatus != 7 {
+			return 0, fmt.Errorf("request %d is not on loan", assessment.Id_request)
+		}
+
+		if err := returnLoan(tx, assessment); err != nil {
+			return 0, err
+		}
+		// the desk records the hand back when an admin takes the kit
+		if assessment.Id_assessor != 0 {
+			if err := createHandover(tx, assessment.Id_request, assessment.Id_assessor, "check_in", assessment.Damage_notes); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	var onLoan int
+	row := tx.QueryRow(`select count(*) from requests where id_kit_request = ? and id_status in (6, 7) and deleted_at is null`, id)
+	if err := row.Scan(&onLoan); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return onLoan, tx.Commit()
+}
+
+// cancel a request of the employee while it still waits for approval or hand over (status 1, 2 or 3),
+// the units it kept from the waitlist are held for the next person
+func (rr *requestRepo) Cancel(idRequest, idUser int) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idAsset, idStatus, idKitRequest int
+	row := tx.QueryRow(`select id_asset, id_status, COALESCE(id_kit_request, 0) from requests where id = ? and id_user = ? and deleted_at is null for update`, idRequest, idUser)
+	if err := row.Scan(&idAsset, &idStatus, &idKitRequest); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("id not found")
+		}
+		log.Println(err)
+		return err
+	}
+	if idStatus < 1 || idStatus > 3 {
+		return fmt.Errorf("request can no longer be cancelled")
+	}
+	if idKitRequest != 0 {
+		return fmt.Errorf("request is part of a kit request")
+	}
+
+	_, err = tx.Exec(`UPDATE requests SET id_status = 9, return_date = '0000-00-00', updated_at = now() WHERE id = ?`, idRequest)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	if err := waitlistRepo.HoldUnits(tx, idAsset); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ask for a later return date on an active loan of the employee, one pending extension per loan
+func (rr *requestRepo) CreateExtension(extension entities.RequestExtension) (int, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var idStatus int
+	var later bool
+	row := tx.QueryRow(`select id_status, COALESCE(return_date, ''), ? > COALESCE(return_date, '') from requests where id = ? and id_user = ? and deleted_at is null for update`,
+		extension.Return_date, extension.Id_request, extension.Id_user)
+	if err := row.Scan(&idStatus, &extension.Current_return_date, &later); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("id not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+	if idStatus != 6 {
+		return 0, fmt.Errorf("request is not on loan")
+	}
+	if !later {
+		return 0, fmt.Errorf("return_date must be after the current return date")
+	}
+
+	var pending int
+	row = tx.QueryRow(`select count(*) from request_extensions where id_request = ? and status = 'pending'`, extension.Id_request)
+	if err := row.Scan(&pending); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if pending > 0 {
+		return 0, fmt.Errorf("an extension is already pending for this request")
+	}
+
+	res, err := tx.Exec(`INSERT INTO request_extensions (id_request, id_user, current_return_date, return_date, reason, status, created_at) VALUES (?, ?, nullif(?, ''), ?, ?, 'pending', now())`,
+		extension.Id_request, extension.Id_user, extension.Current_return_date, extension.Return_date, extension.Reason)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
+
+// get the extensions asked for a request, newest first
+func (rr *requestRepo) GetExtensions(idRequest int) ([]entities.RequestExtension, error) {
+	var extensions []entities.RequestExtension
+	results, err := rr.db.Query(`select id, id_request, id_user, COALESCE(current_return_date, ''), return_date, COALESCE(reason, ''), status, COALESCE(id_approver, 0), COALESCE(decided_at, ''), created_at
+								from request_extensions where id_request = ? order by id desc`, idRequest)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var extension entities.RequestExtension
+
+		err = results.Scan(&extension.Id, &extension.Id_request, &extension.Id_user, &extension.Current_return_date, &extension.Return_date, &extension.Reason, &extension.Status, &extension.Id_approver, &extension.Decided_at, &extension.Created_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		extensions = append(extensions, extension)
+	}
+	return extensions, nil
+}
+
+// approve or reject a pending extension, the return date of the loan only moves once approved
+func (rr *requestRepo) DecideExtension(id int, status string, idApprover int) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idRequest, idUser, idStatus int
+	var returnDate string
+	row := tx.QueryRow(`select e.id_request, e.id_user, e.return_date, r.id_status
+						from request_extensions e
+						join requests r on r.id = e.id_request
+						where e.id = ? and e.status = 'pending' for update`, id)
+	if err := row.Scan(&idRequest, &idUser, &returnDate, &idStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("extension not found or already decided")
+		}
+		log.Println(err)
+		return err
+	}
+
+	if status == "approved" {
+		if idStatus != 6 && idStatus != 7 {
+			return fmt.Errorf("request is not on loan")
+		}
+		_, err = tx.Exec(`UPDATE requests SET return_date = ?, updated_at = now() WHERE id = ?`, returnDate, idRequest)
+		if err != nil {
+			log.Println(err)
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE request_extensions SET status = ?, id_approver = ?, decided_at = now() WHERE id = ?`, status, idApprover, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	message := fmt.Sprintf("your loan extension to %s was %s", returnDate, status)
+	if err := notificationRepo.Notify(tx, idUser, "extension_"+status, message, idRequest); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ownership gets the owner of a resource with the division of the owner and of the user asking, the manager
+// of the division of the owner when the user asking approves for them, whether the resource was escalated to them
+// and the status of a request
+func (rr *requestRepo) ownership(query string, id, idUser int) (entities.Ownership, error) {
+	var ownership entities.Ownership
+	row := rr.db.QueryRow(`select o.id_user, u.divisi, COALESCE((select divisi from users where id = ?), ''),
+						COALESCE((select d.id_manager from approval_delegations d
+							join users m on m.id = d.id_manager
+							where d.id_delegate = ? and m.divisi = u.divisi and d.revoked_at is null and now() between d.start_at and d.end_at
+							order by d.id limit 1), 0),
+						COALESCE(o.id_escalated_to = ?, false), o.id_status
+						from (`+query+`) o
+						join users u on u.id = o.id_user`, idUser, idUser, idUser, id)
+	err := row.Scan(&ownership.Id_owner, &ownership.Owner_divisi, &ownership.User_divisi, &ownership.Id_delegator, &ownership.Escalated, &ownership.Id_status)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println(err)
+	}
+	return ownership, err
+}
+
+// get the owner of a request
+func (rr *requestRepo) GetOwnership(id, idUser int) (entities.Ownership, error) {
+	return rr.ownership(`select id_user, if(sla_breached_status = id_status, id_escalated_to, null) as id_escalated_to, id_status from requests where id = ? and deleted_at is null`, id, idUser)
+}
+
+// get the owner of a kit request
+func (rr *requestRepo) GetKitOwnership(id, idUser int) (entities.Ownership, error) {
+	return rr.ownership(`select id_user, null as id_escalated_to, 0 as id_status from kit_requests where id = ?`, id, idUser)
+}
+
+// get the owner of the loan of an extension
+func (rr *requestRepo) GetExtensionOwnership(id, idUser int) (entities.Ownership, error) {
+	return rr.ownership(`select r.id_user, null as id_escalated_to, 0 as id_status from request_extensions e join requests r on r.id = e.id_request where e.id = ?`, id, idUser)
+}
+
+// reject a request (status 4 or 5) with the reason, kept as a comment the employee can read. The units it kept
+// from the waitlist are held for the next person
+func (rr *requestRepo) Reject(idRequest, idStatus, idUser int, reason string) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idOwner, idAsset int
+	row := tx.QueryRow(`select id_user, id_asset from requests where id = ? and deleted_at is null for update`, idRequest)
+	if err := row.Scan(&idOwner, &idAsset); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("id not found")
+		}
+		log.Println(err)
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE requests SET id_status = ?, return_date = '0000-00-00', request_date = now(), updated_at = now() WHERE id = ?`, idStatus, idRequest)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+
+	if err := waitlistRepo.HoldUnits(tx, idAsset); err != nil {
+		return err
+	}
+
+	comment := entities.RequestComment{Id_request: idRequest, Id_user: idUser, Body: reason}
+	if _, err := commentRepo.Add(tx, comment); err != nil {
+		return err
+	}
+	if err := notificationRepo.Notify(tx, idOwner, "request_rejected", "your request was rejected: "+reason, idRequest); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// record a decision on a request, with the manager a delegate decided for
+func (rr *requestRepo) AddEvent(event entities.RequestEvent) error {
+	_, err := rr.db.Exec(`INSERT INTO request_events (id_request, id_user, id_on_behalf, id_status, created_at) VALUES (?, ?, nullif(?, 0), ?, now())`,
+		event.Id_request, event.Id_user, event.Id_on_behalf, event.Id_status)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+// get the decisions on a request, oldest first. A decision taken by the system (an expired request) has no user
+func (rr *requestRepo) GetEvents(idRequest int) ([]entities.RequestEvent, error) {
+	var events []entities.RequestEvent
+	results, err := rr.db.Query(`select e.id, e.id_request, COALESCE(e.id_user, 0), COALESCE(u.name, 'system'), COALESCE(e.id_on_behalf, 0), COALESCE(m.name, ''), e.id_status, s.description, e.created_at
+								from request_events e
+								left join users u on u.id = e.id_user
+								left join users m on m.id = e.id_on_behalf
+								join status_check s on s.id = e.id_status
+								where e.id_request = ? order by e.id asc`, idRequest)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var event entities.RequestEvent
+
+		err = results.Scan(&event.Id, &event.Id_request, &event.Id_user, &event.User_name, &event.Id_on_behalf, &event.On_behalf_name, &event.Id_status, &event.Status, &event.Created_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+	return events, nil
+}
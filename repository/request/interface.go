@@ -2,15 +2,39 @@ package request
 
 import (
 	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util/pagination"
 )
 
 type RequestRepo interface {
 	Create(entities.Request) error
-	GetAdmin(returnDate, requestDate, status, filterDate, category string, limit, offset int) ([]entities.RequestResponse, error)
-	GetManager(returnDate, requestDate, status, filterDate, category string, limit, offset int) ([]entities.RequestResponse, error)
+	GetAdmin(returnDate, requestDate, status, filterDate, category string, page pagination.Page) ([]entities.RequestResponse, error)
+	CountAdmin(status, filterDate, category string) (int, error)
+	GetManager(idManager int, returnDate, requestDate, status, filterDate, category string, page pagination.Page) ([]entities.RequestResponse, error)
+	CountManager(idManager int, status, filterDate, category string) (int, error)
 	GetById(int) (entities.RequestResponse, error)
 	Update(entities.Request, int) error
-	UpdateAvailQty(int, int) error
 	GetAvailQty(int) (entities.Request, error)
-	GetEmployee(idEmployee int, isHistory bool, limit, offset int) ([]entities.RequestResponse, error)
+	GetEmployee(idEmployee int, isHistory bool, page pagination.Page) ([]entities.RequestResponse, error)
+	CountEmployee(idEmployee int, isHistory bool) (int, error)
+	CheckOut(tag string, idUser, idAdmin int, notes string) (int, error)
+	HandOver(idRequest int, returnDate string) error
+	CheckIn(tag string, idUser int, notes string, assessment entities.ReturnAssessment) (int, error)
+	Return(idRequest int, assessment entities.ReturnAssessment) error
+	Transfer(idRequest, idRecipient, idAdmin int, notes string) (int, error)
+	GetDamageHistory(idUser int) ([]entities.ReturnAssessment, error)
+	GetAssetAvailQty(idAsset int, returnDate string) (int, error)
+	CreateKitRequest(kitRequest entities.KitRequest, idStatus int) (int, error)
+	GetKitRequest(int) (entities.KitRequest, error)
+	UpdateKitRequestStatus(id, idStatus, idUser int) error
+	ReturnKitItems(id int, assessments []entities.ReturnAssessment) (int, error)
+	Cancel(idRequest, idUser int) error
+	CreateExtension(entities.RequestExtension) (int, error)
+	GetExtensions(idRequest int) ([]entities.RequestExtension, error)
+	DecideExtension(id int, status string, idApprover int) error
+	Reject(idRequest, idStatus, idUser int, reason string) error
+	AddEvent(entities.RequestEvent) error
+	GetEvents(idRequest int) ([]entities.RequestEvent, error)
+	GetOwnership(id, idUser int) (entities.Ownership, error)
+	GetKitOwnership(id, idUser int) (entities.Ownership, error)
+	GetExtensionOwnership(id, idUser int) (entities.Ownership, error)
 }
@@ -0,0 +1,329 @@
+package reservation
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+)
+
+type reservationRepo struct {
+	db *sql.DB
+}
+
+func NewReservationRepo(db *sql.DB) *reservationRepo {
+	return &reservationRepo{db: db}
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// statusFrom is the status a reservation must have to be moved to another status
+var statusFrom = map[string]string{
+	"approved":  "'pending'",
+	"rejected":  "'pending'",
+	"cancelled": "'pending', 'approved'",
+}
+
+// getAvailability gets the units of the asset that can be taken, none when the asset is not in service, and
+// every reservation and loan overlapping the period. A loan past its return date keeps its units until returned
+func getAvailability(q queryer, idAsset int, from, to time.Time, lock bool) (int, []util.Booking, error) {
+	var capacity int
+	query := `select if(a.lifecycle_status = 'in_service', a.initial_quantity - (select count(*) from asset_maintenance m where m.id_asset = a.id and m.status = 'open'), 0)
+			from assets a where a.id = ? and a.deleted_at is null`
+	if lock {
+		query += " for update"
+	}
+	if err := q.QueryRow(query, idAsset).Scan(&capacity); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil, fmt.Errorf("asset not found")
+		}
+		log.Println(err)
+		return 0, nil, err
+	}
+
+	fromStr, toStr := from.Format(util.DateTimeLayout), to.Format(util.DateTimeLayout)
+	results, err := q.Query(`select start_at, end_at, quantity from reservations
+							where id_asset = ? and status in ('pending', 'approved') and start_at < ? and end_at > ?
+							union all
+							select request_date, greatest(COALESCE(return_date, now()), now()), quantity - returned_quantity from requests
+							where id_asset = ? and id_status in (6, 7) and deleted_at is null and request_date < ? and greatest(COALESCE(return_date, now()), now()) > ?`,
+		idAsset, toStr, fromStr, idAsset, toStr, fromStr)
+	if err != nil {
+		log.Println(err)
+		return 0, nil, err
+	}
+
+	defer results.Close()
+
+	var bookings []util.Booking
+	for results.Next() {
+		var start, end string
+		var booking util.Booking
+
+		if err := results.Scan(&start, &end, &booking.Quantity); err != nil {
+			log.Println(err)
+			return 0, nil, err
+		}
+		if booking.Start, err = time.ParseInLocation(util.DateTimeLayout, start, time.Local); err != nil {
+			log.Println(err)
+			return 0, nil, err
+		}
+		if booking.End, err = time.ParseInLocation(util.DateTimeLayout, end, time.Local); err != nil {
+			log.Println(err)
+			return 0, nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+	return capacity, bookings, nil
+}
+
+// create reservation, the units must be free during the whole period
+func (rr *reservationRepo) Create(reservation entities.Reservation) (int, error) {
+	start, err := time.ParseInLocation(util.DateTimeLayout, reservation.Start_at, time.Local)
+	if err != nil {
+		return 0, err
+	}
+	end, err := time.ParseInLocation(util.DateTimeLayout, reservation.End_at, time.Local)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// the asset row is locked so two reservations of the same asset are checked one after the other
+	capacity, bookings, err := getAvailability(tx, reservation.Id_asset, start, end, true)
+	if err != nil {
+		return 0, err
+	}
+	if util.PeakUsage(bookings, start, end)+reservation.Quantity > capacity {
+		return 0, fmt.Errorf("not enough units available for this period")
+	}
+
+	res, err := tx.Exec(`INSERT INTO reservations (id_asset, id_user, quantity, start_at, end_at, status, description, created_at, updated_at)
+						VALUES (?, ?, ?, ?, ?, 'pending', ?, now(), now())`,
+		reservation.Id_asset, reservation.Id_user, reservation.Quantity, reservation.Start_at, reservation.End_at, reservation.Description)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
+
+// get reservations, of a user when idUser is given
+func (rr *reservationRepo) Get(idUser int, status string) ([]entities.Reservation, error) {
+	var condition string
+	var bind []interface{}
+
+	if idUser != 0 {
+		condition += "and r.id_user = ? "
+		bind = append(bind, idUser)
+	}
+	if status != "" {
+		condition += "and r.status = ? "
+		bind = append(bind, status)
+	}
+
+	var reservations []entities.Reservation
+	results, err := rr.db.Query(`select r.id, r.id_asset, a.name, r.id_user, u.name, r.quantity, r.start_at, r.end_at, r.status, COALESCE(r.description, ''), COALESCE(r.id_request, 0), r.created_at
+								from reservations r
+								join assets a on a.id = r.id_asset
+								join users u on u.id = r.id_user
+								where true `+condition+`order by r.start_at asc`, bind...)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var reservation entities.Reservation
+
+		err = results.Scan(&reservation.Id, &reservation.Id_asset, &reservation.Asset_name, &reservation.Id_user, &reservation.User_name, &reservation.Quantity,
+			&reservation.Start_at, &reservation.End_at, &reservation.Status, &reservation.Description, &reservation.Id_request, &reservation.Created_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		reservations = append(reservations, reservation)
+	}
+	return reservations, nil
+}
+
+// get reservation by id
+func (rr *reservationRepo) GetById(id int) (entities.Reservation, error) {
+	var reservation entities.Reservation
+
+	row := rr.db.QueryRow(`select r.id, r.id_asset, a.name, r.id_user, u.name, r.quantity, r.start_at, r.end_at, r.status, COALESCE(r.description, ''), COALESCE(r.id_request, 0), r.created_at
+							from reservations r
+							join assets a on a.id = r.id_asset
+							join users u on u.id = r.id_user
+							where r.id = ?`, id)
+
+	err := row.Scan(&reservation.Id, &reservation.Id_asset, &reservation.Asset_name, &reservation.Id_user, &reservation.User_name, &reservation.Quantity,
+		&reservation.Start_at, &reservation.End_at, &reservation.Status, &reservation.Description, &reservation.Id_request, &reservation.Created_at)
+	if err != nil {
+		return reservation, err
+	}
+	return reservation, nil
+}
+
+// get the owner of a reservation and their division, with the division of the logged in user
+func (rr *reservationRepo) GetOwnership(id, idUser int) (entities.Ownership, error) {
+	var ownership entities.Ownership
+	row := rr.db.QueryRow(`select r.id_user, u.divisi, COALESCE((select divisi from users where id = ?), '')
+						from reservations r
+						join users u on u.id = r.id_user
+						where r.id = ?`, idUser, id)
+	err := row.Scan(&ownership.Id_owner, &ownership.Owner_divisi, &ownership.User_divisi)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println(err)
+	}
+	return ownership, err
+}
+
+// approve, reject or cancel a reservation
+func (rr *reservationRepo) UpdateStatus(id int, status string) error {
+	from, ok := statusFrom[status]
+	if !ok {
+		return fmt.Errorf("invalid status")
+	}
+
+	res, err := rr.db.Exec(`UPDATE reservations SET status = ?, updated_at = now() WHERE id = ? AND status in (`+from+`)`, status, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("reservation not found or cannot be %s", status)
+	}
+	return nil
+}
+
+// get units of the asset that can be taken and the bookings overlapping the period
+func (rr *reservationRepo) GetAvailability(idAsset int, from, to time.Time) (int, []util.Booking, error) {
+	return getAvailability(rr.db, idAsset, from, to, false)
+}
+
+// start the approved reservations whose start time has come, each one becomes a loan (status 6) of the asset.
+// A reservation still waiting for approval at its start time expires, an approved one at its end time when
+// its units never came back. Returns how many loans were started
+func (rr *reservationRepo) StartDue() (int, error) {
+	if _, err := rr.db.Exec(`UPDATE reservations SET status = 'expired', updated_at = now() WHERE status = 'pending' AND start_at <= now()`); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	results, err := rr.db.Query(`select id from reservations where status = 'approved' and start_at <= now() order by start_at asc`)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	var ids []int
+	for results.Next() {
+		var id int
+		if err := results.Scan(&id); err != nil {
+			results.Close()
+			log.Println(err)
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	results.Close()
+
+	started := 0
+	for _, id := range ids {
+		ok, err := rr.start(id)
+		if err != nil {
+			return started, err
+		}
+		if ok {
+			started++
+		}
+	}
+	return started, nil
+}
+
+// start turns one approved reservation into a loan, it is kept approved and retried on the next run while
+// the units are not back in stock, until its end time
+func (rr *reservationRepo) start(id int) (bool, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var reservation entities.Reservation
+	row := tx.QueryRow(`select id_asset, id_user, quantity, start_at, end_at, COALESCE(description, '') from reservations where id = ? and status = 'approved' for update`, id)
+	if err := row.Scan(&reservation.Id_asset, &reservation.Id_user, &reservation.Quantity, &reservation.Start_at, &reservation.End_at, &reservation.Description); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		log.Println(err)
+		return false, err
+	}
+
+	res, err := tx.Exec(`UPDATE assets SET avail_quantity = avail_quantity - ?, updated_at = now()
+						WHERE id = ? AND avail_quantity >= ? AND lifecycle_status = 'in_service' AND deleted_at is null`,
+		reservation.Quantity, reservation.Id_asset, reservation.Quantity)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	if row, _ := res.RowsAffected(); row == 0 {
+		// the units never came back before the end of the reservation, it expires instead of being retried
+		expired, err := tx.Exec(`UPDATE reservations SET status = 'expired', updated_at = now() WHERE id = ? AND end_at <= now()`, id)
+		if err != nil {
+			log.Println(err)
+			return false, err
+		}
+		if row, _ := expired.RowsAffected(); row > 0 {
+			log.Println("reservation", id, "expired without units of asset", reservation.Id_asset)
+			return false, tx.Commit()
+		}
+		log.Println("reservation", id, "is waiting for units of asset", reservation.Id_asset)
+		return false, nil
+	}
+
+	res, err = tx.Exec(`INSERT INTO requests (id_user, id_asset, id_status, quantity, request_date, return_date, description, created_at, updated_at)
+						VALUES (?, ?, 6, ?, now(), ?, ?, now(), now())`,
+		reservation.Id_user, reservation.Id_asset, reservation.Quantity, reservation.End_at, reservation.Description)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	idRequest, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+
+	if _, err := tx.Exec(`UPDATE reservations SET status = 'active', id_request = ?, updated_at = now() WHERE id = ?`, idRequest, id); err != nil {
+		log.Println(err)
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,18 @@
+package reservation
+
+import (
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+)
+
+type ReservationRepo interface {
+	Create(entities.Reservation) (int, error)
+	Get(idUser int, status string) ([]entities.Reservation, error)
+	GetById(int) (entities.Reservation, error)
+	GetOwnership(id, idUser int) (entities.Ownership, error)
+	UpdateStatus(id int, status string) error
+	GetAvailability(idAsset int, from, to time.Time) (int, []util.Booking, error)
+	StartDue() (int, error)
+}
@@ -0,0 +1,218 @@
+package sla
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	notificationRepo "sirclo/project/capstone/repository/notification"
+	waitlistRepo "sirclo/project/capstone/repository/waitlist"
+)
+
+type slaRepo struct {
+	db *sql.DB
+}
+
+func NewSlaRepo(db *sql.DB) *slaRepo {
+	return &slaRepo{db: db}
+}
+
+// expiredStatus is the rejection status of a request expired while waiting for the admin (1) or the manager (2)
+var expiredStatus = map[int]int{1: 5, 2: 4}
+
+// get the sla of every pending status
+func (sr *slaRepo) Get() ([]entities.RequestSla, error) {
+	var slas []entities.RequestSla
+	results, err := sr.db.Query(`select l.id_status, s.description, l.business_days, l.max_age_days, COALESCE(l.id_fallback_user, 0), COALESCE(u.name, ''), COALESCE(l.updated_at, '')
+								from request_slas l
+								join status_check s on s.id = l.id_status
+								left join users u on u.id = l.id_fallback_user
+								order by l.id_status asc`)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		var sla entities.RequestSla
+
+		err = results.Scan(&sla.Id_status, &sla.Status, &sla.Business_days, &sla.Max_age_days, &sla.Id_fallback_user, &sla.Fallback_name, &sla.Updated_at)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		slas = append(slas, sla)
+	}
+	return slas, nil
+}
+
+// update the sla of a pending status, the fallback approver of a new request (status 1) has to be an admin
+func (sr *slaRepo) Update(sla entities.RequestSla) error {
+	if sla.Id_fallback_user != 0 {
+		var idRole int
+		row := sr.db.QueryRow(`select COALESCE(id_role, 0) from users where id = ? and deleted_at is null`, sla.Id_fallback_user)
+		if err := row.Scan(&idRole); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("fallback approver not found")
+			}
+			log.Println(err)
+			return err
+		}
+		if sla.Id_status == 1 && idRole != 1 {
+			return fmt.Errorf("fallback approver of status 1 must be an admin")
+		}
+	}
+
+	_, err := sr.db.Exec(`INSERT INTO request_slas (id_status, business_days, max_age_days, id_fallback_user, updated_at) VALUES (?, ?, ?, nullif(?, 0), now())
+						ON DUPLICATE KEY UPDATE business_days = values(business_days), max_age_days = values(max_age_days), id_fallback_user = values(id_fallback_user), updated_at = now()`,
+		sla.Id_status, sla.Business_days, sla.Max_age_days, sla.Id_fallback_user)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
+// overdue is a pending request past the business days of the sla of its status
+type overdue struct {
+	id         int
+	idStatus   int
+	idFallback int
+	assetName  string
+}
+
+// Escalate flags the pending requests past the business days of their sla and hands them to the fallback approver.
+// The time in status starts at the request date, moved on every change of status
+func (sr *slaRepo) Escalate(now time.Time) (int, error) {
+	tx, err := sr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`select r.id, r.id_status, r.request_date, l.business_days, COALESCE(l.id_fallback_user, 0), a.name
+						from requests r
+						join request_slas l on l.id_status = r.id_status
+						join assets a on a.id = r.id_asset
+						where r.id_status in (1, 2) and r.deleted_at is null and l.business_days > 0
+						and (r.sla_breached_status is null or r.sla_breached_status != r.id_status)
+						for update`)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	var requests []overdue
+	for rows.Next() {
+		var request overdue
+		var requestDate string
+		var businessDays int
+
+		if err := rows.Scan(&request.id, &request.idStatus, &requestDate, &businessDays, &request.idFallback, &request.assetName); err != nil {
+			rows.Close()
+			log.Println(err)
+			return 0, err
+		}
+
+		since, err := time.ParseInLocation(util.DateTimeLayout, requestDate, time.Local)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if now.After(util.AddBusinessDays(since, businessDays)) {
+			requests = append(requests, request)
+		}
+	}
+	rows.Close()
+
+	for _, request := range requests {
+		_, err := tx.Exec(`UPDATE requests SET sla_breached_status = id_status, id_escalated_to = nullif(?, 0), escalated_at = now() WHERE id = ?`, request.idFallback, request.id)
+		if err != nil {
+			log.Println(err)
+			return 0, err
+		}
+
+		if request.idFallback != 0 {
+			message := fmt.Sprintf("request %d for %s is overdue and escalated to you", request.id, request.assetName)
+			if err := notificationRepo.Notify(tx, request.idFallback, "request_escalated", message, request.id); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return len(requests), tx.Commit()
+}
+
+// stale is a pending request older than the max age of the sla of its status
+type stale struct {
+	id         int
+	idUser     int
+	idAsset    int
+	idStatus   int
+	maxAgeDays int
+}
+
+// ExpireStale rejects the pending requests older than the max age of their sla, kit components are left to their
+// kit. The units kept for them are held for the waitlist and the rejection is recorded in their history
+func (sr *slaRepo) ExpireStale() (int, error) {
+	tx, err := sr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`select r.id, r.id_user, r.id_asset, r.id_status, l.max_age_days
+						from requests r
+						join request_slas l on l.id_status = r.id_status
+						where r.id_status in (1, 2) and r.deleted_at is null and r.id_kit_request is null
+						and l.max_age_days > 0 and r.created_at < now() - interval l.max_age_days day
+						for update`)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	var requests []stale
+	for rows.Next() {
+		var request stale
+		if err := rows.Scan(&request.id, &request.idUser, &request.idAsset, &request.idStatus, &request.maxAgeDays); err != nil {
+			rows.Close()
+			log.Println(err)
+			return 0, err
+		}
+		requests = append(requests, request)
+	}
+	rows.Close()
+
+	for _, request := range requests {
+		_, err := tx.Exec(`UPDATE requests SET id_status = ?, return_date = '0000-00-00', updated_at = now() WHERE id = ?`, expiredStatus[request.idStatus], request.id)
+		if err != nil {
+			log.Println(err)
+			return 0, err
+		}
+		// no user rejected it, the event is recorded without one
+		_, err = tx.Exec(`INSERT INTO request_events (id_request, id_user, id_status, created_at) VALUES (?, NULL, ?, now())`, request.id, expiredStatus[request.idStatus])
+		if err != nil {
+			log.Println(err)
+			return 0, err
+		}
+
+		message := fmt.Sprintf("your request was rejected automatically after %d days without a decision", request.maxAgeDays)
+		if err := notificationRepo.Notify(tx, request.idUser, "request_expired", message, request.id); err != nil {
+			return 0, err
+		}
+		if err := waitlistRepo.HoldUnits(tx, request.idAsset); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(requests), tx.Commit()
+}
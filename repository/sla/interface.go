@@ -0,0 +1,14 @@
+package sla
+
+import (
+	"time"
+
+	"sirclo/project/capstone/entities"
+)
+
+type SlaRepo interface {
+	Get() ([]entities.RequestSla, error)
+	Update(entities.RequestSla) error
+	Escalate(now time.Time) (int, error)
+	ExpireStale() (int, error)
+}
@@ -6,6 +6,9 @@ import (
 	"log"
 
 	"sirclo/project/capstone/entities"
+	"sirclo/project/capstone/util"
+
+	waitlistRepo "sirclo/project/capstone/repository/waitlist"
 )
 
 type userRepo struct {
@@ -60,6 +63,7 @@ func (ur *userRepo) Get() ([]entities.User, error) {
 			return nil, err
 		}
 
+		user.Badge = util.UserBadge(user.Id)
 		users = append(users, user)
 	}
 	return users, nil
@@ -78,6 +82,7 @@ func (ur *userRepo) GetById(id int) (entities.User, error) {
 	if err != nil {
 		return user, err
 	}
+	user.Badge = util.UserBadge(user.Id)
 
 	return user, nil
 }
@@ -103,3 +108,82 @@ func (ur *userRepo) GetEmail() ([]entities.User, error) {
 	}
 	return users, nil
 }
+
+// deactivate a user once they hold no asset anymore, they leave every waitlist, their reservations and pending
+// requests are cancelled and the units kept for them are held for the waitlist. The offboarding in progress is completed
+func (ur *userRepo) Deactivate(id int) error {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var user int
+	row := tx.QueryRow(`select id from users where id = ? and deleted_at is null for update`, id)
+	if err := row.Scan(&user); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user not found")
+		}
+		log.Println(err)
+		return err
+	}
+
+	var holdings int
+	row = tx.QueryRow(`select count(*) from requests where id_user = ? and id_status in (6, 7) and deleted_at is null`, id)
+	if err := row.Scan(&holdings); err != nil {
+		log.Println(err)
+		return err
+	}
+	if holdings > 0 {
+		return fmt.Errorf("user still holds %d assets, return or write them off first", holdings)
+	}
+
+	// the user leaves every waitlist and their upcoming reservations will not start
+	if err := waitlistRepo.CancelEntries(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE reservations SET status = 'cancelled', updated_at = now() WHERE id_user = ? and status in ('pending', 'approved')`, id); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	rows, err := tx.Query(`select distinct id_asset from requests where id_user = ? and id_status in (1, 2, 3) and deleted_at is null for update`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	var assets []int
+	for rows.Next() {
+		var idAsset int
+		if err := rows.Scan(&idAsset); err != nil {
+			rows.Close()
+			log.Println(err)
+			return err
+		}
+		assets = append(assets, idAsset)
+	}
+	rows.Close()
+
+	_, err = tx.Exec(`UPDATE requests SET id_status = 9, return_date = '0000-00-00', updated_at = now() WHERE id_user = ? and id_status in (1, 2, 3) and deleted_at is null`, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	for _, idAsset := range assets {
+		if err := waitlistRepo.HoldUnits(tx, idAsset); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE offboardings SET completed_at = now() WHERE id_user = ? and completed_at is null`, id); err != nil {
+		log.Println(err)
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE users SET deleted_at = now(), updated_at = now() WHERE id = ?`, id); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -9,4 +9,5 @@ type UserRepo interface {
 	Get() ([]entities.User, error)
 	GetById(int) (entities.User, error)
 	GetEmail() ([]entities.User, error)
+	Deactivate(id int) error
 }
@@ -0,0 +1,365 @@
+package waitlist
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"sirclo/project/capstone/entities"
+	notificationRepo "sirclo/project/capstone/repository/notification"
+)
+
+// hours a unit is held for the next person of the waitlist before it goes to the person after
+const holdHours = 48
+
+type waitlistRepo struct {
+	db *sql.DB
+}
+
+func NewWaitlistRepo(db *sql.DB) *waitlistRepo {
+	return &waitlistRepo{db: db}
+}
+
+// HoldUnits takes the free units of the asset out of the stock for the next people of the waitlist, by
+// priority then first come first served, and notifies them. Units already asked by open requests are not free.
+// It is called in the transaction that puts units back in stock
+func HoldUnits(tx *sql.Tx, idAsset int) error {
+	var freeQuantity int
+	var assetName string
+	row := tx.QueryRow(`select a.avail_quantity - (select COALESCE(sum(r.quantity), 0) from requests r where r.id_asset = a.id and r.id_status in (1, 2, 3) and r.deleted_at is null), a.name
+						from assets a where a.id = ? for update`, idAsset)
+	if err := row.Scan(&freeQuantity, &assetName); err != nil {
+		log.Println(err)
+		return err
+	}
+
+	for ; freeQuantity > 0; freeQuantity-- {
+		var id, idUser int
+		row := tx.QueryRow(`select id, id_user from waitlist_entries where id_asset = ? and status = 'waiting'
+							order by priority desc, id asc limit 1 for update`, idAsset)
+		if err := row.Scan(&id, &idUser); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			log.Println(err)
+			return err
+		}
+
+		if _, err := tx.Exec(`UPDATE waitlist_entries SET status = 'held', held_until = now() + interval ? hour, updated_at = now() WHERE id = ?`, holdHours, id); err != nil {
+			log.Println(err)
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE assets SET avail_quantity = avail_quantity - 1, updated_at = now() WHERE id = ?`, idAsset); err != nil {
+			log.Println(err)
+			return err
+		}
+
+		message := fmt.Sprintf("%s is available, a unit is held for you for %d hours", assetName, holdHours)
+		if err := notificationRepo.Notify(tx, idUser, "waitlist_hold", message, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// release puts the unit held for an entry back in stock and holds it for the next person
+func release(tx *sql.Tx, idAsset int) error {
+	if _, err := tx.Exec(`UPDATE assets SET avail_quantity = least(avail_quantity + 1, initial_quantity), updated_at = now() WHERE id = ?`, idAsset); err != nil {
+		log.Println(err)
+		return err
+	}
+	return HoldUnits(tx, idAsset)
+}
+
+// CancelEntries cancels every open entry of a user, the unit held for them goes to the next person. It is called in
+// the transaction that deactivates the user
+func CancelEntries(tx *sql.Tx, idUser int) error {
+	rows, err := tx.Query(`select id_asset from waitlist_entries where id_user = ? and status = 'held' for update`, idUser)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	var held []int
+	for rows.Next() {
+		var idAsset int
+		if err := rows.Scan(&idAsset); err != nil {
+			rows.Close()
+			log.Println(err)
+			return err
+		}
+		held = append(held, idAsset)
+	}
+	rows.Close()
+
+	if _, err := tx.Exec(`UPDATE waitlist_entries SET status = 'cancelled', updated_at = now() WHERE id_user = ? and status in ('waiting', 'held')`, idUser); err != nil {
+		log.Println(err)
+		return err
+	}
+	for _, idAsset := range held {
+		if err := release(tx, idAsset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// join the waitlist of an asset, only when no unit is available
+func (wr *waitlistRepo) Join(entry entities.WaitlistEntry) (int, error) {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var availQuantity int
+	var lifecycleStatus string
+	row := tx.QueryRow(`select avail_quantity, lifecycle_status from assets where id = ? and deleted_at is null for update`, entry.Id_asset)
+	if err := row.Scan(&availQuantity, &lifecycleStatus); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("asset not found")
+		}
+		log.Println(err)
+		return 0, err
+	}
+	if lifecycleStatus != "in_service" {
+		return 0, fmt.Errorf("asset is not in service")
+	}
+	if availQuantity > 0 {
+		return 0, fmt.Errorf("asset is available, request it instead")
+	}
+
+	var total int
+	row = tx.QueryRow(`select count(*) from waitlist_entries where id_asset = ? and id_user = ? and status in ('waiting', 'held')`, entry.Id_asset, entry.Id_user)
+	if err := row.Scan(&total); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	if total > 0 {
+		return 0, fmt.Errorf("already on the waitlist of this asset")
+	}
+
+	res, err := tx.Exec(`INSERT INTO waitlist_entries (id_asset, id_user, priority, status, created_at, updated_at) VALUES (?, ?, 0, 'waiting', now(), now())`,
+		entry.Id_asset, entry.Id_user)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
+}
+
+// entryQuery selects waitlist entries, the position is the place in the queue of a waiting entry
+const entryQuery = `select w.id, w.id_asset, a.name, w.id_user, u.name, w.priority, w.status, COALESCE(w.held_until, ''), COALESCE(w.id_request, 0), w.created_at,
+					if(w.status = 'waiting', (select count(*) from waitlist_entries w2 where w2.id_asset = w.id_asset and w2.status = 'waiting'
+						and (w2.priority > w.priority or (w2.priority = w.priority and w2.id < w.id))) + 1, 0) as position
+					from waitlist_entries w
+					join assets a on a.id = w.id_asset
+					join users u on u.id = w.id_user `
+
+func scanEntry(scanner interface{ Scan(...interface{}) error }) (entities.WaitlistEntry, error) {
+	var entry entities.WaitlistEntry
+	err := scanner.Scan(&entry.Id, &entry.Id_asset, &entry.Asset_name, &entry.Id_user, &entry.User_name, &entry.Priority, &entry.Status,
+		&entry.Held_until, &entry.Id_request, &entry.Created_at, &entry.Position)
+	return entry, err
+}
+
+// get the open entries of the waitlist, of an asset and/or of a user
+func (wr *waitlistRepo) Get(idAsset, idUser int) ([]entities.WaitlistEntry, error) {
+	var condition string
+	var bind []interface{}
+
+	if idAsset != 0 {
+		condition += "and w.id_asset = ? "
+		bind = append(bind, idAsset)
+	}
+	if idUser != 0 {
+		condition += "and w.id_user = ? "
+		bind = append(bind, idUser)
+	}
+
+	var entries []entities.WaitlistEntry
+	results, err := wr.db.Query(entryQuery+`where w.status in ('waiting', 'held') `+condition+`order by w.id_asset asc, w.status = 'waiting' asc, w.priority desc, w.id asc`, bind...)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	defer results.Close()
+
+	for results.Next() {
+		entry, err := scanEntry(results)
+		if err != nil {
+			log.Println(err)
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// get waitlist entry by id
+func (wr *waitlistRepo) GetById(id int) (entities.WaitlistEntry, error) {
+	return scanEntry(wr.db.QueryRow(entryQuery+`where w.id = ?`, id))
+}
+
+// set the priority of a waiting entry, a higher priority is served first
+func (wr *waitlistRepo) SetPriority(id, priority int) error {
+	res, err := wr.db.Exec(`UPDATE waitlist_entries SET priority = ?, updated_at = now() WHERE id = ? AND status = 'waiting'`, priority, id)
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	row, _ := res.RowsAffected()
+	if row == 0 {
+		return fmt.Errorf("entry not found or not waiting")
+	}
+	return nil
+}
+
+// leave the waitlist, a unit held for the entry goes to the next person
+func (wr *waitlistRepo) Cancel(id int) error {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idAsset int
+	var status string
+	row := tx.QueryRow(`select id_asset, status from waitlist_entries where id = ? and status in ('waiting', 'held') for update`, id)
+	if err := row.Scan(&idAsset, &status); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("entry not found or already closed")
+		}
+		log.Println(err)
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE waitlist_entries SET status = 'cancelled', updated_at = now() WHERE id = ?`, id); err != nil {
+		log.Println(err)
+		return err
+	}
+	if status == "held" {
+		if err := release(tx, idAsset); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// claim the unit held for an entry, it goes back in stock for a new request of the user which then follows the
+// usual approval flow
+func (wr *waitlistRepo) Claim(id int, request entities.Request) (int, error) {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var idAsset, idUser int
+	row := tx.QueryRow(`select id_asset, id_user from waitlist_entries where id = ? and status = 'held' and held_until > now() for update`, id)
+	if err := row.Scan(&idAsset, &idUser); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("no unit held for this entry")
+		}
+		log.Println(err)
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`UPDATE assets SET avail_quantity = least(avail_quantity + 1, initial_quantity), updated_at = now() WHERE id = ?`, idAsset); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	res, err := tx.Exec(`INSERT INTO requests (id_user, id_asset, id_status, quantity, request_date, return_date, description, created_at, updated_at)
+						VALUES (?, ?, ?, 1, now(), ?, ?, now(), now())`,
+		idUser, idAsset, request.Id_status, request.Return_date, request.Description)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+	idRequest, err := res.LastInsertId()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`UPDATE waitlist_entries SET status = 'fulfilled', id_request = ?, updated_at = now() WHERE id = ?`, idRequest, id); err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	return int(idRequest), tx.Commit()
+}
+
+// expire the holds not claimed in time, each unit goes to the next person. Returns how many holds expired
+func (wr *waitlistRepo) ExpireHolds() (int, error) {
+	results, err := wr.db.Query(`select id from waitlist_entries where status = 'held' and held_until <= now()`)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	var ids []int
+	for results.Next() {
+		var id int
+		if err := results.Scan(&id); err != nil {
+			results.Close()
+			log.Println(err)
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	results.Close()
+
+	expired := 0
+	for _, id := range ids {
+		if err := wr.expire(id); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (wr *waitlistRepo) expire(id int) error {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var idAsset, idUser int
+	row := tx.QueryRow(`select id_asset, id_user from waitlist_entries where id = ? and status = 'held' for update`, id)
+	if err := row.Scan(&idAsset, &idUser); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		log.Println(err)
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE waitlist_entries SET status = 'expired', updated_at = now() WHERE id = ?`, id); err != nil {
+		log.Println(err)
+		return err
+	}
+	if err := notificationRepo.Notify(tx, idUser, "waitlist_expired", "the unit held for you was not claimed in time", id); err != nil {
+		return err
+	}
+	if err := release(tx, idAsset); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
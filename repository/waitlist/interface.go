@@ -0,0 +1,13 @@
+package waitlist
+
+import "sirclo/project/capstone/entities"
+
+type WaitlistRepo interface {
+	Join(entities.WaitlistEntry) (int, error)
+	Get(idAsset, idUser int) ([]entities.WaitlistEntry, error)
+	GetById(int) (entities.WaitlistEntry, error)
+	SetPriority(id, priority int) error
+	Cancel(int) error
+	Claim(id int, request entities.Request) (int, error)
+	ExpireHolds() (int, error)
+}
@@ -0,0 +1,49 @@
+package util
+
+import "time"
+
+// DateTimeLayout is the layout of the datetime columns read as text from the database
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+// Booking is a period where units of an asset are taken, by a reservation or by a loan
+type Booking struct {
+	Start    time.Time
+	End      time.Time
+	Quantity int
+}
+
+// PeakUsage is the most units taken at the same time between from and to. The usage only grows when a
+// booking starts, so it is enough to look at from and at every start inside the period
+func PeakUsage(bookings []Booking, from, to time.Time) int {
+	points := []time.Time{from}
+	for _, booking := range bookings {
+		if booking.Start.After(from) && booking.Start.Before(to) {
+			points = append(points, booking.Start)
+		}
+	}
+
+	peak := 0
+	for _, point := range points {
+		usage := 0
+		for _, booking := range bookings {
+			if !booking.Start.After(point) && booking.End.After(point) {
+				usage += booking.Quantity
+			}
+		}
+		if usage > peak {
+			peak = usage
+		}
+	}
+	return peak
+}
+
+// AddBusinessDays moves t forward by days, counting only the days that fall from Monday to Friday
+func AddBusinessDays(t time.Time, days int) time.Time {
+	for days > 0 {
+		t = t.AddDate(0, 0, 1)
+		if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+			days--
+		}
+	}
+	return t
+}
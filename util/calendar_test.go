@@ -0,0 +1,69 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 1. test the most units taken at the same time, a booking ends right when the next one can start
+func TestPeakUsage(t *testing.T) {
+	from, to := date(2021, 6, 1), date(2021, 6, 11)
+	booking := func(start, end, quantity int) Booking {
+		return Booking{Start: date(2021, 6, start), End: date(2021, 6, end), Quantity: quantity}
+	}
+
+	testCases := []struct {
+		name     string
+		bookings []Booking
+		expected int
+	}{
+		{"no bookings", nil, 0},
+		{"single booking", []Booking{booking(3, 5, 2)}, 2},
+		{"overlapping", []Booking{booking(1, 5, 2), booking(3, 8, 3)}, 5},
+		{"back to back", []Booking{booking(1, 3, 2), booking(3, 5, 3)}, 3},
+		{"apart", []Booking{booking(1, 3, 4), booking(6, 9, 1)}, 4},
+		{"nested", []Booking{booking(1, 10, 1), booking(2, 4, 2), booking(3, 5, 4)}, 7},
+		{"started before the period", []Booking{booking(-3, 2, 2), booking(1, 6, 1)}, 3},
+		{"started before and overlapping a later one", []Booking{booking(-5, 4, 2), booking(3, 6, 1)}, 3},
+		{"ended before the period", []Booking{booking(-5, 1, 6), booking(2, 4, 1)}, 1},
+		{"starts when the period ends", []Booking{booking(11, 14, 6), booking(2, 4, 1)}, 1},
+		{"covers the whole period", []Booking{booking(-10, 20, 3)}, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, PeakUsage(tc.bookings, from, to))
+		})
+	}
+}
+
+// 2. test moving forward by business days over weekends and month and year ends, the time of day is kept
+func TestAddBusinessDays(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     time.Time
+		days     int
+		expected time.Time
+	}{
+		{"no days", date(2021, 6, 5), 0, date(2021, 6, 5)},
+		{"negative days", date(2021, 6, 7), -2, date(2021, 6, 7)},
+		{"monday to tuesday", date(2021, 6, 7), 1, date(2021, 6, 8)},
+		{"friday to monday", date(2021, 6, 4), 1, date(2021, 6, 7)},
+		{"saturday to monday", date(2021, 6, 5), 1, date(2021, 6, 7)},
+		{"sunday to monday", date(2021, 6, 6), 1, date(2021, 6, 7)},
+		{"a working week", date(2021, 6, 4), 5, date(2021, 6, 11)},
+		{"two working weeks", date(2021, 6, 2), 10, date(2021, 6, 16)},
+		{"end of month on a weekend", date(2021, 4, 30), 1, date(2021, 5, 3)},
+		{"end of year", date(2021, 12, 30), 2, date(2022, 1, 3)},
+		{"leap day", date(2024, 2, 23), 4, date(2024, 2, 29)},
+		{"time of day", time.Date(2021, 6, 4, 17, 30, 0, 0, time.UTC), 1, time.Date(2021, 6, 7, 17, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, AddBusinessDays(tc.from, tc.days))
+		})
+	}
+}
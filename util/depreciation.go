@@ -0,0 +1,76 @@
+package util
+
+import (
+	"math"
+	"time"
+)
+
+// DepreciationPolicy is configured per category
+type DepreciationPolicy struct {
+	Method           string
+	UsefulLifeMonths int
+	// SalvageRate is the value left at the end of the useful life, as a fraction of the cost
+	SalvageRate float64
+	// DecliningFactor is used by declining_balance, 2 for double declining balance
+	DecliningFactor float64
+}
+
+// MonthsElapsed counts the full months between two dates
+func MonthsElapsed(from, to time.Time) int {
+	if !to.After(from) {
+		return 0
+	}
+
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+// BookValue computes the value of an asset bought at cost on purchaseDate, as of the given date
+func BookValue(cost float64, purchaseDate, asOf time.Time, policy DepreciationPolicy) float64 {
+	if cost <= 0 {
+		return 0
+	}
+	if policy.UsefulLifeMonths <= 0 {
+		// no depreciation configured for the category
+		return cost
+	}
+
+	salvage := cost * policy.SalvageRate
+	months := MonthsElapsed(purchaseDate, asOf)
+	if months >= policy.UsefulLifeMonths {
+		return RoundMoney(salvage)
+	}
+
+	var value float64
+	switch policy.Method {
+	case "declining_balance":
+		factor := policy.DecliningFactor
+		if factor <= 0 {
+			factor = 2
+		}
+		rate := factor / float64(policy.UsefulLifeMonths)
+		if rate > 1 {
+			rate = 1
+		}
+		value = cost * math.Pow(1-rate, float64(months))
+	default:
+		// straight_line
+		value = cost - (cost-salvage)*float64(months)/float64(policy.UsefulLifeMonths)
+	}
+
+	if value < salvage {
+		value = salvage
+	}
+	return RoundMoney(value)
+}
+
+// RoundMoney rounds an amount to 2 decimals
+func RoundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
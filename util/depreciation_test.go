@@ -0,0 +1,86 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// 1. test counting the full months between two dates
+func TestMonthsElapsed(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		expected int
+	}{
+		{"same day", date(2021, 1, 15), date(2021, 1, 15), 0},
+		{"to before from", date(2021, 3, 15), date(2021, 1, 15), 0},
+		{"full months", date(2021, 1, 15), date(2021, 3, 15), 2},
+		{"a day short of a month", date(2021, 1, 15), date(2021, 3, 14), 1},
+		{"end of a longer month", date(2021, 1, 31), date(2021, 2, 28), 0},
+		{"across a year", date(2020, 11, 10), date(2021, 2, 10), 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MonthsElapsed(tc.from, tc.to))
+		})
+	}
+}
+
+// 2. test the book value by straight line and by declining balance, never below the salvage value
+func TestBookValue(t *testing.T) {
+	purchased := date(2021, 1, 1)
+	straightLine := DepreciationPolicy{Method: "straight_line", UsefulLifeMonths: 12, SalvageRate: 0.1}
+	declining := DepreciationPolicy{Method: "declining_balance", UsefulLifeMonths: 10, DecliningFactor: 2}
+
+	testCases := []struct {
+		name     string
+		cost     float64
+		asOf     time.Time
+		policy   DepreciationPolicy
+		expected float64
+	}{
+		{"no cost", 0, date(2021, 7, 1), straightLine, 0},
+		{"no useful life", 1200, date(2021, 7, 1), DepreciationPolicy{}, 1200},
+		{"straight line when bought", 1200, purchased, straightLine, 1200},
+		{"straight line half way", 1200, date(2021, 7, 1), straightLine, 660},
+		{"straight line end of life", 1200, date(2022, 1, 1), straightLine, 120},
+		{"straight line after its life", 1200, date(2025, 1, 1), straightLine, 120},
+		{"unknown method is straight line", 1200, date(2021, 7, 1), DepreciationPolicy{Method: "other", UsefulLifeMonths: 12, SalvageRate: 0.1}, 660},
+		{"declining one month", 1000, date(2021, 2, 1), declining, 800},
+		{"declining two months", 1000, date(2021, 3, 1), declining, 640},
+		{"declining rounded", 1000, date(2021, 6, 1), declining, 327.68},
+		{"declining default factor", 1000, date(2021, 2, 1), DepreciationPolicy{Method: "declining_balance", UsefulLifeMonths: 10}, 800},
+		{"declining down to salvage", 1000, date(2021, 6, 1), DepreciationPolicy{Method: "declining_balance", UsefulLifeMonths: 10, DecliningFactor: 2, SalvageRate: 0.4}, 400},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, BookValue(tc.cost, purchased, tc.asOf, tc.policy))
+		})
+	}
+}
+
+// 3. test rounding an amount to 2 decimals
+func TestRoundMoney(t *testing.T) {
+	testCases := []struct {
+		value    float64
+		expected float64
+	}{
+		{0, 0},
+		{12.344, 12.34},
+		{12.345, 12.35},
+		{-3.456, -3.46},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, RoundMoney(tc.value))
+	}
+}
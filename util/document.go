@@ -0,0 +1,43 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const maxDocumentBytes = 10485760
+
+// ReadDocument reads an uploaded document and makes sure its content is a PDF file
+func ReadDocument(file io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(file, maxDocumentBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckDocumentSize(int64(len(data))); err != nil {
+		return nil, err
+	}
+	if err := CheckDocumentContent(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func CheckDocumentSize(size int64) error {
+	if size <= 0 {
+		return fmt.Errorf("invalid file")
+	} else if size > maxDocumentBytes {
+		return fmt.Errorf("file size too big")
+	}
+	return nil
+}
+
+// CheckDocumentContent sniffs the first bytes of a document, only PDF is supported
+func CheckDocumentContent(data []byte) error {
+	if http.DetectContentType(data) != "application/pdf" {
+		return fmt.Errorf("format file not supported")
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// MaxImageBytes is the biggest photo accepted, see CheckSize
+	MaxImageBytes     = 2097152
+	maxImageDimension = 8000
+	mediumImageSize   = 800
+	thumbnailSize     = 200
+)
+
+// extension of the object of each photo format supported
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// ProcessedImage holds the re-encoded original photo and its smaller renditions
+type ProcessedImage struct {
+	ContentType string
+	Extension   string
+	Original    []byte
+	Medium      []byte
+	Thumbnail   []byte
+}
+
+// ProcessImage validates an uploaded photo by decoding its bytes, drops any
+// metadata (EXIF included) by re-encoding it and generates the medium and
+// thumbnail renditions
+func ProcessImage(file io.Reader) (ProcessedImage, error) {
+	var processed ProcessedImage
+
+	data, err := ioutil.ReadAll(io.LimitReader(file, MaxImageBytes+1))
+	if err != nil {
+		return processed, err
+	}
+	if err := CheckSize(int64(len(data))); err != nil {
+		return processed, err
+	}
+
+	if err := CheckImageContent(data); err != nil {
+		return processed, err
+	}
+	contentType := http.DetectContentType(data)
+	processed.ContentType = contentType
+	processed.Extension = imageExtensions[contentType]
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return processed, fmt.Errorf("invalid image: %v", err)
+	}
+	if config.Width > maxImageDimension || config.Height > maxImageDimension {
+		return processed, fmt.Errorf("image dimension too big")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return processed, fmt.Errorf("invalid image: %v", err)
+	}
+
+	// the orientation is lost together with the rest of the EXIF data, so apply it to the pixels
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	if processed.Original, err = encodeImage(img, contentType); err != nil {
+		return processed, err
+	}
+	if processed.Medium, err = encodeImage(resizeImage(img, mediumImageSize), contentType); err != nil {
+		return processed, err
+	}
+	if processed.Thumbnail, err = encodeImage(resizeImage(img, thumbnailSize), contentType); err != nil {
+		return processed, err
+	}
+
+	return processed, nil
+}
+
+// CheckImageContent sniffs the first bytes of a photo, only jpeg and png are supported
+func CheckImageContent(data []byte) error {
+	if _, ok := imageExtensions[http.DetectContentType(data)]; !ok {
+		return fmt.Errorf("format file not supported")
+	}
+	return nil
+}
+
+func encodeImage(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeImage scales the image down to fit in a size x size box, keeping its aspect ratio
+func resizeImage(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	if width >= height {
+		height = height * size / width
+		width = size
+	} else {
+		width = width * size / height
+		height = size
+	}
+	if width == 0 {
+		width = 1
+	}
+	if height == 0 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag of a jpeg file, 1 (normal) when missing
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		// start of scan, no more metadata after this
+		if marker == 0xDA || length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[offset : offset+2]))
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8 : entry+10]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// applyOrientation rotates and/or flips the image according to the EXIF orientation value
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// orientations 5-8 swap width and height
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var srcX, srcY int
+			switch orientation {
+			case 2:
+				srcX, srcY = width-1-x, y
+			case 3:
+				srcX, srcY = width-1-x, height-1-y
+			case 4:
+				srcX, srcY = x, height-1-y
+			case 5:
+				srcX, srcY = y, x
+			case 6:
+				srcX, srcY = y, height-1-x
+			case 7:
+				srcX, srcY = width-1-y, height-1-x
+			case 8:
+				srcX, srcY = width-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
+		}
+	}
+	return dst
+}
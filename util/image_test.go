@@ -0,0 +1,190 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestImage is a width x height image where every pixel has its own color
+func newTestImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 50), uint8(y * 50), 100, 255})
+		}
+	}
+	return img
+}
+
+func encodeTestPng(width, height int) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, newTestImage(width, height))
+	return buf.Bytes()
+}
+
+func encodeTestJpeg(width, height int) []byte {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, newTestImage(width, height), nil)
+	return buf.Bytes()
+}
+
+// exifSegment is an APP1 segment with a single orientation entry in the byte order of the tiff header
+func exifSegment(byteOrder string, orientation uint16) []byte {
+	var order binary.ByteOrder = binary.BigEndian
+	if byteOrder == "II" {
+		order = binary.LittleEndian
+	}
+
+	tiff := make([]byte, 26)
+	copy(tiff, byteOrder)
+	order.PutUint16(tiff[2:4], 42)
+	order.PutUint32(tiff[4:8], 8)
+	order.PutUint16(tiff[8:10], 1)
+	order.PutUint16(tiff[10:12], 0x0112)
+	order.PutUint16(tiff[12:14], 3)
+	order.PutUint32(tiff[14:18], 1)
+	order.PutUint16(tiff[18:20], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:4], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// withExif puts the segment right after the start of image marker of a jpeg file
+func withExif(data, segment []byte) []byte {
+	result := append([]byte{}, data[:2]...)
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+// 1. test validating, re-encoding and resizing an uploaded photo
+func TestProcessImage(t *testing.T) {
+	testCases := []struct {
+		name        string
+		data        []byte
+		contentType string
+		extension   string
+		original    image.Point
+		medium      image.Point
+		thumbnail   image.Point
+		err         string
+	}{
+		{"small png", encodeTestPng(40, 30), "image/png", ".png", image.Pt(40, 30), image.Pt(40, 30), image.Pt(40, 30), ""},
+		{"small jpeg", encodeTestJpeg(40, 30), "image/jpeg", ".jpg", image.Pt(40, 30), image.Pt(40, 30), image.Pt(40, 30), ""},
+		{"wide png resized", encodeTestPng(1000, 500), "image/png", ".png", image.Pt(1000, 500), image.Pt(800, 400), image.Pt(200, 100), ""},
+		{"tall jpeg resized", encodeTestJpeg(300, 1200), "image/jpeg", ".jpg", image.Pt(300, 1200), image.Pt(200, 800), image.Pt(50, 200), ""},
+		{"jpeg turned by its orientation", withExif(encodeTestJpeg(40, 30), exifSegment("MM", 6)), "image/jpeg", ".jpg", image.Pt(30, 40), image.Pt(30, 40), image.Pt(30, 40), ""},
+		{"empty file", []byte{}, "", "", image.Point{}, image.Point{}, image.Point{}, "invalid file"},
+		{"file too big", make([]byte, MaxImageBytes+1), "", "", image.Point{}, image.Point{}, image.Point{}, "file size too big"},
+		{"not an image", []byte("%PDF-1.4 not a photo"), "", "", image.Point{}, image.Point{}, image.Point{}, "format file not supported"},
+		{"truncated png", encodeTestPng(40, 30)[:20], "", "", image.Point{}, image.Point{}, image.Point{}, "invalid image"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			processed, err := ProcessImage(bytes.NewReader(tc.data))
+			if tc.err != "" {
+				if assert.Error(t, err) {
+					assert.True(t, strings.HasPrefix(err.Error(), tc.err), err.Error())
+				}
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tc.contentType, processed.ContentType)
+			assert.Equal(t, tc.extension, processed.Extension)
+			renditions := []struct {
+				data []byte
+				size image.Point
+			}{{processed.Original, tc.original}, {processed.Medium, tc.medium}, {processed.Thumbnail, tc.thumbnail}}
+			for _, rendition := range renditions {
+				config, format, err := image.DecodeConfig(bytes.NewReader(rendition.data))
+				if assert.NoError(t, err) {
+					assert.Equal(t, strings.TrimPrefix(tc.contentType, "image/"), format)
+					assert.Equal(t, rendition.size, image.Pt(config.Width, config.Height))
+				}
+			}
+		})
+	}
+}
+
+// 2. test reading the EXIF orientation of a jpeg file, in both byte orders of the tiff header
+func TestJpegOrientation(t *testing.T) {
+	photo := encodeTestJpeg(4, 4)
+	unknownOrder := exifSegment("MM", 6)
+	copy(unknownOrder[10:12], "XX")
+
+	type testCase struct {
+		name        string
+		data        []byte
+		orientation int
+	}
+	testCases := []testCase{
+		{"without exif", photo, 1},
+		{"not a jpeg", encodeTestPng(4, 4), 1},
+		{"too short", []byte{0xFF, 0xD8}, 1},
+		{"orientation out of range", withExif(photo, exifSegment("MM", 9)), 1},
+		{"orientation zero", withExif(photo, exifSegment("II", 0)), 1},
+		{"unknown byte order", withExif(photo, unknownOrder), 1},
+		{"segment longer than the file", withExif(photo, exifSegment("MM", 6))[:20], 1},
+	}
+	for orientation := 1; orientation <= 8; orientation++ {
+		testCases = append(testCases,
+			testCase{"big endian " + strconv.Itoa(orientation), withExif(photo, exifSegment("MM", uint16(orientation))), orientation},
+			testCase{"little endian " + strconv.Itoa(orientation), withExif(photo, exifSegment("II", uint16(orientation))), orientation},
+		)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.orientation, jpegOrientation(tc.data))
+		})
+	}
+}
+
+// 3. test turning a 2 x 3 image by every orientation, checked on the size and on the source pixel that ends up
+// in the top left and top right corners
+func TestApplyOrientation(t *testing.T) {
+	src := newTestImage(2, 3)
+
+	testCases := []struct {
+		orientation int
+		size        image.Point
+		topLeft     image.Point
+		topRight    image.Point
+	}{
+		{0, image.Pt(2, 3), image.Pt(0, 0), image.Pt(1, 0)},
+		{1, image.Pt(2, 3), image.Pt(0, 0), image.Pt(1, 0)},
+		{2, image.Pt(2, 3), image.Pt(1, 0), image.Pt(0, 0)},
+		{3, image.Pt(2, 3), image.Pt(1, 2), image.Pt(0, 2)},
+		{4, image.Pt(2, 3), image.Pt(0, 2), image.Pt(1, 2)},
+		{5, image.Pt(3, 2), image.Pt(0, 0), image.Pt(0, 2)},
+		{6, image.Pt(3, 2), image.Pt(0, 2), image.Pt(0, 0)},
+		{7, image.Pt(3, 2), image.Pt(1, 2), image.Pt(1, 0)},
+		{8, image.Pt(3, 2), image.Pt(1, 0), image.Pt(1, 2)},
+		{9, image.Pt(2, 3), image.Pt(0, 0), image.Pt(1, 0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run("orientation "+strconv.Itoa(tc.orientation), func(t *testing.T) {
+			dst := applyOrientation(src, tc.orientation)
+			bounds := dst.Bounds()
+
+			assert.Equal(t, tc.size, image.Pt(bounds.Dx(), bounds.Dy()))
+			assert.Equal(t, src.At(tc.topLeft.X, tc.topLeft.Y), dst.At(bounds.Min.X, bounds.Min.Y))
+			assert.Equal(t, src.At(tc.topRight.X, tc.topRight.Y), dst.At(bounds.Max.X-1, bounds.Min.Y))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	// A4 sheet of 3 x 8 labels, sizes in mm
+	labelColumns = 3
+	labelRows    = 8
+	labelWidth   = 70.0
+	labelHeight  = 37.0
+	labelMargin  = 3.0
+	qrSize       = 29.0
+)
+
+// AssetLabel is what is printed on a single label
+type AssetLabel struct {
+	Tag  string
+	Name string
+}
+
+// AssetTag is the stable tag of an asset, the value encoded in its QR code and barcode
+func AssetTag(id int) string {
+	return fmt.Sprintf("AST-%06d", id)
+}
+
+// QRCode renders content as a size x size pixels PNG QR code
+func QRCode(content string, size int) ([]byte, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+	return scaleBarcode(code, size, size)
+}
+
+// Code128 renders content as a width x height pixels PNG Code128 barcode
+func Code128(content string, width, height int) ([]byte, error) {
+	code, err := code128.Encode(content)
+	if err != nil {
+		return nil, err
+	}
+	return scaleBarcode(code, width, height)
+}
+
+func scaleBarcode(code barcode.Barcode, width, height int) ([]byte, error) {
+	scaled, err := barcode.Scale(code, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	// barcode images are 16-bit grayscale, which the PDF writer cannot embed
+	gray := image.NewGray(scaled.Bounds())
+	draw.Draw(gray, gray.Bounds(), scaled, scaled.Bounds().Min, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, gray); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LabelSheet builds a printable A4 PDF of labels, each with a QR code, a Code128 barcode, the asset name and its tag
+func LabelSheet(labels []AssetLabel) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetFont("Helvetica", "", 8)
+
+	perPage := labelColumns * labelRows
+	for i, label := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+
+		x := float64(i%labelColumns) * labelWidth
+		y := float64((i%perPage)/labelColumns) * labelHeight
+
+		qrPng, err := QRCode(label.Tag, 256)
+		if err != nil {
+			return nil, err
+		}
+		barcodePng, err := Code128(label.Tag, 400, 100)
+		if err != nil {
+			return nil, err
+		}
+
+		options := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader("qr_"+label.Tag, options, bytes.NewReader(qrPng))
+		pdf.RegisterImageOptionsReader("barcode_"+label.Tag, options, bytes.NewReader(barcodePng))
+
+		pdf.ImageOptions("qr_"+label.Tag, x+labelMargin, y+(labelHeight-qrSize)/2, qrSize, qrSize, false, options, 0, "")
+
+		textX := x + labelMargin*2 + qrSize
+		textWidth := labelWidth - qrSize - labelMargin*3
+		pdf.SetXY(textX, y+labelMargin)
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.CellFormat(textWidth, 4, fitText(pdf, label.Name, textWidth), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(textWidth, 4, label.Tag, "", 2, "L", false, 0, "")
+		pdf.ImageOptions("barcode_"+label.Tag, textX, y+labelHeight-labelMargin-12, textWidth, 12, false, options, 0, "")
+	}
+
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fitText cuts text so it fits in width, the PDF fonts only cover latin-1
+func fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	text = pdf.UnicodeTranslatorFromDescriptor("")(text)
+	for len(text) > 0 && pdf.GetStringWidth(text) > width {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// UserBadge is the code printed on the badge of a user, scanned at the desk
+func UserBadge(id int) string {
+	return fmt.Sprintf("USR-%06d", id)
+}
+
+// ParseUserBadge reads the user id back from a scanned badge
+func ParseUserBadge(badge string) (int, error) {
+	var id int
+	if _, err := fmt.Sscanf(badge, "USR-%d", &id); err != nil || id <= 0 || UserBadge(id) != badge {
+		return 0, fmt.Errorf("invalid badge")
+	}
+	return id, nil
+}
@@ -0,0 +1,128 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// DefaultCursorLimit is the size of a page read by cursor when no limit is asked
+const DefaultCursorLimit = 20
+
+// Page is the page asked by a list, by offset or, for long lists, after the cursor of the last item of the
+// previous page. A zero limit asks for every item
+type Page struct {
+	Limit  int
+	Offset int
+	Keyset bool
+	After  Cursor
+}
+
+// Cursor points at the last item of a page read by cursor by its id, which never changes so an item updated while
+// the list is read is neither skipped nor read twice
+type Cursor struct {
+	Id int
+}
+
+// Meta tells where a page is in its list
+type Meta struct {
+	Total_items int `json:"total_items"`
+	Total_pages int `json:"total_pages"`
+	Page        int `json:"page"`
+	Limit       int `json:"limit"`
+}
+
+// List is a page of a list with its meta
+type List struct {
+	Meta
+	Data interface{} `json:"data"`
+}
+
+// CursorList is a page read by cursor, the next cursor is empty on the last page
+type CursorList struct {
+	Limit       int         `json:"limit"`
+	Next_cursor string      `json:"next_cursor"`
+	Data        interface{} `json:"data"`
+}
+
+// Parse reads the page of the query, limit and offset (or page) for a page by offset. The cursor parameter,
+// even empty for the first page, asks for a page by cursor. Limits and offsets that are not numbers are ignored
+func Parse(query url.Values) (Page, error) {
+	var page Page
+
+	page.Limit, _ = strconv.Atoi(query.Get("limit"))
+	page.Offset, _ = strconv.Atoi(query.Get("offset"))
+	if page.Limit < 0 {
+		page.Limit = 0
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	if number, err := strconv.Atoi(query.Get("page")); err == nil && number > 1 && page.Offset == 0 {
+		page.Offset = (number - 1) * page.Limit
+	}
+
+	if _, ok := query["cursor"]; ok {
+		page.Keyset = true
+		page.Offset = 0
+		if page.Limit == 0 {
+			page.Limit = DefaultCursorLimit
+		}
+		if cursor := query.Get("cursor"); cursor != "" {
+			after, err := DecodeCursor(cursor)
+			if err != nil {
+				return page, err
+			}
+			page.After = after
+		}
+	}
+	return page, nil
+}
+
+// Clause is the limit clause of the page with its binds, empty when every item is asked. A page by cursor
+// reads one more item to know whether there is a next page
+func (p Page) Clause() (string, []interface{}) {
+	if p.Limit == 0 {
+		return "", nil
+	}
+	if p.Keyset {
+		return "limit ?", []interface{}{p.Limit + 1}
+	}
+	return "limit ?, ?", []interface{}{p.Offset, p.Limit}
+}
+
+// NewMeta gets the meta of a page from the count of items of the list, a list without limit is a single page
+func NewMeta(total int, p Page) Meta {
+	meta := Meta{Total_items: total, Page: 1, Limit: p.Limit}
+	if p.Limit == 0 {
+		if total > 0 {
+			meta.Total_pages = 1
+		}
+		return meta
+	}
+
+	meta.Total_pages = (total + p.Limit - 1) / p.Limit
+	meta.Page = p.Offset/p.Limit + 1
+	return meta
+}
+
+// Encode turns the cursor into an opaque string for the next page
+func (c Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(c.Id)))
+}
+
+// DecodeCursor reads a cursor made by Encode
+func DecodeCursor(cursor string) (Cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := strconv.Atoi(string(decoded))
+	if err != nil || id <= 0 {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	return Cursor{Id: id}, nil
+}
@@ -0,0 +1,126 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 1. test reading the page of a query
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected Page
+		err      string
+	}{
+		{"every item", "", Page{}, ""},
+		{"limit and offset", "limit=10&offset=20", Page{Limit: 10, Offset: 20}, ""},
+		{"page", "limit=10&page=3", Page{Limit: 10, Offset: 20}, ""},
+		{"first page", "limit=10&page=1", Page{Limit: 10}, ""},
+		{"offset wins over page", "limit=10&offset=5&page=3", Page{Limit: 10, Offset: 5}, ""},
+		{"page without limit", "page=3", Page{}, ""},
+		{"negative limit and offset", "limit=-5&offset=-10", Page{}, ""},
+		{"negative page", "limit=10&page=-2", Page{Limit: 10}, ""},
+		{"not numbers", "limit=a&offset=b&page=c", Page{}, ""},
+		{"empty cursor", "cursor=", Page{Limit: DefaultCursorLimit, Keyset: true}, ""},
+		{"empty cursor with limit", "cursor=&limit=5&offset=10", Page{Limit: 5, Keyset: true}, ""},
+		{"cursor", "cursor=" + Cursor{Id: 42}.Encode() + "&limit=5", Page{Limit: 5, Keyset: true, After: Cursor{Id: 42}}, ""},
+		{"invalid cursor", "cursor=%21%21", Page{Limit: DefaultCursorLimit, Keyset: true}, "invalid cursor"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tc.query)
+			page, err := Parse(query)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, page)
+		})
+	}
+}
+
+// 2. test the limit clause of a page
+func TestClause(t *testing.T) {
+	testCases := []struct {
+		name   string
+		page   Page
+		clause string
+		bind   []interface{}
+	}{
+		{"every item", Page{}, "", nil},
+		{"by offset", Page{Limit: 10, Offset: 20}, "limit ?, ?", []interface{}{20, 10}},
+		{"by cursor reads one more", Page{Limit: 10, Keyset: true, After: Cursor{Id: 3}}, "limit ?", []interface{}{11}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clause, bind := tc.page.Clause()
+			assert.Equal(t, tc.clause, clause)
+			assert.Equal(t, tc.bind, bind)
+		})
+	}
+}
+
+// 3. test the meta of a page
+func TestNewMeta(t *testing.T) {
+	testCases := []struct {
+		name     string
+		total    int
+		page     Page
+		expected Meta
+	}{
+		{"empty list without limit", 0, Page{}, Meta{Page: 1}},
+		{"list without limit", 7, Page{}, Meta{Total_items: 7, Total_pages: 1, Page: 1}},
+		{"empty list", 0, Page{Limit: 10}, Meta{Page: 1, Limit: 10}},
+		{"exact pages", 20, Page{Limit: 10, Offset: 10}, Meta{Total_items: 20, Total_pages: 2, Page: 2, Limit: 10}},
+		{"last page not full", 21, Page{Limit: 10, Offset: 20}, Meta{Total_items: 21, Total_pages: 3, Page: 3, Limit: 10}},
+		{"offset between pages", 21, Page{Limit: 10, Offset: 5}, Meta{Total_items: 21, Total_pages: 3, Page: 1, Limit: 10}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewMeta(tc.total, tc.page))
+		})
+	}
+}
+
+// 4. test a cursor read back and cursors that were not made by Encode
+func TestDecodeCursor(t *testing.T) {
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	testCases := []struct {
+		name     string
+		cursor   string
+		expected Cursor
+		err      string
+	}{
+		{"round trip", Cursor{Id: 42}.Encode(), Cursor{Id: 42}, ""},
+		{"large id", Cursor{Id: 2147483647}.Encode(), Cursor{Id: 2147483647}, ""},
+		{"not base64", "!!", Cursor{}, "invalid cursor"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("4")), Cursor{}, "invalid cursor"},
+		{"not a number", encode("abc"), Cursor{}, "invalid cursor"},
+		{"zero id", encode("0"), Cursor{}, "invalid cursor"},
+		{"negative id", encode("-3"), Cursor{}, "invalid cursor"},
+		{"empty", encode(""), Cursor{}, "invalid cursor"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tc.cursor)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, cursor)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"sirclo/project/capstone/config"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// StoredObject describes an object found in the bucket
+type StoredObject struct {
+	Size        int64
+	ContentType string
+}
+
+// PresignUploadS3 returns a url the client can PUT the file to directly, without going through the API
+func PresignUploadS3(filename, contentType string, expiry time.Duration) (string, error) {
+	config := config.GetConfig()
+
+	req, _ := s3.New(newS3Session()).PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(config.S3Config.BucketName),
+		Key:         aws.String(filename),
+		ContentType: aws.String(contentType),
+	})
+
+	url, err := req.Presign(expiry)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+	return url, nil
+}
+
+// HeadObjectS3 checks the object exists and returns its size and content type
+func HeadObjectS3(filename string) (StoredObject, error) {
+	config := config.GetConfig()
+	var object StoredObject
+
+	output, err := s3.New(newS3Session()).HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(config.S3Config.BucketName),
+		Key:    aws.String(filename),
+	})
+	if err != nil {
+		log.Println(err)
+		return object, fmt.Errorf("object not found")
+	}
+
+	object.Size = aws.Int64Value(output.ContentLength)
+	object.ContentType = aws.StringValue(output.ContentType)
+	return object, nil
+}
+
+// DownloadFromS3 reads an object, up to maxBytes
+func DownloadFromS3(filename string, maxBytes int64) ([]byte, error) {
+	config := config.GetConfig()
+
+	output, err := s3.New(newS3Session()).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(config.S3Config.BucketName),
+		Key:    aws.String(filename),
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return ioutil.ReadAll(io.LimitReader(output.Body, maxBytes))
+}
+
+// DeleteFromS3 removes an object, deleting an object that does not exist is not an error
+func DeleteFromS3(filename string) error {
+	config := config.GetConfig()
+
+	_, err := s3.New(newS3Session()).DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(config.S3Config.BucketName),
+		Key:    aws.String(filename),
+	})
+	if err != nil {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// ObjectURLS3 returns the (unsigned) url of an object
+func ObjectURLS3(filename string) (string, error) {
+	config := config.GetConfig()
+
+	req, _ := s3.New(newS3Session()).GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(config.S3Config.BucketName),
+		Key:    aws.String(filename),
+	})
+	if err := req.Build(); err != nil {
+		log.Println(err)
+		return "", err
+	}
+	return req.HTTPRequest.URL.String(), nil
+}
@@ -4,11 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"log"
-	"mime/multipart"
 	"sirclo/project/capstone/config"
-	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -16,30 +13,41 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
-func UploadToS3(file *multipart.File, filename string) (string, error) {
-	config := config.GetConfig()
+// PhotoURLs holds the location of every rendition of an uploaded photo
+type PhotoURLs struct {
+	Original  string
+	Medium    string
+	Thumbnail string
+}
 
-	// Destination
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, *file); err != nil {
-		log.Println(err)
-		return "", err
-	}
+func newS3Session() *session.Session {
+	config := config.GetConfig()
 
 	s3Config := &aws.Config{
 		Region:      aws.String(config.S3Config.Region),
 		Credentials: credentials.NewStaticCredentials(config.S3Config.KeyID, config.S3Config.AccessKey, ""),
 	}
 
-	s3Session := session.New(s3Config)
+	if config.S3Config.Endpoint != "" {
+		s3Config.Endpoint = aws.String(config.S3Config.Endpoint)
+	}
+	s3Config.S3ForcePathStyle = aws.Bool(config.S3Config.ForcePathStyle)
+
+	return session.New(s3Config)
+}
+
+func UploadToS3(body []byte, filename, contentType string) (string, error) {
+	config := config.GetConfig()
+
+	s3Session := newS3Session()
 
 	uploader := s3manager.NewUploader(s3Session)
 
 	input := &s3manager.UploadInput{
 		Bucket:      aws.String(config.S3Config.BucketName), // bucket's name
 		Key:         aws.String(filename),                   // files destination location
-		Body:        bytes.NewReader(buf.Bytes()),           // content of the file
-		ContentType: aws.String("image/*"),                  // content type
+		Body:        bytes.NewReader(body),                  // content of the file
+		ContentType: aws.String(contentType),                // content type
 	}
 
 	output, err := uploader.UploadWithContext(context.Background(), input)
@@ -51,19 +59,33 @@ func UploadToS3(file *multipart.File, filename string) (string, error) {
 	return output.Location, nil
 }
 
-func CheckExtension(extension string) error {
-	lowExtension := strings.ToLower(extension)
+// UploadPhotoToS3 uploads the original photo and its renditions next to each other
+func UploadPhotoToS3(image ProcessedImage, filename string) (PhotoURLs, error) {
+	var urls PhotoURLs
+	var err error
 
-	if lowExtension != ".jpg" && extension != ".png" && extension != ".jpeg" {
-		return fmt.Errorf("format file not supported")
+	urls.Original, err = UploadToS3(image.Original, filename+image.Extension, image.ContentType)
+	if err != nil {
+		return urls, err
 	}
-	return nil
+
+	urls.Medium, err = UploadToS3(image.Medium, filename+"_medium"+image.Extension, image.ContentType)
+	if err != nil {
+		return urls, err
+	}
+
+	urls.Thumbnail, err = UploadToS3(image.Thumbnail, filename+"_thumb"+image.Extension, image.ContentType)
+	if err != nil {
+		return urls, err
+	}
+
+	return urls, nil
 }
 
 func CheckSize(size int64) error {
 	if size <= 0 {
 		return fmt.Errorf("invalid file")
-	} else if size > 2097152 {
+	} else if size > MaxImageBytes {
 		return fmt.Errorf("file size too big")
 	}
 	return nil